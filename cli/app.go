@@ -0,0 +1,128 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Command is one node of the command tree. A command either runs an
+// action or groups subcommands.
+type Command struct {
+	// Name is the word the command is invoked by.
+	Name string
+	// Usage describes the arguments, e.g. "get <key>".
+	Usage string
+	// Short is a one-line description shown in the help.
+	Short string
+	// Run executes the command with the remaining arguments; nil for
+	// a pure group of subcommands.
+	Run func(out io.Writer, args []string) error
+	// Subcommands are dispatched by their name before Run is
+	// considered.
+	Subcommands []*Command
+}
+
+// App is the root of the command tree of one management tool.
+type App struct {
+	// Name is the name of the tool, used in the help output.
+	Name string
+	// Out is the destination of the command output; os.Stdout when
+	// nil.
+	Out io.Writer
+	// Commands are the top-level commands.
+	Commands []*Command
+}
+
+// AddCommand appends a top-level command.
+func (app *App) AddCommand(cmd *Command) {
+	app.Commands = append(app.Commands, cmd)
+}
+
+// Run dispatches the arguments (without the program name) to the
+// command tree. No arguments or "help" print the usage of the tool.
+func (app *App) Run(args []string) error {
+	out := app.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	if len(args) == 0 || args[0] == "help" {
+		fmt.Fprintf(out, "Usage: %s <command> [arguments]\n\nCommands:\n", app.Name)
+		printCommands(out, "", app.Commands)
+		return nil
+	}
+	return runCommand(out, app.Name, app.Commands, args)
+}
+
+// runCommand descends the command tree along the arguments and executes
+// the deepest matching command.
+func runCommand(out io.Writer, prefix string, commands []*Command, args []string) error {
+	cmd := findCommand(commands, args[0])
+	if cmd == nil {
+		return fmt.Errorf("unknown command %q, run '%s help' for the available commands", args[0], prefix)
+	}
+	rest := args[1:]
+	if len(rest) > 0 && findCommand(cmd.Subcommands, rest[0]) != nil {
+		return runCommand(out, prefix+" "+cmd.Name, cmd.Subcommands, rest)
+	}
+	if cmd.Run == nil {
+		if len(rest) > 0 {
+			return fmt.Errorf("unknown command %q, run '%s help' for the available commands",
+				rest[0], prefix)
+		}
+		printCommands(out, cmd.Name+" ", cmd.Subcommands)
+		return nil
+	}
+	return cmd.Run(out, rest)
+}
+
+// findCommand returns the command with the given name, nil when there
+// is none.
+func findCommand(commands []*Command, name string) *Command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// printCommands prints the one-line help of the commands, recursing
+// into the subcommands.
+func printCommands(out io.Writer, prefix string, commands []*Command) {
+	sorted := make([]*Command, len(commands))
+	copy(sorted, commands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, cmd := range sorted {
+		if cmd.Run != nil {
+			usage := prefix + cmd.Name
+			if cmd.Usage != "" {
+				usage += " " + cmd.Usage
+			}
+			fmt.Fprintf(out, "  %-34s %s\n", usage, cmd.Short)
+		}
+		if len(cmd.Subcommands) > 0 {
+			printCommands(out, prefix+cmd.Name+" ", cmd.Subcommands)
+		}
+	}
+}
+
+// usageError reports a wrong invocation of the command.
+func usageError(cmd *Command) error {
+	return fmt.Errorf("usage: %s %s", cmd.Name, cmd.Usage)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ligato/cn-infra/datasync/datasynctest"
+	"github.com/onsi/gomega"
+)
+
+// run executes the arguments against the app and returns the output.
+func run(app *App, args ...string) (string, error) {
+	out := &bytes.Buffer{}
+	app.Out = out
+	err := app.Run(args)
+	return out.String(), err
+}
+
+// TestAppHelp checks that the help lists the commands of the tool.
+func TestAppHelp(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	app := NewDefaultApp("agentctl", datasynctest.NewMemBroker(), &RESTClient{})
+
+	help, err := run(app)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(help).To(gomega.ContainSubstring("Usage: agentctl"))
+	gomega.Expect(help).To(gomega.ContainSubstring("kv get <key>"))
+	gomega.Expect(help).To(gomega.ContainSubstring("loglevel <logger> <level>"))
+	gomega.Expect(help).To(gomega.ContainSubstring("resync"))
+
+	_, err = run(app, "bogus")
+	gomega.Expect(err).To(gomega.MatchError(
+		"unknown command \"bogus\", run 'agentctl help' for the available commands"))
+}
+
+// TestKVCommands checks the kv command group against an in-memory
+// broker.
+func TestKVCommands(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	app := &App{Name: "agentctl", Commands: []*Command{NewKVCommand(broker)}}
+
+	_, err := run(app, "kv", "put", "/vnf-agent/vpp1/interface/memif0", "{\"mtu\": 1500}")
+	gomega.Expect(err).To(gomega.BeNil())
+	_, err = run(app, "kv", "put", "/vnf-agent/vpp1/interface/memif1", "{}")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	value, err := run(app, "kv", "get", "/vnf-agent/vpp1/interface/memif0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(value).To(gomega.BeEquivalentTo("{\"mtu\": 1500}\n"))
+
+	keys, err := run(app, "kv", "list", "/vnf-agent/vpp1/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(keys).To(gomega.BeEquivalentTo(
+		"/vnf-agent/vpp1/interface/memif0\n/vnf-agent/vpp1/interface/memif1\n"))
+
+	_, err = run(app, "kv", "del", "/vnf-agent/vpp1/interface/memif0")
+	gomega.Expect(err).To(gomega.BeNil())
+	_, err = run(app, "kv", "get", "/vnf-agent/vpp1/interface/memif0")
+	gomega.Expect(err).To(gomega.MatchError(
+		"the key \"/vnf-agent/vpp1/interface/memif0\" does not exist"))
+
+	// wrong argument counts report the usage
+	_, err = run(app, "kv", "put", "/key")
+	gomega.Expect(err).To(gomega.MatchError("usage: put <key> <value>"))
+	_, err = run(app, "kv", "get")
+	gomega.Expect(err).To(gomega.MatchError("usage: get <key>"))
+}
+
+// TestRESTCommands checks the commands calling the REST endpoints of
+// the agent.
+func TestRESTCommands(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var requests []string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		switch req.URL.Path {
+		case "/readiness":
+			fmt.Fprint(w, "{\"state\": 1}")
+		case "/plugins":
+			fmt.Fprint(w, "[\"etcdv3\", \"kafka\"]")
+		case "/resync":
+			fmt.Fprint(w, "resync started")
+		case "/log/defaultLogger/debug":
+			fmt.Fprint(w, "{}")
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer agent.Close()
+
+	client := &RESTClient{BaseURL: agent.URL, Client: agent.Client()}
+	app := NewDefaultApp("agentctl", datasynctest.NewMemBroker(), client)
+
+	status, err := run(app, "status")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(status).To(gomega.BeEquivalentTo(
+		"readiness: {\"state\": 1}\nplugins: [\"etcdv3\", \"kafka\"]\n"))
+
+	output, err := run(app, "resync")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(output).To(gomega.BeEquivalentTo("resync started\n"))
+
+	_, err = run(app, "loglevel", "defaultLogger", "debug")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(requests).To(gomega.ContainElement("PUT /log/defaultLogger/debug"))
+	gomega.Expect(requests).To(gomega.ContainElement("POST /resync"))
+
+	// a failing endpoint surfaces the status of the agent
+	_, err = run(app, "loglevel", "defaultLogger", "bogus")
+	gomega.Expect(err).To(gomega.HaveOccurred())
+	gomega.Expect(err.Error()).To(gomega.ContainSubstring("404"))
+}
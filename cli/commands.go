@@ -0,0 +1,226 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logmanager"
+	"github.com/ligato/cn-infra/rpc/probe"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// requestTimeout bounds every request to the agent.
+const requestTimeout = 10 * time.Second
+
+// RESTClient talks to the REST endpoints of one agent.
+type RESTClient struct {
+	// BaseURL is the address of the agent, e.g. "http://127.0.0.1:9191".
+	BaseURL string
+	// Client performs the requests; a default client with a timeout
+	// when nil.
+	Client *http.Client
+}
+
+// do performs one request and returns the response body.
+func (client *RESTClient) do(method string, path string) (string, error) {
+	httpClient := client.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(client.BaseURL, "/")+path, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the agent responded with status %s: %s", resp.Status,
+			strings.TrimSpace(string(body)))
+	}
+	return string(body), nil
+}
+
+// NewKVCommand builds the "kv" command group operating on the key-value
+// store of the agent.
+func NewKVCommand(broker keyval.BytesBroker) *Command {
+	list := &Command{Name: "list", Usage: "<prefix>", Short: "List the keys under the prefix"}
+	list.Run = func(out io.Writer, args []string) error {
+		if len(args) != 1 {
+			return usageError(list)
+		}
+		iterator, err := broker.ListKeys(args[0])
+		if err != nil {
+			return err
+		}
+		for {
+			key, _, allReceived := iterator.GetNext()
+			if allReceived {
+				return nil
+			}
+			fmt.Fprintln(out, key)
+		}
+	}
+
+	get := &Command{Name: "get", Usage: "<key>", Short: "Print the value of the key"}
+	get.Run = func(out io.Writer, args []string) error {
+		if len(args) != 1 {
+			return usageError(get)
+		}
+		data, found, _, err := broker.GetValue(args[0])
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("the key %q does not exist", args[0])
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	put := &Command{Name: "put", Usage: "<key> <value>", Short: "Store the value under the key"}
+	put.Run = func(out io.Writer, args []string) error {
+		if len(args) != 2 {
+			return usageError(put)
+		}
+		return broker.Put(args[0], []byte(args[1]))
+	}
+
+	del := &Command{Name: "del", Usage: "<key>", Short: "Delete the key"}
+	del.Run = func(out io.Writer, args []string) error {
+		if len(args) != 1 {
+			return usageError(del)
+		}
+		existed, err := broker.Delete(args[0])
+		if err != nil {
+			return err
+		}
+		if !existed {
+			return fmt.Errorf("the key %q does not exist", args[0])
+		}
+		return nil
+	}
+
+	return &Command{
+		Name:        "kv",
+		Short:       "Operate on the key-value store of the agent",
+		Subcommands: []*Command{list, get, put, del},
+	}
+}
+
+// NewStatusCommand builds the "status" command printing the readiness
+// and the loaded plugins of the agent.
+func NewStatusCommand(client *RESTClient) *Command {
+	status := &Command{Name: "status", Short: "Show the readiness and the loaded plugins"}
+	status.Run = func(out io.Writer, args []string) error {
+		if len(args) != 0 {
+			return usageError(status)
+		}
+		readiness, err := client.do("GET", probe.DefaultReadinessPath)
+		if err != nil {
+			fmt.Fprintln(out, "readiness: NOT_READY")
+			fmt.Fprintln(out, err)
+		} else {
+			fmt.Fprintln(out, "readiness:", strings.TrimSpace(readiness))
+		}
+		plugins, err := client.do("GET", rest.PluginsPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "plugins:", strings.TrimSpace(plugins))
+		return nil
+	}
+	return status
+}
+
+// NewLogLevelCommand builds the "loglevel" command changing the level
+// of one logger through the log manager plugin.
+func NewLogLevelCommand(client *RESTClient) *Command {
+	loglevel := &Command{Name: "loglevel", Usage: "<logger> <level>",
+		Short: "Change the level of the logger"}
+	loglevel.Run = func(out io.Writer, args []string) error {
+		if len(args) != 2 {
+			return usageError(loglevel)
+		}
+		_, err := client.do("PUT", "/log/"+args[0]+"/"+args[1])
+		return err
+	}
+	return loglevel
+}
+
+// NewLoggersCommand builds the "loggers" command listing the registered
+// loggers with their levels.
+func NewLoggersCommand(client *RESTClient) *Command {
+	loggers := &Command{Name: "loggers", Short: "List the registered loggers and their levels"}
+	loggers.Run = func(out io.Writer, args []string) error {
+		if len(args) != 0 {
+			return usageError(loggers)
+		}
+		body, err := client.do("GET", logmanager.ListLoggersPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, strings.TrimSpace(body))
+		return nil
+	}
+	return loggers
+}
+
+// NewResyncCommand builds the "resync" command triggering a full data
+// resynchronization of the agent.
+func NewResyncCommand(client *RESTClient) *Command {
+	resync := &Command{Name: "resync", Short: "Trigger a full data resync of the agent"}
+	resync.Run = func(out io.Writer, args []string) error {
+		if len(args) != 0 {
+			return usageError(resync)
+		}
+		body, err := client.do("POST", "/resync")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, strings.TrimSpace(body))
+		return nil
+	}
+	return resync
+}
+
+// NewDefaultApp composes the standard command set of an agent control
+// tool: the KV commands over the given broker and the status, loggers,
+// loglevel and resync commands over the REST endpoints.
+func NewDefaultApp(name string, broker keyval.BytesBroker, client *RESTClient) *App {
+	return &App{
+		Name: name,
+		Commands: []*Command{
+			NewKVCommand(broker),
+			NewStatusCommand(client),
+			NewLoggersCommand(client),
+			NewLogLevelCommand(client),
+			NewResyncCommand(client),
+		},
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements an embeddable command framework for the
+// management tools of the agents built on this framework (in the style
+// of agentctl). It ships ready-made commands over the key-value store
+// of the agent (list/get/put/delete) and over its REST endpoints
+// (plugin status, resync trigger, log levels), so every agent gets a
+// uniform control tool by composing them into an App.
+package cli
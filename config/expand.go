@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	envReference  = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	fileReference = regexp.MustCompile(`file://(/[^\s"']+)`)
+)
+
+// expandReferences substitutes the references in a configuration file
+// at load time: ${ENV_VAR} is replaced with the value of the environment
+// variable (an unset variable expands to an empty string), file://path
+// with the contents of the file (e.g. an orchestrator-mounted secret)
+// with the trailing newline trimmed, and scheme://path#field with the
+// secret resolved by the registered secret resolver of the scheme.
+func expandReferences(in []byte) ([]byte, error) {
+	in, err := expandSecrets(in)
+	if err != nil {
+		return nil, err
+	}
+	out := envReference.ReplaceAllStringFunc(string(in), func(ref string) string {
+		name := envReference.FindStringSubmatch(ref)[1]
+		return os.Getenv(name)
+	})
+
+	var fileErr error
+	out = fileReference.ReplaceAllStringFunc(out, func(ref string) string {
+		path := fileReference.FindStringSubmatch(ref)[1]
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			if fileErr == nil {
+				fileErr = err
+			}
+			return ref
+		}
+		return strings.TrimRight(string(contents), "\n")
+	})
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/namsral/flag"
+)
+
+// plugin => names of its registered flags; guarded by flagsMu
+var (
+	flagsMu     sync.Mutex
+	pluginFlags = map[string][]string{}
+)
+
+// PluginFlags registers the command-line flags of one plugin. The flags
+// must be registered before Agent.Start, which parses them. Every flag
+// can also be set via the environment variable of the same name in upper
+// case with dashes replaced by underscores.
+type PluginFlags struct {
+	pluginName string
+}
+
+// FlagsFor returns the flag registration API of the plugin.
+func FlagsFor(pluginName string) *PluginFlags {
+	return &PluginFlags{pluginName: pluginName}
+}
+
+// String registers a string flag of the plugin.
+func (flags *PluginFlags) String(name, value, usage string) *string {
+	flags.record(name)
+	return flag.String(name, value, usage)
+}
+
+// Int registers an integer flag of the plugin.
+func (flags *PluginFlags) Int(name string, value int, usage string) *int {
+	flags.record(name)
+	return flag.Int(name, value, usage)
+}
+
+// Bool registers a boolean flag of the plugin.
+func (flags *PluginFlags) Bool(name string, value bool, usage string) *bool {
+	flags.record(name)
+	return flag.Bool(name, value, usage)
+}
+
+// Duration registers a duration flag of the plugin.
+func (flags *PluginFlags) Duration(name string, value time.Duration, usage string) *time.Duration {
+	flags.record(name)
+	return flag.Duration(name, value, usage)
+}
+
+// record remembers that the flag belongs to the plugin.
+func (flags *PluginFlags) record(flagName string) {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	pluginFlags[flags.pluginName] = append(pluginFlags[flags.pluginName], flagName)
+}
+
+// PrintPluginFlags writes the usage of all registered flags grouped by
+// the plugin that owns them.
+func PrintPluginFlags(w io.Writer) {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+
+	plugins := make([]string, 0, len(pluginFlags))
+	for pluginName := range pluginFlags {
+		plugins = append(plugins, pluginName)
+	}
+	sort.Strings(plugins)
+
+	for _, pluginName := range plugins {
+		fmt.Fprintf(w, "%s:\n", pluginName)
+		for _, flagName := range pluginFlags[pluginName] {
+			f := flag.CommandLine.Lookup(flagName)
+			if f == nil {
+				continue
+			}
+			fmt.Fprintf(w, "  -%s=%s: %s\n", f.Name, f.DefValue, f.Usage)
+		}
+	}
+}
+
+// SetPluginUsage installs a usage function printing the flags grouped
+// by plugin.
+func SetPluginUsage() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		PrintPluginFlags(os.Stderr)
+	}
+}
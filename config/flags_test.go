@@ -0,0 +1,39 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestPluginFlags(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	endpoint := FlagsFor("etcd").String("etcd-endpoint", "127.0.0.1:2379", "Etcd endpoint.")
+	timeout := FlagsFor("kafka").Int("kafka-timeout", 5, "Kafka timeout in seconds.")
+	gomega.Expect(*endpoint).To(gomega.Equal("127.0.0.1:2379"))
+	gomega.Expect(*timeout).To(gomega.Equal(5))
+
+	var usage bytes.Buffer
+	PrintPluginFlags(&usage)
+	output := usage.String()
+	gomega.Expect(output).To(gomega.ContainSubstring("etcd:"))
+	gomega.Expect(output).To(gomega.ContainSubstring("-etcd-endpoint=127.0.0.1:2379: Etcd endpoint."))
+	gomega.Expect(output).To(gomega.ContainSubstring("kafka:"))
+	gomega.Expect(output).To(gomega.ContainSubstring("-kafka-timeout=5: Kafka timeout in seconds."))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/namsral/flag"
+)
+
+// Constants of the config directory resolution.
+const (
+	// DirFlag is the name of the command-line flag selecting the directory
+	// with the plugin configuration files.
+	DirFlag = "config-dir"
+	// DirEnvVar is the environment variable overriding the directory.
+	DirEnvVar = "CONFIG_DIR"
+	// DefaultConfigDir is used when neither the flag nor the environment
+	// variable is set.
+	DefaultConfigDir = "."
+	// FileExtension is the extension of the per-plugin configuration files.
+	FileExtension = ".conf"
+)
+
+var configDir = flag.String(DirFlag, DefaultConfigDir,
+	"Directory with the plugin configuration files; can also be set via the 'CONFIG_DIR' env variable.")
+
+// PluginConfig gives one plugin access to its configuration file.
+type PluginConfig interface {
+	// GetValue parses the configuration file into the out value. It
+	// returns found=false without an error when the file does not exist,
+	// so that the plugin can fall back to its defaults.
+	GetValue(out interface{}) (found bool, err error)
+	// GetConfigName returns the resolved path of the configuration file.
+	GetConfigName() string
+}
+
+// ForPlugin returns the configuration loader of the plugin. The plugin
+// configuration is expected in <config-dir>/<plugin-name>.conf, in YAML
+// or JSON format.
+func ForPlugin(pluginName string) PluginConfig {
+	return &pluginConfig{pluginName: pluginName}
+}
+
+// Dir returns the directory with the configuration files, resolved from
+// the command-line flag with the environment variable override.
+func Dir() string {
+	if dir := os.Getenv(DirEnvVar); dir != "" {
+		return dir
+	}
+	if configDir != nil && *configDir != "" {
+		return *configDir
+	}
+	return DefaultConfigDir
+}
+
+// pluginConfig implements PluginConfig for one plugin.
+type pluginConfig struct {
+	pluginName string
+}
+
+// GetValue parses the configuration file into the out value.
+func (config *pluginConfig) GetValue(out interface{}) (bool, error) {
+	b, err := ioutil.ReadFile(config.GetConfigName())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	b, err = expandReferences(b)
+	if err != nil {
+		return true, err
+	}
+	if err := yaml.Unmarshal(b, out); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// GetConfigName returns the resolved path of the configuration file.
+func (config *pluginConfig) GetConfigName() string {
+	return filepath.Join(Dir(), config.pluginName+FileExtension)
+}
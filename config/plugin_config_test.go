@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestGetValue(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "etcd.conf"),
+		[]byte("endpoint: 127.0.0.1:2379\n"), 0644)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	os.Setenv(DirEnvVar, dir)
+	defer os.Unsetenv(DirEnvVar)
+
+	cfg := struct {
+		Endpoint string `json:"endpoint"`
+	}{}
+	found, err := ForPlugin("etcd").GetValue(&cfg)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(cfg.Endpoint).To(gomega.Equal("127.0.0.1:2379"))
+}
+
+func TestMissingConfigFile(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+
+	os.Setenv(DirEnvVar, dir)
+	defer os.Unsetenv(DirEnvVar)
+
+	cfg := struct{}{}
+	found, err := ForPlugin("kafka").GetValue(&cfg)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeFalse())
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// SecretResolver resolves one secret reference of a registered scheme,
+// e.g. the reference vault://secret/etcd#password is resolved with
+// path "secret/etcd" and field "password".
+type SecretResolver func(path string, field string) (string, error)
+
+// secretReference matches scheme://path#field references; only the
+// references of registered schemes are resolved.
+var secretReference = regexp.MustCompile(`([a-z][a-z0-9]*)://([^\s"'#]+)#([A-Za-z0-9_.-]+)`)
+
+var (
+	secretMu        sync.RWMutex
+	secretResolvers = map[string]SecretResolver{}
+)
+
+// RegisterSecretResolver registers the resolver of scheme://path#field
+// references found in the plugin configuration files. A secrets provider
+// plugin (e.g. Vault) is supposed to register itself during Init, before
+// the configs of the other plugins are loaded.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// UnregisterSecretResolver removes the resolver of the scheme.
+func UnregisterSecretResolver(scheme string) {
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	delete(secretResolvers, scheme)
+}
+
+// expandSecrets substitutes the scheme://path#field references of the
+// registered schemes with the resolved secrets. The references of
+// unregistered schemes are left untouched.
+func expandSecrets(in []byte) ([]byte, error) {
+	var resolveErr error
+	out := secretReference.ReplaceAllStringFunc(string(in), func(ref string) string {
+		groups := secretReference.FindStringSubmatch(ref)
+		scheme, path, field := groups[1], groups[2], groups[3]
+
+		secretMu.RLock()
+		resolver, registered := secretResolvers[scheme]
+		secretMu.RUnlock()
+		if !registered {
+			return ref
+		}
+		secret, err := resolver(path, field)
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("failed to resolve the secret reference %s: %v", ref, err)
+			}
+			return ref
+		}
+		return secret
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestSecretExpansion(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	RegisterSecretResolver("test", func(path string, field string) (string, error) {
+		gomega.Expect(path).To(gomega.Equal("secret/db"))
+		gomega.Expect(field).To(gomega.Equal("password"))
+		return "s3cr3t", nil
+	})
+	defer UnregisterSecretResolver("test")
+
+	out, err := expandReferences([]byte("password: test://secret/db#password\n"))
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(string(out)).To(gomega.Equal("password: s3cr3t\n"))
+}
+
+func TestUnregisteredSchemeIsLeftUntouched(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	in := "password: vault://secret/db#password\n"
+	out, err := expandReferences([]byte(in))
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(string(out)).To(gomega.Equal(in))
+}
+
+func TestSecretResolutionFailure(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	RegisterSecretResolver("test", func(path string, field string) (string, error) {
+		return "", errors.New("permission denied")
+	})
+	defer UnregisterSecretResolver("test")
+
+	_, err := expandReferences([]byte("password: test://secret/db#password\n"))
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(err.Error()).To(gomega.ContainSubstring("test://secret/db#password"))
+}
@@ -17,6 +17,8 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ligato/cn-infra/logging"
@@ -27,6 +29,7 @@ import (
 // variables set by the Makefile using ldflags
 var (
 	BuildVersion string
+	CommitHash   string
 	BuildDate    string
 )
 
@@ -35,8 +38,20 @@ type Agent struct {
 	MaxStartupTime time.Duration
 	plugins        []*NamedPlugin
 	log            logging.Logger
+
+	watchdogTimeout time.Duration
+	stuckHandler    StuckPluginHandler
+
+	// startup progress, for the timeout diagnostics and rollback
+	progress sync.Mutex
+	phase    string
+	current  PluginName
+	inited   int
 }
 
+var disabledPluginsFlag = flag.String("disabled-plugins", "",
+	"Comma-separated names of plugins to skip; can also be set via the 'DISABLED_PLUGINS' env variable.")
+
 const (
 	logErrorFmt       = "Plugin %s: init error '%s'"
 	logSuccessFmt     = "Plugin %s: init success"
@@ -47,21 +62,28 @@ const (
 // NewAgent returns a new instance of the Agent with plugins.
 func NewAgent(logger logging.Logger, maxStartup time.Duration, plugins ...*NamedPlugin) *Agent {
 	a := Agent{
-		maxStartup,
-		plugins,
-		logger,
+		MaxStartupTime: maxStartup,
+		plugins:        plugins,
+		log:            logger,
 	}
 	return &a
 }
 
 // Start starts/initializes all plugins on the Start/Stop list.
 func (agent *Agent) Start() error {
-	agent.log.WithFields(logging.Fields{"BuildVersion": BuildVersion, "BuildDate": BuildDate}).Info("Starting the agent...")
+	agent.log.WithFields(logging.Fields{
+		"BuildVersion": BuildVersion, "CommitHash": CommitHash, "BuildDate": BuildDate,
+	}).Info("Starting the agent...")
 
 	doneChannel := make(chan *struct{}, 0)
 	errChannel := make(chan error, 0)
 
 	flag.Parse()
+	printVersionIfRequested()
+	agent.filterDisabledPlugins()
+	if err := agent.sortPlugins(); err != nil {
+		return err
+	}
 
 	go func() {
 		err := agent.initPlugins()
@@ -85,31 +107,154 @@ func (agent *Agent) Start() error {
 		agent.log.Info("All plugins initialized successfully")
 		return nil
 	case <-time.After(agent.MaxStartupTime):
-		return fmt.Errorf("%s", "Some plugins not intialized before timeout")
+		agent.progress.Lock()
+		phase, current, inited := agent.phase, agent.current, agent.inited
+		agent.progress.Unlock()
+
+		// roll back the plugins that finished Init, in reverse order
+		for i := inited - 1; i >= 0; i-- {
+			if err := safeclose.Close(agent.plugins[i].Plugin); err != nil {
+				agent.log.Warn("err closing ", agent.plugins[i].PluginName, " ", err)
+			}
+		}
+		return fmt.Errorf("plugin %s did not finish %s within the startup timeout %v",
+			current, phase, agent.MaxStartupTime)
+	}
+}
+
+// filterDisabledPlugins removes the plugins disabled by the
+// 'disabled-plugins' flag from the agent's plugin list, so that their
+// lifecycle phases are skipped and they stay out of the health
+// aggregation. One binary can this way serve multiple deployment
+// profiles.
+func (agent *Agent) filterDisabledPlugins() {
+	if disabledPluginsFlag == nil || *disabledPluginsFlag == "" {
+		return
+	}
+	disabled := map[PluginName]bool{}
+	for _, name := range strings.Split(*disabledPluginsFlag, ",") {
+		disabled[PluginName(strings.TrimSpace(name))] = true
+	}
+
+	enabled := agent.plugins[:0]
+	for _, plug := range agent.plugins {
+		if disabled[plug.PluginName] {
+			agent.log.Info("Plugin ", plug.PluginName, " disabled by configuration")
+			continue
+		}
+		enabled = append(enabled, plug)
+	}
+	agent.plugins = enabled
+}
+
+// Plugins returns all plugins of the agent in the initialization order.
+func (agent *Agent) Plugins() []*NamedPlugin {
+	plugins := make([]*NamedPlugin, len(agent.plugins))
+	copy(plugins, agent.plugins)
+	return plugins
+}
+
+// LookupPlugin returns the loaded plugin of the given name, or nil when
+// there is none. It allows diagnostic endpoints and inter-plugin
+// integrations to discover optional plugins at runtime.
+func (agent *Agent) LookupPlugin(name PluginName) Plugin {
+	for _, plug := range agent.plugins {
+		if plug.PluginName == name {
+			return plug.Plugin
+		}
 	}
+	return nil
+}
+
+// RestartPlugin closes and re-initializes the named plugin without
+// restarting the whole process, e.g. to recover a connector whose remote
+// endpoint was down during startup.
+func (agent *Agent) RestartPlugin(name PluginName) error {
+	var plug *NamedPlugin
+	for _, p := range agent.plugins {
+		if p.PluginName == name {
+			plug = p
+			break
+		}
+	}
+	if plug == nil {
+		return fmt.Errorf("plugin %s is not loaded", name)
+	}
+
+	agent.log.Info("Restarting plugin ", name)
+	if err := agent.watchPhase(name, "Close", func() error {
+		return safeclose.Close(plug.Plugin)
+	}); err != nil {
+		agent.log.Warn("err closing ", name, " ", err)
+	}
+	if err := agent.watchPhase(name, "Init", plug.Plugin.Init); err != nil {
+		return fmt.Errorf(logErrorFmt, name, err)
+	}
+	if plug2, ok := plug.Plugin.(PostInit); ok {
+		if err := agent.watchPhase(name, "AfterInit", plug2.AfterInit); err != nil {
+			return fmt.Errorf(logPostErrorFmt, name, err)
+		}
+	}
+	agent.log.Info("Plugin ", name, " restarted successfully")
+	return nil
 }
 
-// Stop gracefully shuts down the Agent. It is called when the user
+// ReloadPlugins delivers the reload notification to all plugins that
+// implement the Reloadable interface. Errors do not interrupt the reload
+// of the remaining plugins and are aggregated into the returned error.
+func (agent *Agent) ReloadPlugins() error {
+	agent.log.Info("Reloading plugin configuration...")
+	var errMsgs []string
+	for _, plug := range agent.plugins {
+		reloadable, ok := plug.Plugin.(Reloadable)
+		if !ok {
+			continue
+		}
+		if err := agent.watchPhase(plug.PluginName, "Reload", reloadable.Reload); err != nil {
+			agent.log.Error(fmt.Sprintf("Plugin %s: reload error '%s'", plug.PluginName, err))
+			errMsgs = append(errMsgs, string(plug.PluginName)+": "+err.Error())
+		}
+	}
+	if len(errMsgs) > 0 {
+		return errors.New(strings.Join(errMsgs, "; "))
+	}
+	return nil
+}
+
+// trackProgress records which plugin entered which startup phase, so that
+// the startup timeout can report the stalled plugin and close only the
+// plugins that were already initialized.
+func (agent *Agent) trackProgress(pluginName PluginName, phase string, inited int) {
+	agent.progress.Lock()
+	defer agent.progress.Unlock()
+	agent.phase = phase
+	agent.current = pluginName
+	agent.inited = inited
+}
+
+// Stop gracefully shuts down the Agent. It closes all plugins in the
+// reverse registration order; errors do not interrupt the shutdown and
+// are aggregated into the returned error. It is called when the user
 // interrupts the Agent.
 func (agent *Agent) Stop() error {
 	agent.log.Info("Stopping agent...")
-	errMsg := ""
+	var errMsgs []string
 	for i := len(agent.plugins) - 1; i >= 0; i-- {
 		agent.log.WithField("pluginName", agent.plugins[i].PluginName).Debug("Stopping plugin begin")
-		err := safeclose.Close(agent.plugins[i].Plugin)
+		plug := agent.plugins[i]
+		err := agent.watchPhase(plug.PluginName, "Close", func() error {
+			return safeclose.Close(plug.Plugin)
+		})
 		if err != nil {
-			if len(errMsg) > 0 {
-				errMsg += "; " + string(agent.plugins[i].PluginName)
-				errMsg += ": " + err.Error()
-			}
+			errMsgs = append(errMsgs, string(plug.PluginName)+": "+err.Error())
 		}
 		agent.log.WithField("pluginName", agent.plugins[i].PluginName).Debug("Stopping plugin end ", err)
 	}
 
 	agent.log.Debug("Agent stopped")
 
-	if len(errMsg) > 0 {
-		return errors.New(errMsg)
+	if len(errMsgs) > 0 {
+		return errors.New(strings.Join(errMsgs, "; "))
 	}
 	return nil
 }
@@ -117,11 +262,13 @@ func (agent *Agent) Stop() error {
 // initPlugins calls Init() an all plugins on the Start/Stop list
 func (agent *Agent) initPlugins() error {
 	for i, plug := range agent.plugins {
-		err := plug.Init()
+		plugin := plug.Plugin
+		agent.trackProgress(plug.PluginName, "Init", i)
+		err := agent.watchPhase(plug.PluginName, "Init", plugin.Init)
 		if err != nil {
 			//Stop the plugins that are initialized
 			for j := i; j >= 0; j-- {
-				err := safeclose.Close(agent.plugins[j])
+				err := safeclose.Close(agent.plugins[j].Plugin)
 				if err != nil {
 					agent.log.Warn("err closing ", agent.plugins[j].PluginName, " ", err)
 				}
@@ -141,7 +288,8 @@ func (agent *Agent) handleAfterInit() error {
 	for _, plug := range agent.plugins {
 		if plug2, ok := plug.Plugin.(PostInit); ok {
 			agent.log.Debug("afterInit begin for ", plug.PluginName)
-			err := plug2.AfterInit()
+			agent.trackProgress(plug.PluginName, "AfterInit", len(agent.plugins))
+			err := agent.watchPhase(plug.PluginName, "AfterInit", plug2.AfterInit)
 			if err != nil {
 				agent.Stop()
 
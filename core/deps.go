@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "fmt"
+
+// DependentPlugin is an optional interface for plugins that depend on
+// other plugins. The agent orders the Init phase so that the dependencies
+// of a plugin are initialized before the plugin itself, instead of relying
+// on the manual ordering of the plugin list.
+type DependentPlugin interface {
+	// DependsOn returns the names of the plugins that must be
+	// initialized first.
+	DependsOn() []PluginName
+}
+
+// sortPlugins orders the plugins topologically by their declared
+// dependencies, keeping the list order where no dependency dictates
+// otherwise. It returns an error naming the plugins of a dependency
+// cycle. Dependencies on plugins that are not loaded are ignored, so
+// that a plugin can declare an optional dependency.
+func (agent *Agent) sortPlugins() error {
+	index := map[PluginName]*NamedPlugin{}
+	for _, plug := range agent.plugins {
+		index[plug.PluginName] = plug
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[PluginName]int{}
+	var sorted []*NamedPlugin
+
+	var visit func(plug *NamedPlugin, chain []PluginName) error
+	visit = func(plug *NamedPlugin, chain []PluginName) error {
+		switch state[plug.PluginName] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle between plugins %v", append(chain, plug.PluginName))
+		}
+		state[plug.PluginName] = visiting
+
+		if dependent, ok := plug.Plugin.(DependentPlugin); ok {
+			for _, depName := range dependent.DependsOn() {
+				dep, loaded := index[depName]
+				if !loaded {
+					agent.log.Debug("Plugin ", plug.PluginName, " dependency ", depName, " is not loaded")
+					continue
+				}
+				if err := visit(dep, append(chain, plug.PluginName)); err != nil {
+					return err
+				}
+			}
+		}
+
+		state[plug.PluginName] = done
+		sorted = append(sorted, plug)
+		return nil
+	}
+
+	for _, plug := range agent.plugins {
+		if err := visit(plug, nil); err != nil {
+			return err
+		}
+	}
+	agent.plugins = sorted
+	return nil
+}
@@ -17,6 +17,7 @@ package core
 import (
 	"os"
 	"os/signal"
+	"syscall"
 )
 
 // ExampleAgent struct with public channel used to close it
@@ -41,17 +42,25 @@ func (exampleAgent *ExampleAgent) EventLoopWithInterrupt(agent *Agent) {
 
 	sigChan := make(chan os.Signal, 1)
 
-	signal.Notify(sigChan, os.Interrupt)
-	select {
-	case <-sigChan:
-		agent.log.Info("Interrupt received, returning.")
-		return
-	case <-exampleAgent.CloseChannel:
-		err := agent.Stop()
-		if err != nil {
-			agent.log.Errorf("Agent stop error '%v'", err)
-			os.Exit(1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGHUP)
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				if err := agent.ReloadPlugins(); err != nil {
+					agent.log.Errorf("Agent reload error '%v'", err)
+				}
+				continue
+			}
+			agent.log.Info("Interrupt received, returning.")
+			return
+		case <-exampleAgent.CloseChannel:
+			err := agent.Stop()
+			if err != nil {
+				agent.log.Errorf("Agent stop error '%v'", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
 		}
-		os.Exit(0)
 	}
 }
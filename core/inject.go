@@ -0,0 +1,115 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// injectTag is the struct tag marking the plugin fields to be injected.
+// The tag value names the shared dependency; an empty value matches the
+// dependency by the field type.
+const injectTag = "inject"
+
+// Injector holds shared dependencies (logger factory, config reader,
+// KV broker, HTTP plugin, ...) under names and injects them into tagged
+// fields of plugin structs. It is meant to be used before Agent.Start,
+// so that plugin constructors do not need a positional argument for
+// every dependency.
+type Injector struct {
+	deps map[string]interface{}
+}
+
+// NewInjector creates an empty injector.
+func NewInjector() *Injector {
+	return &Injector{deps: map[string]interface{}{}}
+}
+
+// Provide registers the dependency under the name.
+func (injector *Injector) Provide(name string, dep interface{}) {
+	injector.deps[name] = dep
+}
+
+// Inject fills all fields of the plugin struct tagged with `inject`.
+// A field with a named tag receives the dependency of that name; a field
+// with an empty tag value receives the single provided dependency
+// assignable to the field type. Missing or mismatched dependencies are
+// reported as errors.
+func (injector *Injector) Inject(plugin Plugin) error {
+	value := reflect.ValueOf(plugin)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("plugin %T is not a pointer to struct", plugin)
+	}
+	value = value.Elem()
+
+	for i := 0; i < value.NumField(); i++ {
+		fieldType := value.Type().Field(i)
+		name, tagged := fieldType.Tag.Lookup(injectTag)
+		if !tagged {
+			continue
+		}
+		field := value.Field(i)
+		if !field.CanSet() {
+			return fmt.Errorf("field %s of %T tagged with inject is not settable", fieldType.Name, plugin)
+		}
+
+		dep, err := injector.lookup(name, field.Type())
+		if err != nil {
+			return fmt.Errorf("field %s of %T: %s", fieldType.Name, plugin, err)
+		}
+		field.Set(reflect.ValueOf(dep))
+	}
+	return nil
+}
+
+// InjectAll injects the dependencies into all of the plugins.
+func (injector *Injector) InjectAll(plugins ...*NamedPlugin) error {
+	for _, plugin := range plugins {
+		if err := injector.Inject(plugin.Plugin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup finds the dependency by name, or by the field type when the
+// name is empty.
+func (injector *Injector) lookup(name string, fieldType reflect.Type) (interface{}, error) {
+	if name != "" {
+		dep, found := injector.deps[name]
+		if !found {
+			return nil, fmt.Errorf("dependency '%s' was not provided", name)
+		}
+		if !reflect.TypeOf(dep).AssignableTo(fieldType) {
+			return nil, fmt.Errorf("dependency '%s' of type %T is not assignable to %s", name, dep, fieldType)
+		}
+		return dep, nil
+	}
+
+	var match interface{}
+	for _, dep := range injector.deps {
+		if reflect.TypeOf(dep).AssignableTo(fieldType) {
+			if match != nil {
+				return nil, fmt.Errorf("multiple provided dependencies are assignable to %s, use a named tag", fieldType)
+			}
+			match = dep
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no provided dependency is assignable to %s", fieldType)
+	}
+	return match, nil
+}
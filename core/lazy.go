@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "sync"
+
+// LazyPlugin defers the initialization of the wrapped plugin until the
+// first Instance call. An agent built from a large flavor can wrap the
+// rarely used plugins, so that their Init does not slow down the startup
+// and does not allocate resources that stay unused.
+type LazyPlugin struct {
+	plugin Plugin
+
+	mu          sync.Mutex
+	initialized bool
+	initErr     error
+}
+
+// NewLazyPlugin wraps the plugin for deferred initialization. The wrapper
+// takes the place of the plugin in the agent's plugin list.
+func NewLazyPlugin(plugin Plugin) *LazyPlugin {
+	return &LazyPlugin{plugin: plugin}
+}
+
+// Init does nothing; the initialization of the wrapped plugin is deferred
+// until the first Instance call.
+func (lazy *LazyPlugin) Init() error {
+	return nil
+}
+
+// Instance returns the wrapped plugin, running its Init on the first
+// call. The Init error is remembered and returned by all later calls.
+func (lazy *LazyPlugin) Instance() (Plugin, error) {
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+	if !lazy.initialized {
+		lazy.initialized = true
+		lazy.initErr = lazy.plugin.Init()
+	}
+	if lazy.initErr != nil {
+		return nil, lazy.initErr
+	}
+	return lazy.plugin, nil
+}
+
+// Close closes the wrapped plugin when it was initialized.
+func (lazy *LazyPlugin) Close() error {
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+	if !lazy.initialized || lazy.initErr != nil {
+		return nil
+	}
+	return lazy.plugin.Close()
+}
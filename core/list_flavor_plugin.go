@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "reflect"
+
+// Flavor is a reusable collection of pre-wired plugins. An application
+// embeds a flavor (or combines multiple flavors) instead of hand-assembling
+// the same set of plugins for every agent.
+type Flavor interface {
+	// Plugins returns the plugins of the flavor in the initialization order.
+	Plugins() []*NamedPlugin
+}
+
+// ListPluginsInFlavor uses reflection to collect all plugins from the
+// exported struct fields of the flavor, including the plugins of embedded
+// flavors. The field name becomes the plugin name. It is a helper for
+// implementing the Plugins() method of a flavor.
+func ListPluginsInFlavor(flavor Flavor) []*NamedPlugin {
+	return listPlugins(reflect.ValueOf(flavor))
+}
+
+// listPlugins collects the plugins of the struct the value points to.
+func listPlugins(value reflect.Value) []*NamedPlugin {
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var plugins []*NamedPlugin
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if !field.CanInterface() {
+			continue
+		}
+		fieldType := value.Type().Field(i)
+
+		// plugins of an embedded flavor keep their own names
+		if fieldType.Anonymous {
+			if field.Kind() != reflect.Ptr && field.CanAddr() {
+				field = field.Addr()
+			}
+			if embedded, ok := field.Interface().(Flavor); ok {
+				plugins = append(plugins, embedded.Plugins()...)
+				continue
+			}
+		}
+
+		if field.Kind() != reflect.Ptr && field.Kind() != reflect.Interface {
+			if !field.CanAddr() {
+				continue
+			}
+			field = field.Addr()
+		}
+		if field.IsNil() {
+			continue
+		}
+		if plugin, ok := field.Interface().(Plugin); ok {
+			plugins = append(plugins, &NamedPlugin{
+				PluginName: PluginName(fieldType.Name),
+				Plugin:     plugin,
+			})
+		}
+	}
+	return plugins
+}
@@ -31,7 +31,10 @@ func (np *NamedPlugin) String() string {
 	return string(np.PluginName)
 }
 
-// Plugin interface defines plugin's basic life-cycle methods.
+// Plugin interface defines plugin's basic life-cycle methods. The agent
+// drives all registered plugins through the phases in the registration
+// order: Init of all plugins first, then AfterInit of the plugins that
+// implement PostInit, and Close in the reverse order during shutdown.
 type Plugin interface {
 	// Init is called in the agent startup phase.
 	Init() error
@@ -44,3 +47,11 @@ type PostInit interface {
 	// AfterInit is called once Init() of all plugins have returned.
 	AfterInit() error
 }
+
+// Reloadable is an optional interface for plugins that can re-read their
+// configuration at runtime. Reload of all opted-in plugins is triggered
+// by the SIGHUP signal.
+type Reloadable interface {
+	// Reload re-reads the configuration of the plugin.
+	Reload() error
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/net/context"
+)
+
+// Run starts the agent, blocks until the context is canceled or an
+// interrupt/termination signal arrives, and then gracefully stops the
+// agent. SIGHUP triggers a configuration reload without stopping. It
+// replaces hand-written signal loops in the embedding applications.
+func (agent *Agent) Run(ctx context.Context) error {
+	if err := agent.Start(); err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				if err := agent.ReloadPlugins(); err != nil {
+					agent.log.Errorf("Agent reload error '%v'", err)
+				}
+				continue
+			}
+			agent.log.Info("Signal ", sig, " received, stopping.")
+			return agent.Stop()
+		case <-ctx.Done():
+			agent.log.Info("Context canceled, stopping.")
+			return agent.Stop()
+		}
+	}
+}
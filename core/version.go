@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/namsral/flag"
+)
+
+var versionFlag = flag.Bool("version", false, "Print the version of the agent and exit.")
+
+// VersionInfo carries the build information of the agent, settable by
+// the Makefile using ldflags.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git-commit"`
+	BuildDate string `json:"build-date"`
+}
+
+// GetVersionInfo returns the build information of the agent.
+func GetVersionInfo() VersionInfo {
+	return VersionInfo{
+		Version:   BuildVersion,
+		GitCommit: CommitHash,
+		BuildDate: BuildDate,
+	}
+}
+
+// printVersionIfRequested handles the --version flag: it prints the build
+// information and exits. It must be called after the flags are parsed.
+func printVersionIfRequested() {
+	if versionFlag == nil || !*versionFlag {
+		return
+	}
+	info := GetVersionInfo()
+	fmt.Printf("%s, commit %s, built %s\n", info.Version, info.GitCommit, info.BuildDate)
+	os.Exit(0)
+}
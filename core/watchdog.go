@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// StuckPluginHandler is notified when a plugin exceeds the watchdog
+// timeout in a lifecycle phase, e.g. to mark the plugin unhealthy
+// in the status-check subsystem.
+type StuckPluginHandler func(pluginName PluginName, phase string, err error)
+
+// SetWatchdog bounds how long each plugin may spend in Init, AfterInit
+// and Close. When a plugin exceeds the timeout, a goroutine dump is
+// logged, the handler (optional) is notified and the phase fails instead
+// of silently hanging the whole agent. It must be called before Start.
+func (agent *Agent) SetWatchdog(timeout time.Duration, handler StuckPluginHandler) {
+	agent.watchdogTimeout = timeout
+	agent.stuckHandler = handler
+}
+
+// watchPhase runs one lifecycle phase of a plugin, bounded by the
+// watchdog timeout when one is configured. The goroutine of a stuck
+// phase is left behind, the watchdog merely prevents it from blocking
+// the rest of the lifecycle.
+func (agent *Agent) watchPhase(pluginName PluginName, phase string, fn func() error) error {
+	if agent.watchdogTimeout == 0 {
+		return fn()
+	}
+
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- fn()
+	}()
+
+	select {
+	case err := <-errChannel:
+		return err
+	case <-time.After(agent.watchdogTimeout):
+		err := fmt.Errorf("plugin %s did not finish %s within %v",
+			pluginName, phase, agent.watchdogTimeout)
+		agent.log.Error(err.Error(), "; goroutine dump:\n", goroutineDump())
+		if agent.stuckHandler != nil {
+			agent.stuckHandler(pluginName, phase, err)
+		}
+		return err
+	}
+}
+
+// goroutineDump returns the stack traces of all current goroutines.
+func goroutineDump() string {
+	buf := make([]byte, 1<<20)
+	return string(buf[:runtime.Stack(buf, true)])
+}
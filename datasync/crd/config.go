@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the CRD transport.
+type Config struct {
+	// Group of the watched custom resources, e.g. "ligato.io".
+	Group string `json:"group"`
+	// Version of the watched custom resources, e.g. "v1".
+	Version string `json:"version"`
+	// Resources are the plural resource names to watch.
+	Resources []string `json:"resources"`
+	// Namespace restricts the watch to one namespace; empty watches
+	// all namespaces.
+	Namespace string `json:"namespace"`
+	// KubeConfig is the path of the kubeconfig file; empty uses the
+	// in-cluster configuration.
+	KubeConfig string `json:"kube-config"`
+}
+
+// ConfigFromFile loads the CRD transport configuration from the
+// specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
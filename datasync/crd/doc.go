@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crd implements a datasync transport fed by Kubernetes custom
+// resources. The watched resources are converted into the change and
+// resync events of the agent, so k8s-native controllers can drive the
+// agent without an intermediate etcd write path. The connection to the
+// Kubernetes API server is an extension point; the embedding application
+// registers an informer factory backed by client-go.
+package crd
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+// EventType distinguishes the kinds of resource events.
+type EventType string
+
+const (
+	// Added is delivered when a custom resource is created.
+	Added EventType = "Added"
+	// Modified is delivered when a custom resource is updated.
+	Modified EventType = "Modified"
+	// Deleted is delivered when a custom resource is removed.
+	Deleted EventType = "Deleted"
+	// Synced is delivered when the informer cache has caught up with
+	// the API server, both initially and after a reconnect. It carries
+	// no resource and instructs the agent to resync.
+	Synced EventType = "Synced"
+)
+
+// ResourceEvent is one event of a watched custom resource.
+type ResourceEvent struct {
+	// Type of the event.
+	Type EventType
+	// Resource is the plural resource name the event belongs to.
+	Resource string
+	// Namespace of the resource; empty for cluster-scoped resources.
+	Namespace string
+	// Name of the resource.
+	Name string
+	// Spec is the JSON-encoded spec of the resource; nil for Deleted
+	// and Synced events.
+	Spec []byte
+	// ResourceVersion orders the events of one resource.
+	ResourceVersion int64
+}
+
+// Informer watches the custom resources and delivers their events. It
+// is the extension point between the transport and client-go.
+type Informer interface {
+	// Start begins the delivery of the resource events to the channel.
+	Start(events chan<- ResourceEvent) error
+	// Close stops the delivery and releases the resources.
+	Close() error
+}
+
+// InformerFactory creates the informer described by the configuration.
+// The embedding application is supposed to assign a factory backed by
+// client-go before the plugin is initialized.
+var InformerFactory func(config *Config) (Informer, error)
@@ -0,0 +1,217 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/datasync"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// ResyncTrigger is the part of the resync orchestrator API used by the
+// transport to trigger a resync when the informer cache is (re)synced.
+type ResyncTrigger interface {
+	// DoResync triggers the resync of all registered plugins.
+	DoResync()
+}
+
+// Plugin converts the events of the watched custom resources into the
+// change and resync events of the agent. It implements
+// keyval.ProtoWatcher; subscribe through Watcher.
+type Plugin struct {
+	log      logging.Logger
+	config   *Config
+	informer Informer
+	resync   ResyncTrigger
+
+	mu            sync.Mutex
+	subscriptions []subscription
+
+	events    chan ResourceEvent
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// subscription is one watch subscription of a plugin.
+type subscription struct {
+	respChan chan keyval.ProtoWatchResp
+	prefix   string
+}
+
+// NewPlugin creates a new instance of the CRD transport. The informer is
+// created by the registered InformerFactory during Init. Nil resync
+// disables the resync triggering.
+func NewPlugin(log logging.Logger, config *Config, resync ResyncTrigger) *Plugin {
+	return &Plugin{
+		log:       log,
+		config:    config,
+		resync:    resync,
+		events:    make(chan ResourceEvent),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// NewPluginUsingInformer creates a new instance of the CRD transport on
+// top of an already created informer.
+func NewPluginUsingInformer(log logging.Logger, informer Informer, resync ResyncTrigger) *Plugin {
+	return &Plugin{
+		log:       log,
+		informer:  informer,
+		resync:    resync,
+		events:    make(chan ResourceEvent),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Init starts the informer and the conversion of its events.
+func (plugin *Plugin) Init() error {
+	if plugin.informer == nil {
+		if InformerFactory == nil {
+			return errors.New("no informer was injected and no InformerFactory is registered")
+		}
+		informer, err := InformerFactory(plugin.config)
+		if err != nil {
+			return err
+		}
+		plugin.informer = informer
+	}
+	if err := plugin.informer.Start(plugin.events); err != nil {
+		return err
+	}
+	plugin.wg.Add(1)
+	go plugin.processEvents()
+	return nil
+}
+
+// Close stops the informer and the event processing.
+func (plugin *Plugin) Close() error {
+	err := plugin.informer.Close()
+	close(plugin.closeChan)
+	plugin.wg.Wait()
+	return err
+}
+
+// Watcher returns the watcher the plugins subscribe with.
+func (plugin *Plugin) Watcher() datasync.Watcher {
+	return datasync.NewWatcher(plugin)
+}
+
+// Watch implements keyval.ProtoWatcher; it records the subscriptions of
+// the plugins. It is not supposed to be called directly, subscribe
+// through Watcher instead.
+func (plugin *Plugin) Watch(respChan chan keyval.ProtoWatchResp, keys ...string) error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	for _, prefix := range keys {
+		plugin.subscriptions = append(plugin.subscriptions, subscription{respChan: respChan, prefix: prefix})
+	}
+	return nil
+}
+
+// processEvents converts the resource events into change events and
+// resync triggers until the plugin is closed.
+func (plugin *Plugin) processEvents() {
+	defer plugin.wg.Done()
+	for {
+		select {
+		case <-plugin.closeChan:
+			return
+		case event := <-plugin.events:
+			plugin.processEvent(event)
+		}
+	}
+}
+
+// processEvent converts one resource event.
+func (plugin *Plugin) processEvent(event ResourceEvent) {
+	if event.Type == Synced {
+		if plugin.resync != nil {
+			plugin.log.Debug("Informer cache synced, triggering resync")
+			plugin.resync.DoResync()
+		}
+		return
+	}
+
+	resp := &crdWatchResp{
+		key:      resourceKey(event),
+		spec:     event.Spec,
+		del:      event.Type == Deleted,
+		revision: event.ResourceVersion,
+	}
+
+	plugin.mu.Lock()
+	var receivers []chan keyval.ProtoWatchResp
+	for _, sub := range plugin.subscriptions {
+		if strings.HasPrefix(resp.key, sub.prefix) {
+			receivers = append(receivers, sub.respChan)
+		}
+	}
+	plugin.mu.Unlock()
+
+	for _, respChan := range receivers {
+		select {
+		case respChan <- resp:
+		case <-plugin.closeChan:
+			return
+		}
+	}
+}
+
+// resourceKey composes the key of a custom resource; cluster-scoped
+// resources have no namespace segment.
+func resourceKey(event ResourceEvent) string {
+	if event.Namespace == "" {
+		return event.Resource + "/" + event.Name
+	}
+	return event.Resource + "/" + event.Namespace + "/" + event.Name
+}
+
+// crdWatchResp is the change event of one custom resource.
+type crdWatchResp struct {
+	key      string
+	spec     []byte
+	del      bool
+	revision int64
+}
+
+// GetKey returns the key of the resource the change is about.
+func (resp *crdWatchResp) GetKey() string {
+	return resp.key
+}
+
+// GetValue unmarshals the JSON-encoded spec of the resource into msg.
+func (resp *crdWatchResp) GetValue(msg proto.Message) error {
+	return json.Unmarshal(resp.spec, msg)
+}
+
+// GetChangeType distinguishes a put from a delete.
+func (resp *crdWatchResp) GetChangeType() db.PutDel {
+	if resp.del {
+		return db.Delete
+	}
+	return db.Put
+}
+
+// GetRevision returns the resource version of the change.
+func (resp *crdWatchResp) GetRevision() int64 {
+	return resp.revision
+}
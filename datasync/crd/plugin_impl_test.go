@@ -0,0 +1,120 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeInformer delivers manually emitted events.
+type fakeInformer struct {
+	events chan<- ResourceEvent
+	closed bool
+}
+
+func (informer *fakeInformer) Start(events chan<- ResourceEvent) error {
+	informer.events = events
+	return nil
+}
+
+func (informer *fakeInformer) Close() error {
+	informer.closed = true
+	return nil
+}
+
+// resyncRecorder counts the triggered resyncs.
+type resyncRecorder struct {
+	triggered chan struct{}
+}
+
+func (recorder *resyncRecorder) DoResync() {
+	recorder.triggered <- struct{}{}
+}
+
+// ifSpec is a minimal proto message matching the JSON spec of the test
+// resources.
+type ifSpec struct {
+	State string `json:"state"`
+}
+
+func (m *ifSpec) Reset()         { *m = ifSpec{} }
+func (m *ifSpec) String() string { return proto.CompactTextString(m) }
+func (*ifSpec) ProtoMessage()    {}
+
+func TestResourceEventsToChanges(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	informer := &fakeInformer{}
+	plugin := NewPluginUsingInformer(logrus.StandardLogger(), informer, nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	respChan := make(chan keyval.ProtoWatchResp, 10)
+	_, err := plugin.Watcher().WatchData("testPlugin", respChan, "interfaces/")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	informer.events <- ResourceEvent{
+		Type:            Added,
+		Resource:        "interfaces",
+		Namespace:       "default",
+		Name:            "gbe0",
+		Spec:            []byte(`{"state":"up"}`),
+		ResourceVersion: 7,
+	}
+	var resp keyval.ProtoWatchResp
+	gomega.Eventually(respChan).Should(gomega.Receive(&resp))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("interfaces/default/gbe0"))
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Put))
+	gomega.Expect(resp.GetRevision()).To(gomega.BeEquivalentTo(7))
+	spec := &ifSpec{}
+	gomega.Expect(resp.GetValue(spec)).To(gomega.BeNil())
+	gomega.Expect(spec.State).To(gomega.Equal("up"))
+
+	// resources outside of the subscribed prefix are filtered out
+	informer.events <- ResourceEvent{Type: Added, Resource: "routes", Name: "r1", Spec: []byte(`{}`)}
+	informer.events <- ResourceEvent{Type: Deleted, Resource: "interfaces", Namespace: "default", Name: "gbe0"}
+
+	gomega.Eventually(respChan).Should(gomega.Receive(&resp))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("interfaces/default/gbe0"))
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Delete))
+}
+
+func TestSyncedTriggersResync(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	informer := &fakeInformer{}
+	recorder := &resyncRecorder{triggered: make(chan struct{}, 1)}
+	plugin := NewPluginUsingInformer(logrus.StandardLogger(), informer, recorder)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	informer.events <- ResourceEvent{Type: Synced}
+	gomega.Eventually(recorder.triggered).Should(gomega.Receive())
+
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+	gomega.Expect(informer.closed).To(gomega.BeTrue())
+}
+
+func TestInitWithoutInformerFactory(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{Group: "ligato.io"}, nil)
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasynctest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/datasync"
+	"github.com/ligato/cn-infra/datasync/resync"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// stepTimeout bounds the wait for the plugin under test to consume an
+// event or to acknowledge a resync.
+const stepTimeout = 5 * time.Second
+
+// Step is one entry of a scripted scenario. Use the ResyncStep, PutStep
+// and DelStep constructors to build the scenarios.
+type Step struct {
+	// Resync triggers a resync event and waits for its acknowledgement.
+	Resync bool
+	// Key, Value and Del describe one change event.
+	Key   string
+	Value proto.Message
+	Del   bool
+}
+
+// ResyncStep creates a step that triggers a resync event.
+func ResyncStep() Step {
+	return Step{Resync: true}
+}
+
+// PutStep creates a step that delivers a change event with the value
+// stored under the key.
+func PutStep(key string, value proto.Message) Step {
+	return Step{Key: key, Value: value}
+}
+
+// DelStep creates a step that delivers a change event with the key
+// removed.
+func DelStep(key string) Step {
+	return Step{Key: key, Del: true}
+}
+
+// Harness feeds scripted scenarios into a plugin under test. The plugin
+// is wired to the harness through Watcher and Registration, and its
+// outputs are recorded by the broker returned by Outputs.
+type Harness struct {
+	mu            sync.Mutex
+	subscriptions []subscription
+	registration  *scriptedRegistration
+	ackCount      int
+	revision      int64
+	outputs       *MemBroker
+}
+
+// subscription is one keyval watch subscription of the plugin.
+type subscription struct {
+	respChan chan keyval.ProtoWatchResp
+	prefix   string
+}
+
+// NewHarness creates an empty harness.
+func NewHarness() *Harness {
+	return &Harness{outputs: NewMemBroker()}
+}
+
+// Watcher returns the watcher the plugin under test subscribes with.
+func (harness *Harness) Watcher() datasync.Watcher {
+	return datasync.NewWatcher(harness)
+}
+
+// Watch implements keyval.ProtoWatcher; it records the subscriptions of
+// the plugin. It is not supposed to be called directly, subscribe
+// through Watcher instead.
+func (harness *Harness) Watch(respChan chan keyval.ProtoWatchResp, keys ...string) error {
+	harness.mu.Lock()
+	defer harness.mu.Unlock()
+	for _, prefix := range keys {
+		harness.subscriptions = append(harness.subscriptions, subscription{respChan: respChan, prefix: prefix})
+	}
+	return nil
+}
+
+// Registration returns the resync registration of the plugin under test.
+func (harness *Harness) Registration(name string) resync.Registration {
+	harness.mu.Lock()
+	defer harness.mu.Unlock()
+	if harness.registration == nil {
+		harness.registration = &scriptedRegistration{
+			name:       name,
+			statusChan: make(chan resync.StatusEvent, 1),
+		}
+	}
+	return harness.registration
+}
+
+// Outputs returns the broker recording what the plugin publishes.
+func (harness *Harness) Outputs() *MemBroker {
+	return harness.outputs
+}
+
+// ResyncAcks returns the number of acknowledged resync events.
+func (harness *Harness) ResyncAcks() int {
+	harness.mu.Lock()
+	defer harness.mu.Unlock()
+	return harness.ackCount
+}
+
+// Run feeds the steps of the scenario into the plugin, in order. A resync
+// step blocks until the plugin acknowledges the event; a change step
+// blocks until all subscribed channels consume it.
+func (harness *Harness) Run(steps []Step) error {
+	for i, step := range steps {
+		var err error
+		if step.Resync {
+			err = harness.runResync()
+		} else {
+			err = harness.runChange(step)
+		}
+		if err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// runResync delivers one resync event and waits for its acknowledgement.
+func (harness *Harness) runResync() error {
+	harness.mu.Lock()
+	reg := harness.registration
+	harness.mu.Unlock()
+	if reg == nil {
+		return fmt.Errorf("the plugin has no resync registration")
+	}
+
+	event := &scriptedStatusEvent{ackChan: make(chan struct{})}
+	select {
+	case reg.statusChan <- event:
+	case <-time.After(stepTimeout):
+		return fmt.Errorf("the plugin did not consume the resync event")
+	}
+	select {
+	case <-event.ackChan:
+	case <-time.After(stepTimeout):
+		return fmt.Errorf("the plugin did not acknowledge the resync event")
+	}
+
+	harness.mu.Lock()
+	harness.ackCount++
+	harness.mu.Unlock()
+	return nil
+}
+
+// runChange delivers one change event to the matching subscriptions.
+func (harness *Harness) runChange(step Step) error {
+	resp := &scriptedWatchResp{key: step.Key, del: step.Del}
+	if !step.Del && step.Value != nil {
+		data, err := proto.Marshal(step.Value)
+		if err != nil {
+			return err
+		}
+		resp.data = data
+	}
+
+	harness.mu.Lock()
+	harness.revision++
+	resp.revision = harness.revision
+	var receivers []chan keyval.ProtoWatchResp
+	for _, sub := range harness.subscriptions {
+		if strings.HasPrefix(step.Key, sub.prefix) {
+			receivers = append(receivers, sub.respChan)
+		}
+	}
+	harness.mu.Unlock()
+
+	for _, respChan := range receivers {
+		select {
+		case respChan <- resp:
+		case <-time.After(stepTimeout):
+			return fmt.Errorf("the plugin did not consume the change of key %q", step.Key)
+		}
+	}
+	return nil
+}
+
+// scriptedRegistration implements resync.Registration.
+type scriptedRegistration struct {
+	name       string
+	statusChan chan resync.StatusEvent
+}
+
+// StatusChan delivers the scripted resync events.
+func (reg *scriptedRegistration) StatusChan() chan resync.StatusEvent {
+	return reg.statusChan
+}
+
+// String identifies the registration.
+func (reg *scriptedRegistration) String() string {
+	return reg.name
+}
+
+// scriptedStatusEvent implements resync.StatusEvent.
+type scriptedStatusEvent struct {
+	ackChan chan struct{}
+	ackOnce sync.Once
+}
+
+// ResyncStatus returns the status of the resync.
+func (event *scriptedStatusEvent) ResyncStatus() resync.Status {
+	return resync.Started
+}
+
+// Ack confirms that the plugin finished its part of the resync.
+func (event *scriptedStatusEvent) Ack() {
+	event.ackOnce.Do(func() {
+		close(event.ackChan)
+	})
+}
+
+// scriptedWatchResp implements keyval.ProtoWatchResp.
+type scriptedWatchResp struct {
+	key      string
+	data     []byte
+	del      bool
+	revision int64
+}
+
+// GetKey returns the key the change is about.
+func (resp *scriptedWatchResp) GetKey() string {
+	return resp.key
+}
+
+// GetValue unmarshals the changed value into msg.
+func (resp *scriptedWatchResp) GetValue(msg proto.Message) error {
+	return proto.Unmarshal(resp.data, msg)
+}
+
+// GetChangeType distinguishes a put from a delete.
+func (resp *scriptedWatchResp) GetChangeType() db.PutDel {
+	if resp.del {
+		return db.Delete
+	}
+	return db.Put
+}
+
+// GetRevision returns the revision assigned to the change by the harness.
+func (resp *scriptedWatchResp) GetRevision() int64 {
+	return resp.revision
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasynctest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/onsi/gomega"
+)
+
+// ifConfig is a minimal proto message used as the change event payload.
+type ifConfig struct {
+	State string `protobuf:"bytes,1,opt,name=state" json:"state,omitempty"`
+}
+
+func (m *ifConfig) Reset()         { *m = ifConfig{} }
+func (m *ifConfig) String() string { return proto.CompactTextString(m) }
+func (*ifConfig) ProtoMessage()    {}
+
+// TestScriptedScenarios runs table-driven scenarios against a trivial
+// reconciler that mirrors the watched interface configuration into the
+// state prefix.
+func TestScriptedScenarios(t *testing.T) {
+	cases := []struct {
+		name     string
+		steps    []Step
+		wantAcks int
+		wantLog  []Record
+	}{
+		{
+			name: "change only",
+			steps: []Step{
+				PutStep("/config/if/gbe0", &ifConfig{State: "up"}),
+				DelStep("/config/if/gbe0"),
+			},
+			wantLog: []Record{
+				{Key: "/state/if/gbe0", Data: []byte("up")},
+				{Key: "/state/if/gbe0", Del: true},
+			},
+		},
+		{
+			name: "resync between changes",
+			steps: []Step{
+				PutStep("/config/if/gbe0", &ifConfig{State: "up"}),
+				ResyncStep(),
+				PutStep("/config/if/gbe1", &ifConfig{State: "down"}),
+			},
+			wantAcks: 1,
+			wantLog: []Record{
+				{Key: "/state/if/gbe0", Data: []byte("up")},
+				{Key: "/state/if/gbe1", Data: []byte("down")},
+			},
+		},
+		{
+			name: "changes outside of the watched prefix are ignored",
+			steps: []Step{
+				PutStep("/config/bd/bd1", &ifConfig{State: "up"}),
+				PutStep("/config/if/gbe0", &ifConfig{State: "up"}),
+			},
+			wantLog: []Record{
+				{Key: "/state/if/gbe0", Data: []byte("up")},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			gomega.RegisterTestingT(t)
+
+			harness := NewHarness()
+			respChan := make(chan keyval.ProtoWatchResp)
+			_, err := harness.Watcher().WatchData("echoPlugin", respChan, "/config/if/")
+			gomega.Expect(err).To(gomega.BeNil())
+			registration := harness.Registration("echoPlugin")
+
+			quit := make(chan struct{})
+			defer close(quit)
+			go func() {
+				outputs := harness.Outputs()
+				for {
+					select {
+					case <-quit:
+						return
+					case event := <-registration.StatusChan():
+						event.Ack()
+					case resp := <-respChan:
+						stateKey := "/state/if/" + strings.TrimPrefix(resp.GetKey(), "/config/if/")
+						if resp.GetChangeType() == db.Delete {
+							outputs.Delete(stateKey)
+							continue
+						}
+						config := &ifConfig{}
+						if err := resp.GetValue(config); err != nil {
+							continue
+						}
+						outputs.Put(stateKey, []byte(config.State))
+					}
+				}
+			}()
+
+			gomega.Expect(harness.Run(testCase.steps)).To(gomega.BeNil())
+			gomega.Expect(harness.ResyncAcks()).To(gomega.Equal(testCase.wantAcks))
+			// the plugin finishes the write of the last consumed event asynchronously
+			gomega.Eventually(func() []Record {
+				return harness.Outputs().Records()
+			}).Should(gomega.Equal(testCase.wantLog))
+		})
+	}
+}
+
+func TestUnconsumedResyncIsReported(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	harness := NewHarness()
+	err := harness.Run([]Step{ResyncStep()})
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(err.Error()).To(gomega.ContainSubstring("no resync registration"))
+}
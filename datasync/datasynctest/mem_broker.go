@@ -0,0 +1,218 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasynctest
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// Record is one write the plugin under test performed against the
+// output broker.
+type Record struct {
+	// Key the write is about.
+	Key string
+	// Data is the stored value; nil for a delete.
+	Data []byte
+	// Del distinguishes a delete from a put.
+	Del bool
+}
+
+// MemBroker is an in-memory implementation of keyval.BytesBroker that
+// keeps an ordered log of the writes, so the tests can assert both the
+// final state and the sequence of the published outputs.
+type MemBroker struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	records []Record
+}
+
+// NewMemBroker creates an empty broker.
+func NewMemBroker() *MemBroker {
+	return &MemBroker{data: map[string][]byte{}}
+}
+
+// Records returns the writes in the order they were performed.
+func (broker *MemBroker) Records() []Record {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	records := make([]Record, len(broker.records))
+	copy(records, broker.records)
+	return records
+}
+
+// Put stores the value under the key.
+func (broker *MemBroker) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	stored := make([]byte, len(data))
+	copy(stored, data)
+	broker.data[key] = stored
+	broker.records = append(broker.records, Record{Key: key, Data: stored})
+	return nil
+}
+
+// GetValue retrieves the value of the key.
+func (broker *MemBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	stored, found := broker.data[key]
+	if !found {
+		return nil, false, 0, nil
+	}
+	return stored, true, 0, nil
+}
+
+// ListKeys returns an iterator over the keys matching the prefix,
+// sorted lexicographically.
+func (broker *MemBroker) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	return &memKeyIterator{keys: broker.keysFor(prefix)}, nil
+}
+
+// ListValues returns an iterator over the key-value pairs matching the
+// prefix, sorted lexicographically by the key.
+func (broker *MemBroker) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	keys := broker.keysForLocked(prefix)
+	pairs := make([]memKeyVal, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, memKeyVal{key: key, value: broker.data[key]})
+	}
+	return &memKeyValIterator{pairs: pairs}, nil
+}
+
+// Delete removes the key.
+func (broker *MemBroker) Delete(key string) (existed bool, err error) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	_, existed = broker.data[key]
+	delete(broker.data, key)
+	if existed {
+		broker.records = append(broker.records, Record{Key: key, Del: true})
+	}
+	return existed, nil
+}
+
+// NewTxn creates a transaction. The operations are buffered and applied
+// together by Commit.
+func (broker *MemBroker) NewTxn() keyval.BytesTxn {
+	return &memTxn{broker: broker}
+}
+
+// keysFor returns the sorted keys matching the prefix.
+func (broker *MemBroker) keysFor(prefix string) []string {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return broker.keysForLocked(prefix)
+}
+
+// keysForLocked returns the sorted keys matching the prefix. The caller
+// must hold the mutex.
+func (broker *MemBroker) keysForLocked(prefix string) []string {
+	var keys []string
+	for key := range broker.data {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// memTxn buffers the operations until Commit.
+type memTxn struct {
+	broker *MemBroker
+	ops    []Record
+}
+
+// Put adds a store operation into the transaction.
+func (txn *memTxn) Put(key string, data []byte) keyval.BytesTxn {
+	txn.ops = append(txn.ops, Record{Key: key, Data: data})
+	return txn
+}
+
+// Delete adds a delete operation into the transaction.
+func (txn *memTxn) Delete(key string) keyval.BytesTxn {
+	txn.ops = append(txn.ops, Record{Key: key, Del: true})
+	return txn
+}
+
+// Commit applies the buffered operations.
+func (txn *memTxn) Commit() error {
+	for _, op := range txn.ops {
+		if op.Del {
+			txn.broker.Delete(op.Key)
+			continue
+		}
+		txn.broker.Put(op.Key, op.Data)
+	}
+	return nil
+}
+
+// memKeyVal is one listed key-value pair.
+type memKeyVal struct {
+	key   string
+	value []byte
+}
+
+// GetKey returns the key of the pair.
+func (kv memKeyVal) GetKey() string {
+	return kv.key
+}
+
+// GetValue returns the value of the pair.
+func (kv memKeyVal) GetValue() []byte {
+	return kv.value
+}
+
+// GetRevision returns zero, the broker does not version the values.
+func (kv memKeyVal) GetRevision() int64 {
+	return 0
+}
+
+// memKeyValIterator iterates over listed key-value pairs.
+type memKeyValIterator struct {
+	pairs []memKeyVal
+	index int
+}
+
+// GetNext retrieves the following item from the iterator.
+func (it *memKeyValIterator) GetNext() (keyval.BytesKeyVal, bool) {
+	if it.index >= len(it.pairs) {
+		return nil, true
+	}
+	kv := it.pairs[it.index]
+	it.index++
+	return kv, false
+}
+
+// memKeyIterator iterates over listed keys.
+type memKeyIterator struct {
+	keys  []string
+	index int
+}
+
+// GetNext retrieves the following key from the iterator.
+func (it *memKeyIterator) GetNext() (string, int64, bool) {
+	if it.index >= len(it.keys) {
+		return "", 0, true
+	}
+	key := it.keys[it.index]
+	it.index++
+	return key, 0, false
+}
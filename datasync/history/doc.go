@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history records the northbound configuration changes applied
+// by an agent into a bounded audit trail under a well-known keyval
+// prefix. The trail is exposed over REST and supports rolling the
+// config subtree of the agent back to a selected point, giving the
+// operators an undo button after a bad push.
+package history
@@ -0,0 +1,237 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// HistoryPrefix is the well-known key prefix the change records are
+// stored under, next to the config subtree of the agent.
+const HistoryPrefix = "history/"
+
+// defaultLimit bounds the trail when no limit is configured.
+const defaultLimit = 1000
+
+// Record is one applied northbound change.
+type Record struct {
+	// Seq orders the records; it grows monotonically.
+	Seq uint64 `json:"seq"`
+	// Key the change is about.
+	Key string `json:"key"`
+	// OldValue is the value before the change; nil when the key was
+	// absent or unknown to the trail.
+	OldValue []byte `json:"old_value,omitempty"`
+	// NewValue is the value after the change; nil for a delete.
+	NewValue []byte `json:"new_value,omitempty"`
+	// Del distinguishes a delete from a put.
+	Del bool `json:"del,omitempty"`
+	// Revision is the datastore revision of the change.
+	Revision int64 `json:"revision"`
+	// Time the change was recorded.
+	Time time.Time `json:"time"`
+}
+
+// History maintains the bounded change trail of one agent. The records
+// are persisted through the broker under the history prefix, so the
+// trail survives agent restarts when the broker does.
+type History struct {
+	log    logging.Logger
+	broker keyval.BytesBroker
+	limit  int
+
+	mu sync.Mutex
+	// seq is the sequence number of the last appended record.
+	seq uint64
+	// lastValues tracks the latest recorded value of every key, nil for
+	// a deleted key; it provides the old value of the next record.
+	lastValues map[string][]byte
+}
+
+// NewHistory creates a trail persisted through the broker, typically
+// the broker prefixed with the prefix of the agent. limit <= 0 selects
+// the default bound. Existing records are reloaded, so the trail
+// continues across restarts.
+func NewHistory(log logging.Logger, broker keyval.BytesBroker, limit int) (*History, error) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	history := &History{
+		log:        log,
+		broker:     broker,
+		limit:      limit,
+		lastValues: map[string][]byte{},
+	}
+	records, err := history.List("")
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		history.seq = record.Seq
+		if record.Del {
+			history.lastValues[record.Key] = nil
+		} else {
+			history.lastValues[record.Key] = record.NewValue
+		}
+	}
+	return history, nil
+}
+
+// Append records one applied change. The old value is taken from the
+// last record of the key.
+func (history *History) Append(key string, newValue []byte, del bool, revision int64) error {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	history.seq++
+	record := &Record{
+		Seq:      history.seq,
+		Key:      key,
+		OldValue: history.lastValues[key],
+		NewValue: newValue,
+		Del:      del,
+		Revision: revision,
+		Time:     time.Now(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := history.broker.Put(recordKey(record.Seq), data); err != nil {
+		return err
+	}
+	if del {
+		history.lastValues[key] = nil
+	} else {
+		history.lastValues[key] = newValue
+	}
+	history.prune()
+	return nil
+}
+
+// List returns the records in sequence order, all of them for an empty
+// key or just those of the given key.
+func (history *History) List(key string) ([]Record, error) {
+	iterator, err := history.broker.ListValues(HistoryPrefix)
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for {
+		kv, allReceived := iterator.GetNext()
+		if allReceived {
+			return records, nil
+		}
+		record := Record{}
+		if err := json.Unmarshal(kv.GetValue(), &record); err != nil {
+			history.log.Warn("Skipping an unreadable history record ", kv.GetKey(), ": ", err)
+			continue
+		}
+		if key == "" || record.Key == key {
+			records = append(records, record)
+		}
+	}
+}
+
+// Rollback restores the recorded keys to their state right after the
+// record with the given sequence number was applied (seq 0 restores the
+// state before the first record). The writes go through the same broker,
+// so they propagate like any other northbound change and are recorded
+// again as new entries of the trail.
+func (history *History) Rollback(seq uint64) error {
+	records, err := history.List("")
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if last := records[len(records)-1].Seq; seq > last {
+		return fmt.Errorf("the history has no record %d, the last one is %d", seq, last)
+	}
+
+	// replay the trail to learn the value of every key at the requested
+	// point and at the present
+	atPoint := map[string][]byte{}
+	current := map[string][]byte{}
+	for _, record := range records {
+		value := record.NewValue
+		if record.Del {
+			value = nil
+		}
+		if record.Seq <= seq {
+			atPoint[record.Key] = value
+		} else if _, known := atPoint[record.Key]; !known {
+			// the key changed only after the point; its old value at the
+			// first later record is its state at the point
+			atPoint[record.Key] = record.OldValue
+		}
+		current[record.Key] = value
+	}
+
+	txn := history.broker.NewTxn()
+	changes := 0
+	for key, currentValue := range current {
+		target := atPoint[key]
+		if string(target) == string(currentValue) {
+			continue
+		}
+		if target == nil {
+			txn.Delete(key)
+		} else {
+			txn.Put(key, target)
+		}
+		changes++
+	}
+	if changes == 0 {
+		return nil
+	}
+	history.log.WithFields(logging.Fields{"seq": seq, "changes": changes}).
+		Info("Rolling the configuration back")
+	return txn.Commit()
+}
+
+// prune drops the oldest records above the bound. The caller must hold
+// the mutex.
+func (history *History) prune() {
+	if history.seq < uint64(history.limit) {
+		return
+	}
+	oldest := history.seq - uint64(history.limit)
+	// records older than the bound were pruned earlier, only a few
+	// trailing ones can remain
+	for seq := oldest; seq > 0; seq-- {
+		existed, err := history.broker.Delete(recordKey(seq))
+		if err != nil {
+			history.log.Warn("Failed to prune the history record ", seq, ": ", err)
+			return
+		}
+		if !existed {
+			return
+		}
+	}
+}
+
+// recordKey returns the storage key of the record with the sequence
+// number, zero-padded so the lexical order matches the numeric one.
+func recordKey(seq uint64) string {
+	return fmt.Sprintf("%s%012d", HistoryPrefix, seq)
+}
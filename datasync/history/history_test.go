@@ -0,0 +1,227 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/datasync/datasynctest"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// TestAppendAndList checks the recording of changes with the old-value
+// chaining.
+func TestAppendAndList(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	gomega.Expect(history.Append("if/memif0", []byte(`{"mtu": 1500}`), false, 1)).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/memif0", []byte(`{"mtu": 9000}`), false, 2)).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/memif0", nil, true, 3)).To(gomega.BeNil())
+
+	records, err := history.List("")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(records).To(gomega.HaveLen(3))
+	gomega.Expect(records[0].Seq).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(records[0].OldValue).To(gomega.BeNil())
+	gomega.Expect(records[1].OldValue).To(gomega.BeEquivalentTo([]byte(`{"mtu": 1500}`)))
+	gomega.Expect(records[2].Del).To(gomega.BeTrue())
+	gomega.Expect(records[2].OldValue).To(gomega.BeEquivalentTo([]byte(`{"mtu": 9000}`)))
+	gomega.Expect(records[2].Revision).To(gomega.BeEquivalentTo(3))
+
+	filtered, err := history.List("other")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(filtered).To(gomega.BeEmpty())
+}
+
+// TestBoundedTrail checks that the oldest records are pruned above the
+// limit.
+func TestBoundedTrail(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 3)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	for i := 1; i <= 5; i++ {
+		gomega.Expect(history.Append("key", []byte{byte(i)}, false, int64(i))).To(gomega.BeNil())
+	}
+
+	records, err := history.List("")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(records).To(gomega.HaveLen(3))
+	gomega.Expect(records[0].Seq).To(gomega.BeEquivalentTo(3))
+	gomega.Expect(records[2].Seq).To(gomega.BeEquivalentTo(5))
+}
+
+// TestReloadAcrossRestart checks that a new trail over the same broker
+// continues the sequence and the old-value chaining.
+func TestReloadAcrossRestart(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(history.Append("key", []byte("one"), false, 1)).To(gomega.BeNil())
+
+	reloaded, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(reloaded.Append("key", []byte("two"), false, 2)).To(gomega.BeNil())
+
+	records, err := reloaded.List("")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(records).To(gomega.HaveLen(2))
+	gomega.Expect(records[1].Seq).To(gomega.BeEquivalentTo(2))
+	gomega.Expect(records[1].OldValue).To(gomega.BeEquivalentTo([]byte("one")))
+}
+
+// TestRollback checks the restoration of the recorded keys to an
+// earlier point.
+func TestRollback(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// seq 1-2: the state to restore
+	gomega.Expect(broker.Put("if/memif0", []byte("good"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/memif0", []byte("good"), false, 1)).To(gomega.BeNil())
+	gomega.Expect(broker.Put("if/memif1", []byte("keep"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/memif1", []byte("keep"), false, 2)).To(gomega.BeNil())
+
+	// seq 3-5: the bad push
+	gomega.Expect(broker.Put("if/memif0", []byte("bad"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/memif0", []byte("bad"), false, 3)).To(gomega.BeNil())
+	gomega.Expect(broker.Delete("if/memif1")).To(gomega.BeEquivalentTo(true))
+	gomega.Expect(history.Append("if/memif1", nil, true, 4)).To(gomega.BeNil())
+	gomega.Expect(broker.Put("if/new", []byte("extra"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("if/new", []byte("extra"), false, 5)).To(gomega.BeNil())
+
+	gomega.Expect(history.Rollback(2)).To(gomega.BeNil())
+
+	value, found, _, _ := broker.GetValue("if/memif0")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(value).To(gomega.BeEquivalentTo([]byte("good")))
+	value, found, _, _ = broker.GetValue("if/memif1")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(value).To(gomega.BeEquivalentTo([]byte("keep")))
+	_, found, _, _ = broker.GetValue("if/new")
+	gomega.Expect(found).To(gomega.BeFalse())
+
+	// a sequence number past the end of the trail is rejected
+	gomega.Expect(history.Rollback(99)).NotTo(gomega.BeNil())
+}
+
+// fakeWatcher lets the test emit bytes watch events.
+type fakeWatcher struct {
+	respChan chan keyval.BytesWatchResp
+}
+
+func (watcher *fakeWatcher) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	watcher.respChan = respChan
+	return nil
+}
+
+// fakeWatchResp is one emitted change event.
+type fakeWatchResp struct {
+	key        string
+	value      []byte
+	changeType db.PutDel
+}
+
+func (resp *fakeWatchResp) GetKey() string           { return resp.key }
+func (resp *fakeWatchResp) GetValue() []byte         { return resp.value }
+func (resp *fakeWatchResp) GetChangeType() db.PutDel { return resp.changeType }
+func (resp *fakeWatchResp) GetRevision() int64       { return 10 }
+
+// TestRecordingWatcher checks that the forwarded events land in the
+// trail.
+func TestRecordingWatcher(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	watcher := &fakeWatcher{}
+	respChan := make(chan keyval.BytesWatchResp, 1)
+	gomega.Expect(NewRecordingWatcher(history, watcher).Watch(respChan, "if/")).To(gomega.BeNil())
+
+	watcher.respChan <- &fakeWatchResp{key: "if/memif0", value: []byte("cfg"), changeType: db.Put}
+	var forwarded keyval.BytesWatchResp
+	gomega.Eventually(respChan, time.Second).Should(gomega.Receive(&forwarded))
+	gomega.Expect(forwarded.GetKey()).To(gomega.BeEquivalentTo("if/memif0"))
+
+	records, err := history.List("")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(records).To(gomega.HaveLen(1))
+	gomega.Expect(records[0].NewValue).To(gomega.BeEquivalentTo([]byte("cfg")))
+	gomega.Expect(records[0].Revision).To(gomega.BeEquivalentTo(10))
+}
+
+// TestHistoryHandlers checks the REST endpoints of the trail.
+func TestHistoryHandlers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	history, err := NewHistory(logrus.StandardLogger(), broker, 0)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(broker.Put("key", []byte("one"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("key", []byte("one"), false, 1)).To(gomega.BeNil())
+	gomega.Expect(broker.Put("key", []byte("two"))).To(gomega.BeNil())
+	gomega.Expect(history.Append("key", []byte("two"), false, 2)).To(gomega.BeNil())
+
+	router := mux.NewRouter()
+	RegisterHistoryHandlers(routerHandlers{router}, history)
+
+	listResp := httptest.NewRecorder()
+	router.ServeHTTP(listResp, httptest.NewRequest("GET", HistoryPath+"?key=key", nil))
+	gomega.Expect(listResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var records []Record
+	gomega.Expect(json.Unmarshal(listResp.Body.Bytes(), &records)).To(gomega.BeNil())
+	gomega.Expect(records).To(gomega.HaveLen(2))
+
+	rollbackResp := httptest.NewRecorder()
+	router.ServeHTTP(rollbackResp, httptest.NewRequest("POST", "/history/rollback/1", nil))
+	gomega.Expect(rollbackResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	value, _, _, _ := broker.GetValue("key")
+	gomega.Expect(value).To(gomega.BeEquivalentTo([]byte("one")))
+
+	badResp := httptest.NewRecorder()
+	router.ServeHTTP(badResp, httptest.NewRequest("POST", "/history/rollback/99", nil))
+	gomega.Expect(badResp.Code).To(gomega.BeEquivalentTo(http.StatusInternalServerError))
+}
+
+// routerHandlers adapts a plain mux router to the rest.HTTPHandlers
+// interface for the tests.
+type routerHandlers struct {
+	router *mux.Router
+}
+
+func (handlers routerHandlers) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	return handlers.router.Handle(path, handler).Methods(methods...)
+}
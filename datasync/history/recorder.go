@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// NewRecordingWatcher wraps the watcher so that every delivered change
+// event is appended to the trail before it reaches the subscriber. Wire
+// it between the keyval plugin and the datasync adapter to get the
+// northbound changes recorded transparently.
+func NewRecordingWatcher(history *History, watcher keyval.BytesWatcher) keyval.BytesWatcher {
+	return &recordingWatcher{history: history, watcher: watcher}
+}
+
+// recordingWatcher implements keyval.BytesWatcher.
+type recordingWatcher struct {
+	history *History
+	watcher keyval.BytesWatcher
+}
+
+// Watch subscribes an intermediate channel and forwards the recorded
+// events to respChan.
+func (rw *recordingWatcher) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	eventChan := make(chan keyval.BytesWatchResp)
+	if err := rw.watcher.Watch(eventChan, keys...); err != nil {
+		return err
+	}
+	go func() {
+		for resp := range eventChan {
+			err := rw.history.Append(resp.GetKey(), resp.GetValue(),
+				resp.GetChangeType() == db.Delete, resp.GetRevision())
+			if err != nil {
+				rw.history.log.Warn("Failed to record the change of ", resp.GetKey(), ": ", err)
+			}
+			respChan <- resp
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the history endpoints.
+const (
+	// HistoryPath lists the records; the optional "key" query parameter
+	// restricts the listing to one key.
+	HistoryPath = "/history"
+	// RollbackPath rolls the config subtree back to the state right
+	// after the record with the given sequence number.
+	RollbackPath = "/history/rollback/{seq:[0-9]+}"
+)
+
+// RegisterHistoryHandlers exposes the trail and the rollback over REST.
+func RegisterHistoryHandlers(handlers rest.HTTPHandlers, history *History) {
+	handlers.RegisterHTTPHandler(HistoryPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			records, err := history.List(req.URL.Query().Get("key"))
+			w.Header().Set("Content-Type", "application/json")
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			json.NewEncoder(w).Encode(records)
+		}), "GET")
+
+	handlers.RegisterHTTPHandler(RollbackPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			seq, err := strconv.ParseUint(mux.Vars(req)["seq"], 10, 64)
+			w.Header().Set("Content-Type", "application/json")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			if err := history.Rollback(seq); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"status": "rolled back"})
+		}), "POST")
+}
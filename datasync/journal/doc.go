@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journal keeps a bounded in-memory journal of the received
+// watch events. A plugin that registers late or restarts its watch can
+// replay the short window of events it missed instead of triggering a
+// full resync; when the window was already pruned from the journal, the
+// replay reports the gap and the caller falls back to the resync.
+package journal
@@ -0,0 +1,152 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/datasync"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// defaultLimit bounds the journal when no limit is configured.
+const defaultLimit = 1000
+
+// Entry is one journaled watch event.
+type Entry struct {
+	// Seq orders the entries; it grows monotonically.
+	Seq uint64
+	// Time the event was journaled.
+	Time time.Time
+	// Resp is the journaled change event.
+	Resp keyval.ProtoWatchResp
+}
+
+// Journal keeps the received watch events for replay, bounded by count
+// and optionally by age.
+type Journal struct {
+	log    logging.Logger
+	limit  int
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	entries []Entry
+	nextSeq uint64
+}
+
+// NewJournal creates a journal keeping at most limit events (<= 0
+// selects the default) no older than maxAge (zero disables the age
+// bound).
+func NewJournal(log logging.Logger, limit int, maxAge time.Duration) *Journal {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	return &Journal{log: log, limit: limit, maxAge: maxAge, nextSeq: 1}
+}
+
+// Append journals the event and returns its sequence number.
+func (journal *Journal) Append(resp keyval.ProtoWatchResp) uint64 {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	seq := journal.nextSeq
+	journal.nextSeq++
+	journal.entries = append(journal.entries, Entry{Seq: seq, Time: time.Now(), Resp: resp})
+	journal.prune(time.Now())
+	return seq
+}
+
+// LastSeq returns the sequence number of the newest journaled event,
+// zero when nothing was journaled yet.
+func (journal *Journal) LastSeq() uint64 {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	return journal.nextSeq - 1
+}
+
+// Replay returns the journaled events following the given sequence
+// number, restricted to the key prefixes when any are given. complete
+// is false when events after afterSeq were already pruned; the caller
+// is then supposed to fall back to a full resync.
+func (journal *Journal) Replay(afterSeq uint64, keyPrefixes ...string) (entries []Entry, complete bool) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	journal.prune(time.Now())
+	if afterSeq+1 < journal.oldestSeq() {
+		return nil, false
+	}
+	for _, entry := range journal.entries {
+		if entry.Seq > afterSeq && matchesPrefixes(entry.Resp.GetKey(), keyPrefixes) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, true
+}
+
+// oldestSeq returns the sequence number the journal still starts at.
+// The caller must hold the mutex.
+func (journal *Journal) oldestSeq() uint64 {
+	if len(journal.entries) == 0 {
+		return journal.nextSeq
+	}
+	return journal.entries[0].Seq
+}
+
+// prune drops the entries above the count bound and past the age bound.
+// The caller must hold the mutex.
+func (journal *Journal) prune(now time.Time) {
+	if drop := len(journal.entries) - journal.limit; drop > 0 {
+		journal.entries = journal.entries[drop:]
+	}
+	if journal.maxAge <= 0 {
+		return
+	}
+	deadline := now.Add(-journal.maxAge)
+	firstFresh := 0
+	for ; firstFresh < len(journal.entries); firstFresh++ {
+		if !journal.entries[firstFresh].Time.Before(deadline) {
+			break
+		}
+	}
+	journal.entries = journal.entries[firstFresh:]
+}
+
+// matchesPrefixes reports whether the key falls under one of the
+// prefixes; no prefixes match everything.
+func matchesPrefixes(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewJournalMiddleware creates a datasync middleware that journals
+// every delivered change event. Wire it into the watcher adapter to
+// capture the northbound events transparently.
+func NewJournalMiddleware(journal *Journal) datasync.Middleware {
+	return func(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+		journal.Append(resp)
+		return resp, true
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// journalResp is a minimal watch event carrying just a key.
+type journalResp struct {
+	key string
+}
+
+func (resp *journalResp) GetKey() string                   { return resp.key }
+func (resp *journalResp) GetValue(msg proto.Message) error { return nil }
+func (resp *journalResp) GetChangeType() db.PutDel         { return db.Put }
+func (resp *journalResp) GetRevision() int64               { return 0 }
+
+// TestAppendAndReplay checks the replay of the events following a
+// sequence number.
+func TestAppendAndReplay(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	journal := NewJournal(logrus.StandardLogger(), 0, 0)
+	gomega.Expect(journal.LastSeq()).To(gomega.BeEquivalentTo(0))
+
+	gomega.Expect(journal.Append(&journalResp{key: "if/memif0"})).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(journal.Append(&journalResp{key: "if/memif1"})).To(gomega.BeEquivalentTo(2))
+	gomega.Expect(journal.Append(&journalResp{key: "route/default"})).To(gomega.BeEquivalentTo(3))
+	gomega.Expect(journal.LastSeq()).To(gomega.BeEquivalentTo(3))
+
+	entries, complete := journal.Replay(1)
+	gomega.Expect(complete).To(gomega.BeTrue())
+	gomega.Expect(entries).To(gomega.HaveLen(2))
+	gomega.Expect(entries[0].Seq).To(gomega.BeEquivalentTo(2))
+	gomega.Expect(entries[0].Resp.GetKey()).To(gomega.BeEquivalentTo("if/memif1"))
+
+	// nothing new after the last sequence number
+	entries, complete = journal.Replay(3)
+	gomega.Expect(complete).To(gomega.BeTrue())
+	gomega.Expect(entries).To(gomega.BeEmpty())
+}
+
+// TestReplayPrefixFilter checks the restriction of the replay to key
+// prefixes.
+func TestReplayPrefixFilter(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	journal := NewJournal(logrus.StandardLogger(), 0, 0)
+	journal.Append(&journalResp{key: "if/memif0"})
+	journal.Append(&journalResp{key: "route/default"})
+	journal.Append(&journalResp{key: "if/memif1"})
+
+	entries, complete := journal.Replay(0, "if/")
+	gomega.Expect(complete).To(gomega.BeTrue())
+	gomega.Expect(entries).To(gomega.HaveLen(2))
+	gomega.Expect(entries[1].Resp.GetKey()).To(gomega.BeEquivalentTo("if/memif1"))
+}
+
+// TestCountBound checks the pruning by the count limit and the gap
+// detection of the replay.
+func TestCountBound(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	journal := NewJournal(logrus.StandardLogger(), 2, 0)
+	journal.Append(&journalResp{key: "a"})
+	journal.Append(&journalResp{key: "b"})
+	journal.Append(&journalResp{key: "c"})
+
+	// the first event was pruned, the window starting at it is gone
+	_, complete := journal.Replay(0)
+	gomega.Expect(complete).To(gomega.BeFalse())
+
+	entries, complete := journal.Replay(2)
+	gomega.Expect(complete).To(gomega.BeTrue())
+	gomega.Expect(entries).To(gomega.HaveLen(1))
+	gomega.Expect(entries[0].Seq).To(gomega.BeEquivalentTo(3))
+}
+
+// TestAgeBound checks the pruning by the age limit.
+func TestAgeBound(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	journal := NewJournal(logrus.StandardLogger(), 0, 10*time.Millisecond)
+	journal.Append(&journalResp{key: "old"})
+	time.Sleep(20 * time.Millisecond)
+	journal.Append(&journalResp{key: "fresh"})
+
+	entries, complete := journal.Replay(1)
+	gomega.Expect(complete).To(gomega.BeTrue())
+	gomega.Expect(entries).To(gomega.HaveLen(1))
+	gomega.Expect(entries[0].Resp.GetKey()).To(gomega.BeEquivalentTo("fresh"))
+
+	// the pruned window is reported as a gap
+	_, complete = journal.Replay(0)
+	gomega.Expect(complete).To(gomega.BeFalse())
+}
+
+// TestJournalMiddleware checks that the middleware journals the events
+// and always delivers them.
+func TestJournalMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	journal := NewJournal(logrus.StandardLogger(), 0, 0)
+	middleware := NewJournalMiddleware(journal)
+
+	event := &journalResp{key: "if/memif0"}
+	resp, deliver := middleware(event)
+	gomega.Expect(deliver).To(gomega.BeTrue())
+	gomega.Expect(resp).To(gomega.BeIdenticalTo(keyval.ProtoWatchResp(event)))
+	gomega.Expect(journal.LastSeq()).To(gomega.BeEquivalentTo(1))
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import "github.com/ligato/cn-infra/db/keyval"
+
+// Middleware processes a change event after it has been received from
+// the transport and before it is delivered to the plugin. It may enrich
+// or rewrite the event (e.g. field defaulting, deprecated-key translation)
+// and returns deliver=false to filter the event out entirely.
+type Middleware func(resp keyval.ProtoWatchResp) (newResp keyval.ProtoWatchResp, deliver bool)
+
+// WithKey returns a copy of the change event with the key replaced. It is
+// a convenience helper for middleware that translates deprecated keys.
+func WithKey(resp keyval.ProtoWatchResp, key string) keyval.ProtoWatchResp {
+	return &keyRewriteResp{ProtoWatchResp: resp, key: key}
+}
+
+// keyRewriteResp overrides the key of a change event.
+type keyRewriteResp struct {
+	keyval.ProtoWatchResp
+	key string
+}
+
+// GetKey returns the rewritten key.
+func (resp *keyRewriteResp) GetKey() string {
+	return resp.key
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// ackTimeout is the maximum time the orchestrator waits until all plugins
+// acknowledge a resync event.
+const ackTimeout = 1 * time.Minute
+
+// Config holds the settings of the resync orchestrator.
+type Config struct {
+	// Period enables the periodic automatic resync trigger. It serves
+	// as a safety net against missed watch events. Zero disables the trigger.
+	Period time.Duration
+	// MaxJitter is the upper bound of the random delay added to each period
+	// so that a fleet of agents does not resync simultaneously against
+	// the same datastore. It defaults to a tenth of the Period.
+	MaxJitter time.Duration
+}
+
+// Plugin implements the resync orchestrator. It keeps track of registered plugins
+// and triggers their resync either on demand (DoResync) or periodically.
+type Plugin struct {
+	log    logging.Logger
+	config *Config
+
+	mu            sync.Mutex
+	registrations map[string]*registration
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the resync orchestrator. Nil config
+// disables the periodic trigger.
+func NewPlugin(log logging.Logger, config *Config) *Plugin {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Plugin{
+		log:           log,
+		config:        config,
+		registrations: map[string]*registration{},
+		closeChan:     make(chan struct{}),
+	}
+}
+
+// Init starts the periodic resync trigger if it is enabled by the configuration.
+func (plugin *Plugin) Init() error {
+	if plugin.config.Period > 0 {
+		plugin.wg.Add(1)
+		go plugin.periodicResync()
+	}
+	return nil
+}
+
+// Close stops the periodic resync trigger.
+func (plugin *Plugin) Close() error {
+	close(plugin.closeChan)
+	plugin.wg.Wait()
+	return nil
+}
+
+// Register subscribes a plugin for resync events. The resyncName must be unique.
+func (plugin *Plugin) Register(resyncName string) (Registration, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if _, found := plugin.registrations[resyncName]; found {
+		return nil, fmt.Errorf("resync registration %q already exists", resyncName)
+	}
+	reg := &registration{name: resyncName, statusChan: make(chan StatusEvent, 1)}
+	plugin.registrations[resyncName] = reg
+	return reg, nil
+}
+
+// ListRegistrations returns the names of the subscribed plugins, sorted
+// alphabetically.
+func (plugin *Plugin) ListRegistrations() []string {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	names := make([]string, 0, len(plugin.registrations))
+	for name := range plugin.registrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DoResync triggers the resync of all registered plugins and blocks until
+// all of them acknowledge the event or the timeout expires.
+func (plugin *Plugin) DoResync() {
+	plugin.mu.Lock()
+	regs := make([]*registration, 0, len(plugin.registrations))
+	for _, reg := range plugin.registrations {
+		regs = append(regs, reg)
+	}
+	plugin.mu.Unlock()
+
+	plugin.resyncRegistrations(regs)
+}
+
+// DoResyncPlugins triggers the resync of just the named plugins and
+// blocks like DoResync. Unknown names are reported without triggering
+// anything.
+func (plugin *Plugin) DoResyncPlugins(resyncNames ...string) error {
+	plugin.mu.Lock()
+	regs := make([]*registration, 0, len(resyncNames))
+	for _, name := range resyncNames {
+		reg, found := plugin.registrations[name]
+		if !found {
+			plugin.mu.Unlock()
+			return fmt.Errorf("no resync registration %q exists", name)
+		}
+		regs = append(regs, reg)
+	}
+	plugin.mu.Unlock()
+
+	plugin.resyncRegistrations(regs)
+	return nil
+}
+
+// resyncRegistrations delivers the resync event to the registrations
+// and waits for the acknowledgements.
+func (plugin *Plugin) resyncRegistrations(regs []*registration) {
+	var acks []*statusEvent
+	for _, reg := range regs {
+		event := &statusEvent{status: Started, ackChan: make(chan struct{})}
+		select {
+		case reg.statusChan <- event:
+			acks = append(acks, event)
+		default:
+			plugin.log.WithField("resyncName", reg.name).
+				Warn("Unable to deliver the resync event, channel is full")
+		}
+	}
+
+	timeout := time.After(ackTimeout)
+	for i, event := range acks {
+		select {
+		case <-event.ackChan:
+		case <-timeout:
+			plugin.log.WithField("resyncName", regs[i].name).
+				Warn("Timeout expired while waiting for the resync acknowledgement")
+		case <-plugin.closeChan:
+			return
+		}
+	}
+}
+
+// periodicResync triggers the resync each period plus a random jitter.
+func (plugin *Plugin) periodicResync() {
+	defer plugin.wg.Done()
+
+	maxJitter := plugin.config.MaxJitter
+	if maxJitter == 0 {
+		maxJitter = plugin.config.Period / 10
+	}
+	for {
+		delay := plugin.config.Period
+		if maxJitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(maxJitter) + 1))
+		}
+		select {
+		case <-plugin.closeChan:
+			return
+		case <-time.After(delay):
+			plugin.log.Debug("Periodic resync triggered")
+			plugin.DoResync()
+		}
+	}
+}
+
+// registration implements Registration.
+type registration struct {
+	name       string
+	statusChan chan StatusEvent
+}
+
+// StatusChan delivers events that instruct the plugin to start its resync.
+func (reg *registration) StatusChan() chan StatusEvent {
+	return reg.statusChan
+}
+
+// String identifies the registration.
+func (reg *registration) String() string {
+	return reg.name
+}
+
+// statusEvent implements StatusEvent.
+type statusEvent struct {
+	status  Status
+	ackChan chan struct{}
+	ackOnce sync.Once
+}
+
+// ResyncStatus returns the status of the resync.
+func (event *statusEvent) ResyncStatus() Status {
+	return event.status
+}
+
+// Ack confirms that the subscribed plugin finished its part of the resync.
+func (event *statusEvent) Ack() {
+	event.ackOnce.Do(func() {
+		close(event.ackChan)
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestDoResync(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	reg, err := plugin.Register("testPlugin")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// duplicate registration is reported
+	_, err = plugin.Register("testPlugin")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	go func() {
+		event := <-reg.StatusChan()
+		gomega.Expect(event.ResyncStatus()).To(gomega.BeEquivalentTo(Started))
+		event.Ack()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		plugin.DoResync()
+		close(done)
+	}()
+	gomega.Eventually(done).Should(gomega.BeClosed())
+}
+
+func TestPeriodicResync(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{Period: time.Millisecond})
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	reg, err := plugin.Register("testPlugin")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	var event StatusEvent
+	gomega.Eventually(reg.StatusChan()).Should(gomega.Receive(&event))
+	event.Ack()
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the resync management endpoints.
+const (
+	// ResyncPath triggers the resync of all registered plugins with
+	// POST and lists the registrations with GET.
+	ResyncPath = "/resync"
+	// ResyncPluginPath triggers the resync of one named plugin.
+	ResyncPluginPath = "/resync/{name}"
+)
+
+// RegisterResyncHandlers exposes the resync triggers of the
+// orchestrator over REST.
+func RegisterResyncHandlers(handlers rest.HTTPHandlers, plugin *Plugin) {
+	handlers.RegisterHTTPHandler(ResyncPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if req.Method == "GET" {
+				json.NewEncoder(w).Encode(plugin.ListRegistrations())
+				return
+			}
+			plugin.DoResync()
+			json.NewEncoder(w).Encode(map[string]string{"status": "resync finished"})
+		}), "GET", "POST")
+
+	handlers.RegisterHTTPHandler(ResyncPluginPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := plugin.DoResyncPlugins(mux.Vars(req)["name"]); err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"status": "resync finished"})
+		}), "POST")
+}
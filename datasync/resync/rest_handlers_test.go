@@ -0,0 +1,103 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// ackAll keeps acknowledging the resync events of the registration.
+func ackAll(reg Registration) {
+	go func() {
+		for event := range reg.StatusChan() {
+			event.Ack()
+		}
+	}()
+}
+
+// TestDoResyncPlugins checks the targeted resync trigger.
+func TestDoResyncPlugins(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	first, err := plugin.Register("first")
+	gomega.Expect(err).To(gomega.BeNil())
+	second, err := plugin.Register("second")
+	gomega.Expect(err).To(gomega.BeNil())
+	ackAll(first)
+
+	gomega.Expect(plugin.ListRegistrations()).To(gomega.Equal([]string{"first", "second"}))
+
+	gomega.Expect(plugin.DoResyncPlugins("first")).To(gomega.BeNil())
+	// the other plugin was not resynced
+	gomega.Expect(second.StatusChan()).To(gomega.BeEmpty())
+
+	gomega.Expect(plugin.DoResyncPlugins("bogus")).NotTo(gomega.BeNil())
+}
+
+// TestResyncHandlers checks the REST triggers of the orchestrator.
+func TestResyncHandlers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	reg, err := plugin.Register("testPlugin")
+	gomega.Expect(err).To(gomega.BeNil())
+	ackAll(reg)
+
+	router := mux.NewRouter()
+	RegisterResyncHandlers(routerHandlers{router}, plugin)
+
+	listResp := httptest.NewRecorder()
+	router.ServeHTTP(listResp, httptest.NewRequest("GET", ResyncPath, nil))
+	gomega.Expect(listResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var names []string
+	gomega.Expect(json.Unmarshal(listResp.Body.Bytes(), &names)).To(gomega.BeNil())
+	gomega.Expect(names).To(gomega.Equal([]string{"testPlugin"}))
+
+	allResp := httptest.NewRecorder()
+	router.ServeHTTP(allResp, httptest.NewRequest("POST", ResyncPath, nil))
+	gomega.Expect(allResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+
+	oneResp := httptest.NewRecorder()
+	router.ServeHTTP(oneResp, httptest.NewRequest("POST", "/resync/testPlugin", nil))
+	gomega.Expect(oneResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+
+	missingResp := httptest.NewRecorder()
+	router.ServeHTTP(missingResp, httptest.NewRequest("POST", "/resync/bogus", nil))
+	gomega.Expect(missingResp.Code).To(gomega.BeEquivalentTo(http.StatusNotFound))
+}
+
+// routerHandlers adapts a plain mux router to the rest.HTTPHandlers
+// interface for the tests.
+type routerHandlers struct {
+	router *mux.Router
+}
+
+func (handlers routerHandlers) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	return handlers.router.Handle(path, handler).Methods(methods...)
+}
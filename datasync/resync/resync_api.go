@@ -0,0 +1,40 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+// Status of the resync delivered in StatusEvent.
+type Status string
+
+const (
+	// Started means that the resync is in progress and the plugin is supposed
+	// to reload its configuration from the datastore.
+	Started Status = "Started"
+)
+
+// StatusEvent is the event sent to the channel of a registration when a resync starts.
+type StatusEvent interface {
+	// ResyncStatus returns the status of the resync.
+	ResyncStatus() Status
+	// Ack confirms that the subscribed plugin finished its part of the resync.
+	Ack()
+}
+
+// Registration is a subscription of one plugin for resync events.
+type Registration interface {
+	// StatusChan delivers events that instruct the plugin to start its resync.
+	StatusChan() chan StatusEvent
+	// String identifies the registration.
+	String() string
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// StatusPrefix is the well-known key prefix under which the per-plugin apply
+// status records are published. External controllers and dashboards watch
+// this prefix to verify the convergence of the agent.
+const StatusPrefix = "status/"
+
+// ApplyStatus describes the outcome of the last resync/change application
+// of one plugin.
+type ApplyStatus struct {
+	// PluginName identifies the plugin the status belongs to.
+	PluginName string `json:"plugin_name"`
+	// Revision is the datastore revision the plugin has applied.
+	Revision int64 `json:"revision"`
+	// Error is the error message of the failed application, empty on success.
+	Error string `json:"error,omitempty"`
+	// LastUpdate is the timestamp of the status record.
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// StatusPublisher publishes per-plugin apply status records to the datastore.
+type StatusPublisher interface {
+	// PublishApplyStatus writes the status record of one plugin under
+	// the status prefix. Nil applyErr denotes a successful application.
+	PublishApplyStatus(pluginName string, revision int64, applyErr error) error
+}
+
+// NewStatusPublisher creates a publisher that writes the status records
+// using the given broker. The broker is supposed to be prefixed with
+// the prefix of the agent.
+func NewStatusPublisher(broker keyval.BytesBroker) StatusPublisher {
+	return &statusPublisher{broker: broker}
+}
+
+// statusPublisher implements StatusPublisher on top of a BytesBroker.
+type statusPublisher struct {
+	broker keyval.BytesBroker
+}
+
+// PublishApplyStatus writes the status record of one plugin under the status prefix.
+func (publisher *statusPublisher) PublishApplyStatus(pluginName string, revision int64, applyErr error) error {
+	status := &ApplyStatus{
+		PluginName: pluginName,
+		Revision:   revision,
+		LastUpdate: time.Now(),
+	}
+	if applyErr != nil {
+		status.Error = applyErr.Error()
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return publisher.broker.Put(StatusPrefix+pluginName, data)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// validationStatusName is the name the validation layer publishes its
+// apply status records under.
+const validationStatusName = "validation"
+
+// Validator is implemented by configuration messages with constraints
+// beyond their schema. The validation middleware calls it after a
+// successful unmarshal.
+type Validator interface {
+	// Validate returns a descriptive error when the message violates
+	// its constraints.
+	Validate() error
+}
+
+// ValidationRegistry maps key prefixes to the protobuf message types
+// stored under them.
+type ValidationRegistry struct {
+	mu       sync.RWMutex
+	prefixes []string
+	types    map[string]func() proto.Message
+}
+
+// NewValidationRegistry creates an empty registry.
+func NewValidationRegistry() *ValidationRegistry {
+	return &ValidationRegistry{types: map[string]func() proto.Message{}}
+}
+
+// RegisterType maps the keys under the prefix to the proto messages the
+// factory creates. When multiple registered prefixes match a key, the
+// longest one wins.
+func (registry *ValidationRegistry) RegisterType(prefix string, factory func() proto.Message) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, known := registry.types[prefix]; !known {
+		registry.prefixes = append(registry.prefixes, prefix)
+	}
+	registry.types[prefix] = factory
+}
+
+// factoryFor returns the factory of the longest registered prefix
+// matching the key, nil when there is none.
+func (registry *ValidationRegistry) factoryFor(key string) func() proto.Message {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	var best string
+	var factory func() proto.Message
+	for _, prefix := range registry.prefixes {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix && len(prefix) >= len(best) {
+			best = prefix
+			factory = registry.types[prefix]
+		}
+	}
+	return factory
+}
+
+// NewValidationMiddleware creates a middleware that checks the put
+// events against the types of the registry before they reach the
+// plugins. Invalid changes are filtered out and their error is published
+// through the publisher to the status prefix; keys without a registered
+// type and delete events pass through unchanged. The publisher may be
+// nil, the rejections are then only logged.
+func NewValidationMiddleware(log logging.Logger, registry *ValidationRegistry,
+	publisher StatusPublisher) Middleware {
+
+	return func(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+		if resp.GetChangeType() == db.Delete {
+			return resp, true
+		}
+		factory := registry.factoryFor(resp.GetKey())
+		if factory == nil {
+			return resp, true
+		}
+		err := validate(resp, factory())
+		if err == nil {
+			return resp, true
+		}
+		err = fmt.Errorf("invalid configuration under the key %q: %s", resp.GetKey(), err)
+		log.WithFields(logging.Fields{"key": resp.GetKey(), "err": err}).
+			Warn("Change event rejected by the validation")
+		if publisher != nil {
+			if pubErr := publisher.PublishApplyStatus(validationStatusName,
+				resp.GetRevision(), err); pubErr != nil {
+				log.Warn("Failed to publish the validation status: ", pubErr)
+			}
+		}
+		return resp, false
+	}
+}
+
+// validate checks the value of the event against the message type:
+// the unmarshal itself, unknown fields, required constraints and the
+// optional Validator of the message.
+func validate(resp keyval.ProtoWatchResp, msg proto.Message) error {
+	if err := resp.GetValue(msg); err != nil {
+		return err
+	}
+	if unrecognized(msg) {
+		return fmt.Errorf("the data contains fields unknown to %s", proto.MessageName(msg))
+	}
+	// marshalling surfaces the unset required fields of proto2 messages
+	if _, err := proto.Marshal(msg); err != nil {
+		return err
+	}
+	if validator, ok := msg.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// unrecognized reports whether the message retained bytes of fields
+// missing from its schema after the unmarshal.
+func unrecognized(msg proto.Message) bool {
+	value := reflect.ValueOf(msg)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return false
+	}
+	field := value.Elem().FieldByName("XXX_unrecognized")
+	return field.IsValid() && field.Kind() == reflect.Slice && field.Len() > 0
+}
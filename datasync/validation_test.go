@@ -0,0 +1,167 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// validatedConfig is a minimal proto message with a custom constraint.
+type validatedConfig struct {
+	Name             string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	MTU              int32  `protobuf:"varint,2,opt,name=mtu" json:"mtu,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *validatedConfig) Reset()         { *m = validatedConfig{} }
+func (m *validatedConfig) String() string { return proto.CompactTextString(m) }
+func (*validatedConfig) ProtoMessage()    {}
+
+// Validate rejects a non-positive MTU.
+func (m *validatedConfig) Validate() error {
+	if m.MTU <= 0 {
+		return errors.New("the MTU must be positive")
+	}
+	return nil
+}
+
+// validationResp is a change event delivering raw JSON data.
+type validationResp struct {
+	key        string
+	data       []byte
+	changeType db.PutDel
+	unmarshal  func(data []byte, msg proto.Message) error
+}
+
+func (resp *validationResp) GetKey() string { return resp.key }
+func (resp *validationResp) GetValue(msg proto.Message) error {
+	if resp.unmarshal != nil {
+		return resp.unmarshal(resp.data, msg)
+	}
+	return json.Unmarshal(resp.data, msg)
+}
+func (resp *validationResp) GetChangeType() db.PutDel { return resp.changeType }
+func (resp *validationResp) GetRevision() int64       { return 7 }
+
+// validationPublisher records the published status records.
+type validationPublisher struct {
+	names  []string
+	errors []string
+}
+
+func (publisher *validationPublisher) PublishApplyStatus(pluginName string, revision int64, applyErr error) error {
+	publisher.names = append(publisher.names, pluginName)
+	publisher.errors = append(publisher.errors, applyErr.Error())
+	return nil
+}
+
+// newValidationMiddleware builds a middleware with the test type
+// registered under the interface prefix.
+func newValidationMiddleware(publisher StatusPublisher) Middleware {
+	registry := NewValidationRegistry()
+	registry.RegisterType("/agent/if/", func() proto.Message { return &validatedConfig{} })
+	return NewValidationMiddleware(logrus.StandardLogger(), registry, publisher)
+}
+
+// TestValidationPassesValidData checks that valid changes and unmatched
+// keys are delivered untouched.
+func TestValidationPassesValidData(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	publisher := &validationPublisher{}
+	middleware := newValidationMiddleware(publisher)
+
+	valid := &validationResp{key: "/agent/if/memif0", data: []byte(`{"name": "memif0", "mtu": 1500}`)}
+	resp, deliver := middleware(valid)
+	gomega.Expect(deliver).To(gomega.BeTrue())
+	gomega.Expect(resp).To(gomega.BeIdenticalTo(keyval.ProtoWatchResp(valid)))
+
+	unmatched := &validationResp{key: "/agent/route/default", data: []byte("not even json")}
+	_, deliver = middleware(unmatched)
+	gomega.Expect(deliver).To(gomega.BeTrue())
+
+	deleted := &validationResp{key: "/agent/if/memif0", changeType: db.Delete}
+	_, deliver = middleware(deleted)
+	gomega.Expect(deliver).To(gomega.BeTrue())
+
+	gomega.Expect(publisher.errors).To(gomega.BeEmpty())
+}
+
+// TestValidationRejectsMalformedData checks that an unparseable value is
+// filtered out and the error is published to the status prefix.
+func TestValidationRejectsMalformedData(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	publisher := &validationPublisher{}
+	middleware := newValidationMiddleware(publisher)
+
+	_, deliver := middleware(&validationResp{key: "/agent/if/bad", data: []byte("{broken")})
+	gomega.Expect(deliver).To(gomega.BeFalse())
+	gomega.Expect(publisher.names).To(gomega.Equal([]string{"validation"}))
+	gomega.Expect(publisher.errors[0]).To(gomega.ContainSubstring("/agent/if/bad"))
+}
+
+// TestValidationRejectsCustomConstraint checks the Validator interface
+// of the registered message.
+func TestValidationRejectsCustomConstraint(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	publisher := &validationPublisher{}
+	middleware := newValidationMiddleware(publisher)
+
+	_, deliver := middleware(&validationResp{key: "/agent/if/memif0",
+		data: []byte(`{"name": "memif0", "mtu": -1}`)})
+	gomega.Expect(deliver).To(gomega.BeFalse())
+	gomega.Expect(publisher.errors[0]).To(gomega.ContainSubstring("the MTU must be positive"))
+}
+
+// TestValidationRejectsUnknownFields checks the detection of the bytes
+// a proto unmarshal could not attribute to the schema.
+func TestValidationRejectsUnknownFields(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	publisher := &validationPublisher{}
+	middleware := newValidationMiddleware(publisher)
+
+	unmarshal := func(data []byte, msg proto.Message) error {
+		if err := json.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		msg.(*validatedConfig).XXX_unrecognized = []byte{0x1a, 0x01, 0x78}
+		return nil
+	}
+	_, deliver := middleware(&validationResp{key: "/agent/if/memif0",
+		data: []byte(`{"name": "memif0", "mtu": 1500}`), unmarshal: unmarshal})
+	gomega.Expect(deliver).To(gomega.BeFalse())
+	gomega.Expect(publisher.errors[0]).To(gomega.ContainSubstring("fields unknown to"))
+}
+
+// TestValidationWithoutPublisher checks that rejections work with a nil
+// publisher.
+func TestValidationWithoutPublisher(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	middleware := newValidationMiddleware(nil)
+	_, deliver := middleware(&validationResp{key: "/agent/if/bad", data: []byte("{broken")})
+	gomega.Expect(deliver).To(gomega.BeFalse())
+}
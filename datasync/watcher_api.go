@@ -0,0 +1,38 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import "github.com/ligato/cn-infra/db/keyval"
+
+// Watcher defines API for monitoring changes in a datastore on behalf of a plugin.
+// Unlike keyval.ProtoWatcher, subscriptions created through this API are represented
+// by WatchRegistration objects and can therefore be modified or cancelled at runtime.
+type Watcher interface {
+	// WatchData starts monitoring changes associated with the given key prefixes.
+	// Watch events will be delivered to respChan. The watcherName is used
+	// to identify the subscription in logs.
+	WatchData(watcherName string, respChan chan keyval.ProtoWatchResp, keyPrefixes ...string) (WatchRegistration, error)
+}
+
+// WatchRegistration is a single subscription of one watcher. It allows the watcher
+// to add/remove watched key prefixes at runtime and to unsubscribe entirely.
+type WatchRegistration interface {
+	// Register adds a new key prefix to the subscription.
+	Register(keyPrefix string) error
+	// Unregister stops delivery of events matching the given key prefix.
+	Unregister(keyPrefix string) error
+	// Close cancels the subscription. No more events are delivered to respChan afterwards.
+	Close() error
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// NewWatcher creates an adapter that exposes the Watcher API on top of
+// the given keyval watcher. The optional middleware is applied to each
+// change event, in the given order, before it is delivered to the watcher.
+func NewWatcher(kvWatcher keyval.ProtoWatcher, middleware ...Middleware) Watcher {
+	return &watcherAdapter{kvWatcher: kvWatcher, middleware: middleware}
+}
+
+// watcherAdapter implements Watcher using a keyval.ProtoWatcher.
+type watcherAdapter struct {
+	kvWatcher  keyval.ProtoWatcher
+	middleware []Middleware
+}
+
+// WatchData creates a new registration and subscribes it to all given key prefixes.
+func (adapter *watcherAdapter) WatchData(watcherName string, respChan chan keyval.ProtoWatchResp,
+	keyPrefixes ...string) (WatchRegistration, error) {
+
+	reg := &watchDataReg{
+		watcherName: watcherName,
+		kvWatcher:   adapter.kvWatcher,
+		middleware:  adapter.middleware,
+		respChan:    respChan,
+		eventChan:   make(chan keyval.ProtoWatchResp),
+		closeChan:   make(chan struct{}),
+		prefixes:    map[string]bool{},
+	}
+	go reg.forwardEvents()
+
+	for _, prefix := range keyPrefixes {
+		err := reg.Register(prefix)
+		if err != nil {
+			reg.Close()
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// watchDataReg implements WatchRegistration. The underlying keyval subscriptions
+// cannot be cancelled, thus deregistration is implemented by filtering out
+// the events of inactive prefixes before they reach the watcher.
+type watchDataReg struct {
+	watcherName string
+	kvWatcher   keyval.ProtoWatcher
+	middleware  []Middleware
+	respChan    chan keyval.ProtoWatchResp
+	eventChan   chan keyval.ProtoWatchResp
+	closeChan   chan struct{}
+
+	mu sync.Mutex
+	// prefixes contains an entry for each key prefix ever subscribed in keyval,
+	// the value denotes whether the prefix is currently active.
+	prefixes map[string]bool
+	closed   bool
+}
+
+// Register adds a new key prefix to the subscription.
+func (reg *watchDataReg) Register(keyPrefix string) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.closed {
+		return fmt.Errorf("watch registration of %s is already closed", reg.watcherName)
+	}
+	if active, found := reg.prefixes[keyPrefix]; found {
+		if active {
+			return fmt.Errorf("key prefix %q is already registered by %s", keyPrefix, reg.watcherName)
+		}
+		// reuse the still running keyval subscription
+		reg.prefixes[keyPrefix] = true
+		return nil
+	}
+	err := reg.kvWatcher.Watch(reg.eventChan, keyPrefix)
+	if err != nil {
+		return err
+	}
+	reg.prefixes[keyPrefix] = true
+	return nil
+}
+
+// Unregister stops delivery of the events matching the given key prefix.
+func (reg *watchDataReg) Unregister(keyPrefix string) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if active, found := reg.prefixes[keyPrefix]; !found || !active {
+		return fmt.Errorf("key prefix %q is not registered by %s", keyPrefix, reg.watcherName)
+	}
+	reg.prefixes[keyPrefix] = false
+	return nil
+}
+
+// Close cancels the subscription.
+func (reg *watchDataReg) Close() error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.closed {
+		return nil
+	}
+	reg.closed = true
+	for prefix := range reg.prefixes {
+		reg.prefixes[prefix] = false
+	}
+	close(reg.closeChan)
+	return nil
+}
+
+// isActive checks whether the event with the given key should be delivered.
+func (reg *watchDataReg) isActive(key string) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for prefix, active := range reg.prefixes {
+		if active && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMiddleware runs the event through the middleware chain.
+func (reg *watchDataReg) applyMiddleware(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+	for _, middleware := range reg.middleware {
+		var deliver bool
+		resp, deliver = middleware(resp)
+		if !deliver {
+			return resp, false
+		}
+	}
+	return resp, true
+}
+
+// forwardEvents delivers the events of active prefixes to the watcher.
+func (reg *watchDataReg) forwardEvents() {
+	for {
+		select {
+		case <-reg.closeChan:
+			return
+		case resp := <-reg.eventChan:
+			resp, deliver := reg.applyMiddleware(resp)
+			if deliver && reg.isActive(resp.GetKey()) {
+				select {
+				case reg.respChan <- resp:
+				case <-reg.closeChan:
+					return
+				}
+			}
+		}
+	}
+}
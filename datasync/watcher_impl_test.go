@@ -0,0 +1,115 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datasync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/onsi/gomega"
+)
+
+// mockKvWatcher records subscribed keys and allows to emit events manually.
+type mockKvWatcher struct {
+	respChan chan keyval.ProtoWatchResp
+	keys     []string
+}
+
+func (m *mockKvWatcher) Watch(respChan chan keyval.ProtoWatchResp, key ...string) error {
+	m.respChan = respChan
+	m.keys = append(m.keys, key...)
+	return nil
+}
+
+// mockWatchResp is a minimal watch event carrying just a key.
+type mockWatchResp struct {
+	key string
+}
+
+func (m *mockWatchResp) GetKey() string                   { return m.key }
+func (m *mockWatchResp) GetValue(msg proto.Message) error { return nil }
+func (m *mockWatchResp) GetChangeType() db.PutDel         { return db.Put }
+func (m *mockWatchResp) GetRevision() int64               { return 0 }
+
+func TestWatchRegistration(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	kvWatcher := &mockKvWatcher{}
+	respChan := make(chan keyval.ProtoWatchResp, 10)
+
+	reg, err := NewWatcher(kvWatcher).WatchData("testWatcher", respChan, "/abc/", "/def/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(kvWatcher.keys).To(gomega.ConsistOf("/abc/", "/def/"))
+
+	// events of registered prefixes are delivered
+	kvWatcher.respChan <- &mockWatchResp{key: "/abc/1"}
+	gomega.Eventually(respChan).Should(gomega.Receive())
+
+	// events of unregistered prefixes are filtered out
+	err = reg.Unregister("/abc/")
+	gomega.Expect(err).To(gomega.BeNil())
+	kvWatcher.respChan <- &mockWatchResp{key: "/abc/2"}
+	kvWatcher.respChan <- &mockWatchResp{key: "/def/1"}
+	var resp keyval.ProtoWatchResp
+	gomega.Eventually(respChan).Should(gomega.Receive(&resp))
+	gomega.Expect(resp.GetKey()).To(gomega.BeEquivalentTo("/def/1"))
+
+	// re-registration does not create a duplicate keyval subscription
+	err = reg.Register("/abc/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(kvWatcher.keys).To(gomega.HaveLen(2))
+
+	// duplicate registration is reported
+	err = reg.Register("/abc/")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// no events are delivered once the registration is closed
+	err = reg.Close()
+	gomega.Expect(err).To(gomega.BeNil())
+	go func() { kvWatcher.respChan <- &mockWatchResp{key: "/def/2"} }()
+	gomega.Consistently(respChan).ShouldNot(gomega.Receive())
+}
+
+func TestWatchMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	kvWatcher := &mockKvWatcher{}
+	respChan := make(chan keyval.ProtoWatchResp, 10)
+
+	// middleware translating a deprecated key prefix and filtering out one key
+	translate := func(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+		if strings.HasPrefix(resp.GetKey(), "/old/") {
+			return WithKey(resp, "/new/"+strings.TrimPrefix(resp.GetKey(), "/old/")), true
+		}
+		return resp, true
+	}
+	filter := func(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+		return resp, resp.GetKey() != "/new/skipped"
+	}
+
+	_, err := NewWatcher(kvWatcher, translate, filter).WatchData("testWatcher", respChan, "/new/", "/old/")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	kvWatcher.respChan <- &mockWatchResp{key: "/old/skipped"}
+	kvWatcher.respChan <- &mockWatchResp{key: "/old/1"}
+
+	var resp keyval.ProtoWatchResp
+	gomega.Eventually(respChan).Should(gomega.Receive(&resp))
+	gomega.Expect(resp.GetKey()).To(gomega.BeEquivalentTo("/new/1"))
+	gomega.Consistently(respChan).ShouldNot(gomega.Receive())
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// Backup writes a consistent snapshot of the database into w while the
+// broker stays fully operational. The number of written bytes is
+// returned.
+func (broker *BytesBrokerBolt) Backup(w io.Writer) (int64, error) {
+	broker.dbMu.RLock()
+	defer broker.dbMu.RUnlock()
+
+	var written int64
+	err := broker.boltDB.View(func(tx *bolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(w)
+		return err
+	})
+	return written, err
+}
+
+// Compact copies the live pairs into a fresh database file and swaps it
+// in place of the current one, reclaiming the space of deleted pairs.
+// All broker operations are blocked for the whole duration of the
+// compaction, so that no write can slip in between the copy and the
+// swap and get lost.
+func (broker *BytesBrokerBolt) Compact() error {
+	broker.dbMu.Lock()
+	defer broker.dbMu.Unlock()
+
+	fpath := broker.boltDB.Path()
+	sizeBefore := fileSize(fpath)
+
+	fileMode := broker.config.FileMode
+	if fileMode == 0 {
+		fileMode = defaultFileMode
+	}
+	compactedPath := fpath + ".compacting"
+	compacted, err := bolt.Open(compactedPath, fileMode, nil)
+	if err != nil {
+		return err
+	}
+	err = compacted.Update(func(dstTx *bolt.Tx) error {
+		dstBucket, err := dstTx.CreateBucketIfNotExists(rootBucket)
+		if err != nil {
+			return err
+		}
+		return broker.boltDB.View(func(srcTx *bolt.Tx) error {
+			return srcTx.Bucket(rootBucket).ForEach(func(key, value []byte) error {
+				return dstBucket.Put(key, value)
+			})
+		})
+	})
+	if err == nil {
+		err = compacted.Close()
+	} else {
+		compacted.Close()
+	}
+	if err != nil {
+		os.Remove(compactedPath)
+		return err
+	}
+
+	if err = broker.boltDB.Close(); err != nil {
+		os.Remove(compactedPath)
+		return err
+	}
+	if err = os.Rename(compactedPath, fpath); err != nil {
+		os.Remove(compactedPath)
+		// reopen the original file, the broker must stay operational
+		broker.boltDB, _ = bolt.Open(fpath, fileMode, nil)
+		return err
+	}
+	broker.boltDB, err = bolt.Open(fpath, fileMode, nil)
+	if err != nil {
+		return err
+	}
+
+	broker.log.WithFields(logging.Fields{
+		"dbPath":     fpath,
+		"sizeBefore": sizeBefore,
+		"sizeAfter":  fileSize(fpath),
+	}).Info("Compacted the Bolt database")
+	return nil
+}
+
+// periodicCompact compacts the database with the configured period
+// until the broker is closed.
+func (broker *BytesBrokerBolt) periodicCompact() {
+	defer broker.wg.Done()
+	ticker := time.NewTicker(broker.config.CompactPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := broker.Compact(); err != nil {
+				broker.log.Error("Periodic compaction failed: ", err)
+			}
+		case <-broker.closeCh:
+			return
+		}
+	}
+}
+
+// fileSize returns the size of the file; zero when it cannot be
+// examined.
+func fileSize(fpath string) int64 {
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
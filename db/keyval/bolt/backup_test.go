@@ -0,0 +1,146 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// newTestBroker opens a broker over a temporary database file.
+func newTestBroker(t *testing.T) (broker *BytesBrokerBolt, closeFunc func()) {
+	dir, err := ioutil.TempDir("", "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker, err = NewBytesBroker(logrus.StandardLogger(), &Config{DbPath: path.Join(dir, "bolt.db")})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return broker, func() {
+		broker.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// TestBackup checks that the snapshot written by Backup is a valid
+// database holding the same pairs.
+func TestBackup(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	broker, closeFunc := newTestBroker(t)
+	defer closeFunc()
+
+	gomega.Expect(broker.Put("vnf/if/0", []byte("memif"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("vnf/if/1", []byte("veth"))).To(gomega.BeNil())
+
+	var snapshot bytes.Buffer
+	written, err := broker.Backup(&snapshot)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(written).To(gomega.BeEquivalentTo(snapshot.Len()))
+
+	// open the snapshot as a database and verify the content
+	dir, err := ioutil.TempDir("", "bolt")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+	restoredPath := path.Join(dir, "restored.db")
+	gomega.Expect(ioutil.WriteFile(restoredPath, snapshot.Bytes(), 0600)).To(gomega.BeNil())
+	restored, err := NewBytesBroker(logrus.StandardLogger(), &Config{DbPath: restoredPath})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer restored.Close()
+
+	data, found, _, err := restored.GetValue("vnf/if/0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.BeEquivalentTo("memif"))
+}
+
+// TestCompact checks that compaction shrinks the file after deletes and
+// keeps the live pairs readable.
+func TestCompact(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	broker, closeFunc := newTestBroker(t)
+	defer closeFunc()
+
+	payload := bytes.Repeat([]byte("x"), 4096)
+	for i := 0; i < 64; i++ {
+		key := "garbage/" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		gomega.Expect(broker.Put(key, payload)).To(gomega.BeNil())
+	}
+	gomega.Expect(broker.Put("vnf/if/0", []byte("memif"))).To(gomega.BeNil())
+	iterator, err := broker.ListKeys("garbage/")
+	gomega.Expect(err).To(gomega.BeNil())
+	for {
+		key, _, allReceived := iterator.GetNext()
+		if allReceived {
+			break
+		}
+		_, err = broker.Delete(key)
+		gomega.Expect(err).To(gomega.BeNil())
+	}
+
+	sizeBefore := fileSize(broker.config.DbPath)
+	gomega.Expect(broker.Compact()).To(gomega.BeNil())
+	gomega.Expect(fileSize(broker.config.DbPath)).To(gomega.BeNumerically("<", sizeBefore))
+
+	data, found, _, err := broker.GetValue("vnf/if/0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.BeEquivalentTo("memif"))
+}
+
+// TestBackupHandlers checks the REST endpoints streaming a snapshot and
+// triggering a compaction.
+func TestBackupHandlers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	broker, closeFunc := newTestBroker(t)
+	defer closeFunc()
+
+	gomega.Expect(broker.Put("vnf/if/0", []byte("memif"))).To(gomega.BeNil())
+
+	router := mux.NewRouter()
+	RegisterBackupHandlers(routerHandlers{router}, broker)
+
+	backupReq := httptest.NewRequest("GET", BackupPath, nil)
+	backupResp := httptest.NewRecorder()
+	router.ServeHTTP(backupResp, backupReq)
+	gomega.Expect(backupResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	gomega.Expect(backupResp.Body.Len()).To(gomega.BeNumerically(">", 0))
+
+	compactReq := httptest.NewRequest("POST", CompactPath, nil)
+	compactResp := httptest.NewRecorder()
+	router.ServeHTTP(compactResp, compactReq)
+	gomega.Expect(compactResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	gomega.Expect(compactResp.Body.String()).To(gomega.ContainSubstring("compacted"))
+}
+
+// routerHandlers adapts a plain mux router to the rest.HTTPHandlers
+// interface for the tests.
+type routerHandlers struct {
+	router *mux.Router
+}
+
+func (handlers routerHandlers) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	return handlers.router.Handle(path, handler).Methods(methods...)
+}
@@ -0,0 +1,453 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// rootBucket holds all the key-value pairs of the broker.
+var rootBucket = []byte("data")
+
+// BytesBrokerBolt implements the keyval.BytesBroker and
+// keyval.BytesWatcher interfaces on top of an embedded Bolt database.
+type BytesBrokerBolt struct {
+	log    logging.Logger
+	config *Config
+
+	// dbMu guards boltDB; compaction swaps the handle for a freshly
+	// compacted file while other operations hold the read lock.
+	dbMu   sync.RWMutex
+	boltDB *bolt.DB
+
+	// watchMu guards the watch subscriptions.
+	watchMu sync.Mutex
+	subs    []*subscription
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// subscription delivers the changes of the keys matching the prefix to
+// the channel; trim is removed from the keys of the responses.
+type subscription struct {
+	prefix   string
+	trim     string
+	respChan chan keyval.BytesWatchResp
+}
+
+// NewBytesBroker opens (and creates when missing) the database file
+// configured in config. Periodic compaction is started when a compact
+// period is configured.
+func NewBytesBroker(log logging.Logger, config *Config) (*BytesBrokerBolt, error) {
+	fileMode := config.FileMode
+	if fileMode == 0 {
+		fileMode = defaultFileMode
+	}
+	boltDB, err := bolt.Open(config.DbPath, fileMode, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = boltDB.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(rootBucket)
+		return err
+	})
+	if err != nil {
+		boltDB.Close()
+		return nil, err
+	}
+	broker := &BytesBrokerBolt{
+		log:     log,
+		config:  config,
+		boltDB:  boltDB,
+		closeCh: make(chan struct{}),
+	}
+	if config.CompactPeriod > 0 {
+		broker.wg.Add(1)
+		go broker.periodicCompact()
+	}
+	return broker, nil
+}
+
+// Close stops the periodic compaction and closes the database file.
+func (broker *BytesBrokerBolt) Close() error {
+	close(broker.closeCh)
+	broker.wg.Wait()
+
+	broker.dbMu.Lock()
+	defer broker.dbMu.Unlock()
+	return broker.boltDB.Close()
+}
+
+// NewPluginBroker creates a broker that prepends the given prefix to
+// all keys in its calls and trims it from the returned keys.
+func (broker *BytesBrokerBolt) NewPluginBroker(prefix string) *BytesPluginBrokerBolt {
+	return &BytesPluginBrokerBolt{broker: broker, prefix: prefix}
+}
+
+// Put stores the value under the key.
+func (broker *BytesBrokerBolt) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	broker.dbMu.RLock()
+	err := broker.boltDB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(rootBucket).Put([]byte(key), data)
+	})
+	broker.dbMu.RUnlock()
+	if err != nil {
+		broker.log.Error("bolt put error: ", err)
+		return err
+	}
+	broker.notify(key, data, db.Put)
+	return nil
+}
+
+// GetValue retrieves the value of the key. Bolt does not version the
+// values, the returned revision is always zero.
+func (broker *BytesBrokerBolt) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	broker.dbMu.RLock()
+	defer broker.dbMu.RUnlock()
+	err = broker.boltDB.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(rootBucket).Get([]byte(key)); value != nil {
+			data = append([]byte(nil), value...)
+			found = true
+		}
+		return nil
+	})
+	return data, found, 0, err
+}
+
+// ListKeys returns an iterator over the keys matching the prefix,
+// sorted lexicographically.
+func (broker *BytesBrokerBolt) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	pairs, err := broker.list(prefix, false)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		keys = append(keys, pair.key)
+	}
+	return &bytesKeyIterator{keys: keys}, nil
+}
+
+// ListValues returns an iterator over the key-value pairs matching the
+// prefix, sorted lexicographically by the key.
+func (broker *BytesBrokerBolt) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	pairs, err := broker.list(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	return &bytesKeyValIterator{pairs: pairs}, nil
+}
+
+// list collects the pairs matching the prefix; the values are fetched
+// only when withValues is set.
+func (broker *BytesBrokerBolt) list(prefix string, withValues bool) ([]bytesKeyVal, error) {
+	broker.dbMu.RLock()
+	defer broker.dbMu.RUnlock()
+	var pairs []bytesKeyVal
+	err := broker.boltDB.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(rootBucket).Cursor()
+		bytesPrefix := []byte(prefix)
+		for key, value := cursor.Seek(bytesPrefix); key != nil && bytes.HasPrefix(key, bytesPrefix); key, value = cursor.Next() {
+			pair := bytesKeyVal{key: string(key)}
+			if withValues {
+				pair.value = append([]byte(nil), value...)
+			}
+			pairs = append(pairs, pair)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// Delete removes the key. The returned flag reports whether the key
+// existed.
+func (broker *BytesBrokerBolt) Delete(key string) (existed bool, err error) {
+	broker.dbMu.RLock()
+	err = broker.boltDB.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rootBucket)
+		if bucket.Get([]byte(key)) == nil {
+			return nil
+		}
+		existed = true
+		return bucket.Delete([]byte(key))
+	})
+	broker.dbMu.RUnlock()
+	if err != nil {
+		broker.log.Error("bolt delete error: ", err)
+		return false, err
+	}
+	if existed {
+		broker.notify(key, nil, db.Delete)
+	}
+	return existed, nil
+}
+
+// NewTxn creates a transaction. The operations are buffered and applied
+// together in a single Bolt transaction by Commit.
+func (broker *BytesBrokerBolt) NewTxn() keyval.BytesTxn {
+	return &bytesTxn{broker: broker}
+}
+
+// Watch starts the delivery of the changes of the keys matching the
+// given prefixes to respChan. The responses are delivered synchronously
+// from the writing goroutine, use a buffered channel.
+func (broker *BytesBrokerBolt) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	for _, prefix := range keys {
+		broker.watchPrefix(respChan, prefix, "")
+	}
+	return nil
+}
+
+// watchPrefix registers a subscription for one prefix. The trimPrefix is
+// removed from the keys of the delivered responses.
+func (broker *BytesBrokerBolt) watchPrefix(respChan chan keyval.BytesWatchResp, prefix string, trimPrefix string) {
+	broker.watchMu.Lock()
+	defer broker.watchMu.Unlock()
+	broker.subs = append(broker.subs, &subscription{prefix: prefix, trim: trimPrefix, respChan: respChan})
+}
+
+// notify delivers the change to the subscriptions matching the key.
+func (broker *BytesBrokerBolt) notify(key string, data []byte, op db.PutDel) {
+	broker.watchMu.Lock()
+	var receivers []*subscription
+	for _, sub := range broker.subs {
+		if strings.HasPrefix(key, sub.prefix) {
+			receivers = append(receivers, sub)
+		}
+	}
+	broker.watchMu.Unlock()
+
+	for _, sub := range receivers {
+		sub.respChan <- &bytesWatchResp{
+			key:        strings.TrimPrefix(key, sub.trim),
+			value:      data,
+			changeType: op,
+		}
+	}
+}
+
+// BytesPluginBrokerBolt is a wrapper of BytesBrokerBolt that prepends
+// a prefix to all keys in its calls and trims it from the returned keys.
+type BytesPluginBrokerBolt struct {
+	broker *BytesBrokerBolt
+	prefix string
+}
+
+// Put stores the value under the prefixed key.
+func (pdb *BytesPluginBrokerBolt) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	return pdb.broker.Put(pdb.prefix+key, data, opts...)
+}
+
+// GetValue retrieves the value of the prefixed key.
+func (pdb *BytesPluginBrokerBolt) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	return pdb.broker.GetValue(pdb.prefix + key)
+}
+
+// ListKeys returns an iterator over the matching keys with the broker
+// prefix trimmed.
+func (pdb *BytesPluginBrokerBolt) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	iterator, err := pdb.broker.ListKeys(pdb.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	keyIterator := iterator.(*bytesKeyIterator)
+	for i, key := range keyIterator.keys {
+		keyIterator.keys[i] = strings.TrimPrefix(key, pdb.prefix)
+	}
+	return keyIterator, nil
+}
+
+// ListValues returns an iterator over the matching key-value pairs with
+// the broker prefix trimmed from the keys.
+func (pdb *BytesPluginBrokerBolt) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	iterator, err := pdb.broker.ListValues(pdb.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	kvIterator := iterator.(*bytesKeyValIterator)
+	for i := range kvIterator.pairs {
+		kvIterator.pairs[i].key = strings.TrimPrefix(kvIterator.pairs[i].key, pdb.prefix)
+	}
+	return kvIterator, nil
+}
+
+// Delete removes the prefixed key.
+func (pdb *BytesPluginBrokerBolt) Delete(key string) (bool, error) {
+	return pdb.broker.Delete(pdb.prefix + key)
+}
+
+// NewTxn creates a transaction operating on the prefixed keys.
+func (pdb *BytesPluginBrokerBolt) NewTxn() keyval.BytesTxn {
+	return &bytesTxn{broker: pdb.broker, prefix: pdb.prefix}
+}
+
+// Watch starts the delivery of the changes of the prefixed keys to
+// respChan; the broker prefix is trimmed from the keys of the responses.
+func (pdb *BytesPluginBrokerBolt) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	for _, prefix := range keys {
+		pdb.broker.watchPrefix(respChan, pdb.prefix+prefix, pdb.prefix)
+	}
+	return nil
+}
+
+// txnOp is one buffered operation of a transaction.
+type txnOp struct {
+	key  string
+	data []byte
+	del  bool
+}
+
+// bytesTxn buffers the operations until Commit.
+type bytesTxn struct {
+	broker *BytesBrokerBolt
+	prefix string
+	ops    []txnOp
+}
+
+// Put adds a store operation into the transaction.
+func (txn *bytesTxn) Put(key string, data []byte) keyval.BytesTxn {
+	txn.ops = append(txn.ops, txnOp{key: txn.prefix + key, data: data})
+	return txn
+}
+
+// Delete adds a delete operation into the transaction.
+func (txn *bytesTxn) Delete(key string) keyval.BytesTxn {
+	txn.ops = append(txn.ops, txnOp{key: txn.prefix + key, del: true})
+	return txn
+}
+
+// Commit applies the buffered operations in a single Bolt transaction.
+func (txn *bytesTxn) Commit() error {
+	broker := txn.broker
+	broker.dbMu.RLock()
+	err := broker.boltDB.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rootBucket)
+		for _, op := range txn.ops {
+			if op.del {
+				if err := bucket.Delete([]byte(op.key)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put([]byte(op.key), op.data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	broker.dbMu.RUnlock()
+	if err != nil {
+		return err
+	}
+	for _, op := range txn.ops {
+		if op.del {
+			broker.notify(op.key, nil, db.Delete)
+		} else {
+			broker.notify(op.key, op.data, db.Put)
+		}
+	}
+	return nil
+}
+
+// bytesKeyVal is one listed key-value pair.
+type bytesKeyVal struct {
+	key   string
+	value []byte
+}
+
+// GetKey returns the key of the pair.
+func (kv bytesKeyVal) GetKey() string {
+	return kv.key
+}
+
+// GetValue returns the value of the pair.
+func (kv bytesKeyVal) GetValue() []byte {
+	return kv.value
+}
+
+// GetRevision returns zero, Bolt does not version the values.
+func (kv bytesKeyVal) GetRevision() int64 {
+	return 0
+}
+
+// bytesKeyValIterator iterates over listed key-value pairs.
+type bytesKeyValIterator struct {
+	pairs []bytesKeyVal
+	index int
+}
+
+// GetNext retrieves the following item from the iterator.
+func (it *bytesKeyValIterator) GetNext() (keyval.BytesKeyVal, bool) {
+	if it.index >= len(it.pairs) {
+		return nil, true
+	}
+	kv := it.pairs[it.index]
+	it.index++
+	return kv, false
+}
+
+// bytesKeyIterator iterates over listed keys.
+type bytesKeyIterator struct {
+	keys  []string
+	index int
+}
+
+// GetNext retrieves the following key from the iterator.
+func (it *bytesKeyIterator) GetNext() (string, int64, bool) {
+	if it.index >= len(it.keys) {
+		return "", 0, true
+	}
+	key := it.keys[it.index]
+	it.index++
+	return key, 0, false
+}
+
+// bytesWatchResp is a notification about a change of one key.
+type bytesWatchResp struct {
+	key        string
+	value      []byte
+	changeType db.PutDel
+}
+
+// GetKey returns the key the change is about.
+func (resp *bytesWatchResp) GetKey() string {
+	return resp.key
+}
+
+// GetValue returns the changed value; nil for a delete.
+func (resp *bytesWatchResp) GetValue() []byte {
+	return resp.value
+}
+
+// GetChangeType distinguishes a put from a delete.
+func (resp *bytesWatchResp) GetChangeType() db.PutDel {
+	return resp.changeType
+}
+
+// GetRevision returns zero, Bolt does not version the values.
+func (resp *bytesWatchResp) GetRevision() int64 {
+	return 0
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the Bolt database.
+type Config struct {
+	// DbPath is the path of the database file.
+	DbPath string `json:"db-path"`
+	// FileMode is the permission of the database file; 0600 when zero.
+	FileMode os.FileMode `json:"file-mode"`
+	// CompactPeriod turns on periodic compaction of the database file
+	// when non-zero.
+	CompactPeriod time.Duration `json:"compact-period"`
+}
+
+// defaultFileMode is the permission of the database file when none is
+// configured.
+const defaultFileMode os.FileMode = 0600
+
+// ConfigFromFile loads the Bolt configuration from the specified file.
+// If the file is valid and contains valid configuration, the parsed
+// configuration is returned; otherwise, an error is returned.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	if config.FileMode == 0 {
+		config.FileMode = defaultFileMode
+	}
+	return config, nil
+}
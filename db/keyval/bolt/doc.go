@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bolt implements the broker and watcher interfaces of the
+// keyval package on top of an embedded Bolt database file. It is meant
+// for appliance deployments that keep the agent state locally instead
+// of in a remote data store. The broker supports online backup into an
+// io.Writer and compaction into a fresh file, both without stopping
+// the agent.
+package bolt
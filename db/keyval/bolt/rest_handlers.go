@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the Bolt maintenance endpoints.
+const (
+	BackupPath  = "/db/bolt/backup"
+	CompactPath = "/db/bolt/compact"
+)
+
+// RegisterBackupHandlers exposes the maintenance endpoints of the
+// broker: download of a consistent snapshot and trigger of an immediate
+// compaction.
+func RegisterBackupHandlers(handlers rest.HTTPHandlers, broker *BytesBrokerBolt) {
+	handlers.RegisterHTTPHandler(BackupPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="bolt.db"`)
+			if _, err := broker.Backup(w); err != nil {
+				// the response may be partially written already, the
+				// error can only be logged
+				broker.log.Error("Backup streaming failed: ", err)
+			}
+		}), "GET")
+
+	handlers.RegisterHTTPHandler(CompactPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := broker.Compact(); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"status": "compacted"})
+		}), "POST")
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ligato/cn-infra/db/keyval/keyvaltest"
+	"github.com/ligato/cn-infra/logging/logrus"
+)
+
+func TestConformance(t *testing.T) {
+	keyvaltest.RunSuite(t, func(t *testing.T) (keyvaltest.Broker, func()) {
+		dir, err := ioutil.TempDir("", "bolt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		broker, err := NewBytesBroker(logrus.StandardLogger(), &Config{DbPath: path.Join(dir, "bolt.db")})
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+		return broker, func() {
+			broker.Close()
+			os.RemoveAll(dir)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browser
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// Browser reads the key-value store of the agent and decodes the stored
+// values through the registered proto types.
+type Browser struct {
+	log        logging.Logger
+	broker     keyval.BytesBroker
+	serializer keyval.Serializer
+
+	mu       sync.RWMutex
+	prefixes []string
+	types    map[string]func() proto.Message
+}
+
+// Entry is one dumped key with its decoded value. Value is nil when no
+// registered type matches the key or the data does not unmarshal; Raw
+// then carries the stored bytes instead.
+type Entry struct {
+	Key string `json:"key"`
+	// Value is the decoded proto message of the key.
+	Value proto.Message `json:"value,omitempty"`
+	// Raw is the stored data of an undecodable key (base64 in JSON).
+	Raw []byte `json:"raw,omitempty"`
+	// Revision of the stored value.
+	Revision int64 `json:"revision"`
+}
+
+// NewBrowser creates a browser over the broker. The values are decoded
+// with the JSON serializer by default, matching the format the proto
+// brokers of this repository store by default.
+func NewBrowser(log logging.Logger, broker keyval.BytesBroker) *Browser {
+	return &Browser{
+		log:        log,
+		broker:     broker,
+		serializer: &keyval.SerializerJSON{},
+		types:      map[string]func() proto.Message{},
+	}
+}
+
+// NewBrowserUsingSerializer creates a browser decoding the stored data
+// with the given serializer.
+func NewBrowserUsingSerializer(log logging.Logger, broker keyval.BytesBroker, serializer keyval.Serializer) *Browser {
+	browser := NewBrowser(log, broker)
+	browser.serializer = serializer
+	return browser
+}
+
+// RegisterType maps the keys under the prefix to the proto messages the
+// factory creates. When multiple registered prefixes match a key, the
+// longest one wins.
+func (browser *Browser) RegisterType(prefix string, factory func() proto.Message) {
+	browser.mu.Lock()
+	defer browser.mu.Unlock()
+	if _, known := browser.types[prefix]; !known {
+		browser.prefixes = append(browser.prefixes, prefix)
+	}
+	browser.types[prefix] = factory
+}
+
+// ListKeys returns the keys under the prefix.
+func (browser *Browser) ListKeys(prefix string) ([]string, error) {
+	iterator, err := browser.broker.ListKeys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for {
+		key, _, allReceived := iterator.GetNext()
+		if allReceived {
+			return keys, nil
+		}
+		keys = append(keys, key)
+	}
+}
+
+// Dump returns the entries under the prefix with the values decoded
+// through the registered types.
+func (browser *Browser) Dump(prefix string) ([]Entry, error) {
+	iterator, err := browser.broker.ListValues(prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for {
+		kv, allReceived := iterator.GetNext()
+		if allReceived {
+			return entries, nil
+		}
+		entry := Entry{Key: kv.GetKey(), Revision: kv.GetRevision()}
+		if message := browser.decode(kv.GetKey(), kv.GetValue()); message != nil {
+			entry.Value = message
+		} else {
+			entry.Raw = kv.GetValue()
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// decode unmarshals the data of the key through the longest matching
+// registered prefix, nil when there is none or the data does not fit.
+func (browser *Browser) decode(key string, data []byte) proto.Message {
+	factory := browser.factoryFor(key)
+	if factory == nil {
+		return nil
+	}
+	message := factory()
+	if err := browser.serializer.Unmarshal(data, message); err != nil {
+		browser.log.WithFields(logging.Fields{"key": key, "err": err}).
+			Warn("Stored data does not unmarshal into the registered type")
+		return nil
+	}
+	return message
+}
+
+// factoryFor returns the factory of the longest registered prefix
+// matching the key.
+func (browser *Browser) factoryFor(key string) func() proto.Message {
+	browser.mu.RLock()
+	defer browser.mu.RUnlock()
+	var best string
+	var factory func() proto.Message
+	for _, prefix := range browser.prefixes {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix && len(prefix) >= len(best) {
+			best = prefix
+			factory = browser.types[prefix]
+		}
+	}
+	return factory
+}
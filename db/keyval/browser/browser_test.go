@@ -0,0 +1,157 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browser
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/datasync/datasynctest"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// ifConfig is a minimal proto message registered for the interface keys.
+type ifConfig struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	MTU  int32  `protobuf:"varint,2,opt,name=mtu" json:"mtu,omitempty"`
+}
+
+func (m *ifConfig) Reset()         { *m = ifConfig{} }
+func (m *ifConfig) String() string { return proto.CompactTextString(m) }
+func (*ifConfig) ProtoMessage()    {}
+
+// newTestBrowser builds a browser over an in-memory broker prefilled
+// with two decodable interface keys and one unregistered key.
+func newTestBrowser(t *testing.T) *Browser {
+	broker := datasynctest.NewMemBroker()
+	gomega.Expect(broker.Put("/agent/if/memif0", []byte(`{"name": "memif0", "mtu": 1500}`))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/agent/if/memif1", []byte(`{"name": "memif1"}`))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/agent/route/default", []byte("opaque"))).To(gomega.BeNil())
+
+	browser := NewBrowser(logrus.StandardLogger(), broker)
+	browser.RegisterType("/agent/if/", func() proto.Message { return &ifConfig{} })
+	return browser
+}
+
+// TestListKeys checks the listing of the keys under a prefix.
+func TestListKeys(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	browser := newTestBrowser(t)
+
+	keys, err := browser.ListKeys("/agent/if/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(keys).To(gomega.Equal([]string{"/agent/if/memif0", "/agent/if/memif1"}))
+
+	keys, err = browser.ListKeys("/missing/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(keys).To(gomega.BeEmpty())
+}
+
+// TestDump checks that registered keys come back decoded and the others
+// carry the raw data.
+func TestDump(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	browser := newTestBrowser(t)
+
+	entries, err := browser.Dump("/agent/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(entries).To(gomega.HaveLen(3))
+
+	gomega.Expect(entries[0].Key).To(gomega.BeEquivalentTo("/agent/if/memif0"))
+	gomega.Expect(entries[0].Value).To(gomega.Equal(&ifConfig{Name: "memif0", MTU: 1500}))
+	gomega.Expect(entries[0].Raw).To(gomega.BeNil())
+
+	gomega.Expect(entries[2].Key).To(gomega.BeEquivalentTo("/agent/route/default"))
+	gomega.Expect(entries[2].Value).To(gomega.BeNil())
+	gomega.Expect(entries[2].Raw).To(gomega.BeEquivalentTo([]byte("opaque")))
+}
+
+// TestDumpUndecodableValue checks the fallback to the raw data when the
+// stored bytes do not unmarshal into the registered type.
+func TestDumpUndecodableValue(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	browser := newTestBrowser(t)
+
+	broker := datasynctest.NewMemBroker()
+	gomega.Expect(broker.Put("/agent/if/bad", []byte("not json"))).To(gomega.BeNil())
+	browser.broker = broker
+
+	entries, err := browser.Dump("/agent/if/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(entries).To(gomega.HaveLen(1))
+	gomega.Expect(entries[0].Value).To(gomega.BeNil())
+	gomega.Expect(entries[0].Raw).To(gomega.BeEquivalentTo([]byte("not json")))
+}
+
+// TestLongestPrefixWins checks that the most specific registered prefix
+// decodes the key.
+func TestLongestPrefixWins(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	gomega.Expect(broker.Put("/agent/if/memif0", []byte(`{"name": "memif0"}`))).To(gomega.BeNil())
+
+	browser := NewBrowser(logrus.StandardLogger(), broker)
+	browser.RegisterType("/agent/", func() proto.Message { return nil })
+	browser.RegisterType("/agent/if/", func() proto.Message { return &ifConfig{} })
+
+	entries, err := browser.Dump("/agent/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(entries[0].Value).To(gomega.Equal(&ifConfig{Name: "memif0"}))
+}
+
+// TestBrowserHandlers checks the REST endpoints of the browser.
+func TestBrowserHandlers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	browser := newTestBrowser(t)
+
+	router := mux.NewRouter()
+	RegisterBrowserHandlers(routerHandlers{router}, browser)
+
+	keysReq := httptest.NewRequest("GET", KeysPath+"?prefix=/agent/if/", nil)
+	keysResp := httptest.NewRecorder()
+	router.ServeHTTP(keysResp, keysReq)
+	gomega.Expect(keysResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var keys []string
+	gomega.Expect(json.Unmarshal(keysResp.Body.Bytes(), &keys)).To(gomega.BeNil())
+	gomega.Expect(keys).To(gomega.Equal([]string{"/agent/if/memif0", "/agent/if/memif1"}))
+
+	dumpReq := httptest.NewRequest("GET", DumpPath+"?prefix=/agent/if/", nil)
+	dumpResp := httptest.NewRecorder()
+	router.ServeHTTP(dumpResp, dumpReq)
+	gomega.Expect(dumpResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var entries []struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	}
+	gomega.Expect(json.Unmarshal(dumpResp.Body.Bytes(), &entries)).To(gomega.BeNil())
+	gomega.Expect(entries).To(gomega.HaveLen(2))
+	gomega.Expect(entries[0].Value["name"]).To(gomega.BeEquivalentTo("memif0"))
+}
+
+// routerHandlers adapts a plain mux router to the rest.HTTPHandlers
+// interface for the tests.
+type routerHandlers struct {
+	router *mux.Router
+}
+
+func (handlers routerHandlers) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	return handlers.router.Handle(path, handler).Methods(methods...)
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package browser exposes management REST endpoints for inspecting the
+// key-value store of the agent: listing the keys under a prefix and
+// dumping the stored values decoded through a pluggable key-to-proto
+// type registry. Support engineers can see what configuration the agent
+// holds without direct access to the backing database.
+package browser
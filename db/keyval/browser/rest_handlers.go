@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browser
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the browser endpoints. Both take the inspected prefix in the
+// "prefix" query parameter.
+const (
+	KeysPath = "/db/keys"
+	DumpPath = "/db/dump"
+)
+
+// RegisterBrowserHandlers exposes the inspection endpoints of the
+// browser: the key listing and the decoded dump.
+func RegisterBrowserHandlers(handlers rest.HTTPHandlers, browser *Browser) {
+	handlers.RegisterHTTPHandler(KeysPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			keys, err := browser.ListKeys(req.URL.Query().Get("prefix"))
+			respond(w, keys, err)
+		}), "GET")
+
+	handlers.RegisterHTTPHandler(DumpPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			entries, err := browser.Dump(req.URL.Query().Get("prefix"))
+			respond(w, entries, err)
+		}), "GET")
+}
+
+// respond writes the result or the error as JSON.
+func respond(w http.ResponseWriter, result interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyval
+
+import (
+	"fmt"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// AtomicTxnParticipant is implemented by brokers that are able to batch
+// the writes of multiple sibling brokers sharing the same underlying
+// connection into a single backend transaction (e.g. plugin brokers of
+// one etcd connection).
+type AtomicTxnParticipant interface {
+	// TxnHandle identifies the underlying connection. Brokers returning
+	// the same non-nil handle can share one backend transaction.
+	TxnHandle() interface{}
+	// NewConnectionTxn creates a transaction bound to the underlying
+	// connection, without the broker prefix being prepended to the keys.
+	NewConnectionTxn() BytesTxn
+	// PrefixKey returns the key as stored in the data store, i.e. with
+	// the broker prefix prepended.
+	PrefixKey(key string) string
+}
+
+// compositeOp is a single operation of a composite transaction together
+// with the broker it is destined for.
+type compositeOp struct {
+	broker BytesBroker
+	key    string
+	data   []byte
+	del    bool
+}
+
+// CompositeTxn batches writes destined for multiple brokers (typically
+// plugin brokers with different prefixes). When all the target brokers
+// implement AtomicTxnParticipant and share the same connection, the
+// operations are committed atomically in a single backend transaction.
+// Otherwise the commit falls back to best-effort ordered writes, one
+// transaction per broker; registered rollback hooks are invoked when
+// the fallback fails after some of the brokers have already committed.
+type CompositeTxn struct {
+	log       logging.Logger
+	ops       []compositeOp
+	rollbacks []func()
+}
+
+// NewCompositeTxn creates an empty composite transaction.
+func NewCompositeTxn(log logging.Logger) *CompositeTxn {
+	return &CompositeTxn{log: log}
+}
+
+// Put adds a store operation destined for the given broker into the
+// transaction.
+func (txn *CompositeTxn) Put(broker BytesBroker, key string, data []byte) *CompositeTxn {
+	txn.ops = append(txn.ops, compositeOp{broker: broker, key: key, data: data})
+	return txn
+}
+
+// Delete adds a delete operation destined for the given broker into the
+// transaction.
+func (txn *CompositeTxn) Delete(broker BytesBroker, key string) *CompositeTxn {
+	txn.ops = append(txn.ops, compositeOp{broker: broker, key: key, del: true})
+	return txn
+}
+
+// OnRollback registers a hook invoked when the best-effort commit fails
+// after some of the brokers have already committed. The hooks are run in
+// the reverse registration order. The hooks are not invoked on the
+// atomic path - a failed backend transaction leaves the data store
+// untouched.
+func (txn *CompositeTxn) OnRollback(hook func()) *CompositeTxn {
+	txn.rollbacks = append(txn.rollbacks, hook)
+	return txn
+}
+
+// Commit commits the operations to the data store, atomically when the
+// target brokers share one connection supporting transactions.
+func (txn *CompositeTxn) Commit() error {
+	if len(txn.ops) == 0 {
+		return nil
+	}
+	if participant, ok := txn.coalesce(); ok {
+		return txn.commitAtomic(participant)
+	}
+	return txn.commitOrdered()
+}
+
+// coalesce returns a participant whose connection can commit all the
+// operations in a single backend transaction, or false when the brokers
+// do not share one such connection.
+func (txn *CompositeTxn) coalesce() (AtomicTxnParticipant, bool) {
+	var first AtomicTxnParticipant
+	for _, op := range txn.ops {
+		participant, ok := op.broker.(AtomicTxnParticipant)
+		if !ok || participant.TxnHandle() == nil {
+			return nil, false
+		}
+		if first == nil {
+			first = participant
+		} else if participant.TxnHandle() != first.TxnHandle() {
+			return nil, false
+		}
+	}
+	return first, true
+}
+
+// commitAtomic commits all the operations in a single backend
+// transaction of the shared connection.
+func (txn *CompositeTxn) commitAtomic(participant AtomicTxnParticipant) error {
+	backendTxn := participant.NewConnectionTxn()
+	for _, op := range txn.ops {
+		key := op.broker.(AtomicTxnParticipant).PrefixKey(op.key)
+		if op.del {
+			backendTxn.Delete(key)
+		} else {
+			backendTxn.Put(key, op.data)
+		}
+	}
+	return backendTxn.Commit()
+}
+
+// commitOrdered commits the operations broker by broker, in the order
+// the brokers first appear in the transaction, one transaction per
+// broker. On failure the rollback hooks are invoked and an error
+// naming the already committed brokers is returned.
+func (txn *CompositeTxn) commitOrdered() error {
+	var brokers []BytesBroker
+	perBroker := map[BytesBroker]BytesTxn{}
+	for _, op := range txn.ops {
+		brokerTxn, ok := perBroker[op.broker]
+		if !ok {
+			brokerTxn = op.broker.NewTxn()
+			perBroker[op.broker] = brokerTxn
+			brokers = append(brokers, op.broker)
+		}
+		if op.del {
+			brokerTxn.Delete(op.key)
+		} else {
+			brokerTxn.Put(op.key, op.data)
+		}
+	}
+
+	for index, broker := range brokers {
+		if err := perBroker[broker].Commit(); err != nil {
+			if index > 0 {
+				txn.log.WithFields(logging.Fields{
+					"committed": index,
+					"brokers":   len(brokers),
+				}).Warn("Composite transaction failed partway, running rollback hooks: ", err)
+				txn.rollback()
+			}
+			return fmt.Errorf("composite transaction failed after committing %d of %d brokers: %s",
+				index, len(brokers), err)
+		}
+	}
+	return nil
+}
+
+// rollback runs the registered hooks in the reverse registration order.
+func (txn *CompositeTxn) rollback() {
+	for index := len(txn.rollbacks) - 1; index >= 0; index-- {
+		txn.rollbacks[index]()
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeConnection is a shared backend of the fake participant brokers. It
+// counts the committed backend transactions, so the tests can assert that
+// the composite transaction was coalesced into a single one.
+type fakeConnection struct {
+	data       map[string][]byte
+	txnCommits int
+	err        error
+}
+
+func newFakeConnection() *fakeConnection {
+	return &fakeConnection{data: map[string][]byte{}}
+}
+
+// fakeTxn buffers the operations and applies them to the connection data
+// on Commit.
+type fakeTxn struct {
+	conn     *fakeConnection
+	prefix   string
+	puts     map[string][]byte
+	deletes  []string
+	onCommit func()
+}
+
+func (tx *fakeTxn) Put(key string, data []byte) BytesTxn {
+	if tx.puts == nil {
+		tx.puts = map[string][]byte{}
+	}
+	tx.puts[tx.prefix+key] = data
+	return tx
+}
+
+func (tx *fakeTxn) Delete(key string) BytesTxn {
+	tx.deletes = append(tx.deletes, tx.prefix+key)
+	return tx
+}
+
+func (tx *fakeTxn) Commit() error {
+	if tx.conn.err != nil {
+		return tx.conn.err
+	}
+	for key, data := range tx.puts {
+		tx.conn.data[key] = data
+	}
+	for _, key := range tx.deletes {
+		delete(tx.conn.data, key)
+	}
+	tx.conn.txnCommits++
+	if tx.onCommit != nil {
+		tx.onCommit()
+	}
+	return nil
+}
+
+// fakeBroker is a minimal BytesBroker over the fake connection. Only the
+// methods exercised by the composite transaction are functional.
+type fakeBroker struct {
+	conn   *fakeConnection
+	prefix string
+	// participant controls whether the broker advertises the shared
+	// connection for transaction coalescing.
+	participant bool
+	// onCommit is invoked after each successful per-broker commit.
+	onCommit func()
+}
+
+func (broker *fakeBroker) Put(key string, data []byte, opts ...PutOption) error {
+	broker.conn.data[broker.prefix+key] = data
+	return nil
+}
+
+func (broker *fakeBroker) NewTxn() BytesTxn {
+	return &fakeTxn{conn: broker.conn, prefix: broker.prefix, onCommit: broker.onCommit}
+}
+
+func (broker *fakeBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	data, found = broker.conn.data[broker.prefix+key]
+	return data, found, 0, nil
+}
+
+func (broker *fakeBroker) ListValues(key string) (BytesKeyValIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (broker *fakeBroker) ListKeys(prefix string) (BytesKeyIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (broker *fakeBroker) Delete(key string) (bool, error) {
+	delete(broker.conn.data, broker.prefix+key)
+	return true, nil
+}
+
+func (broker *fakeBroker) TxnHandle() interface{} {
+	if !broker.participant {
+		return nil
+	}
+	return broker.conn
+}
+
+func (broker *fakeBroker) NewConnectionTxn() BytesTxn {
+	return &fakeTxn{conn: broker.conn}
+}
+
+func (broker *fakeBroker) PrefixKey(key string) string {
+	return broker.prefix + key
+}
+
+// TestCompositeTxnAtomic checks that the writes of sibling brokers
+// sharing one connection are committed in a single backend transaction.
+func TestCompositeTxnAtomic(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	vpp := &fakeBroker{conn: conn, prefix: "vpp/", participant: true}
+	linux := &fakeBroker{conn: conn, prefix: "linux/", participant: true}
+
+	err := NewCompositeTxn(logrus.StandardLogger()).
+		Put(vpp, "if/memif0", []byte("memif")).
+		Put(linux, "if/veth0", []byte("veth")).
+		Delete(vpp, "if/obsolete").
+		Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(conn.txnCommits).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(conn.data["vpp/if/memif0"]).To(gomega.BeEquivalentTo("memif"))
+	gomega.Expect(conn.data["linux/if/veth0"]).To(gomega.BeEquivalentTo("veth"))
+}
+
+// TestCompositeTxnFallback checks that brokers of different connections
+// are committed one transaction per broker.
+func TestCompositeTxnFallback(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	connA := newFakeConnection()
+	connB := newFakeConnection()
+	first := &fakeBroker{conn: connA, prefix: "a/", participant: true}
+	second := &fakeBroker{conn: connB, prefix: "b/", participant: true}
+
+	err := NewCompositeTxn(logrus.StandardLogger()).
+		Put(first, "key", []byte("1")).
+		Put(second, "key", []byte("2")).
+		Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(connA.txnCommits).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(connB.txnCommits).To(gomega.BeEquivalentTo(1))
+	gomega.Expect(connA.data["a/key"]).To(gomega.BeEquivalentTo("1"))
+	gomega.Expect(connB.data["b/key"]).To(gomega.BeEquivalentTo("2"))
+}
+
+// TestCompositeTxnNonParticipant checks that a broker not advertising a
+// shared connection forces the per-broker fallback even for siblings.
+func TestCompositeTxnNonParticipant(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	participant := &fakeBroker{conn: conn, prefix: "a/", participant: true}
+	plain := &fakeBroker{conn: conn, prefix: "b/", participant: false}
+
+	err := NewCompositeTxn(logrus.StandardLogger()).
+		Put(participant, "key", []byte("1")).
+		Put(plain, "key", []byte("2")).
+		Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(conn.txnCommits).To(gomega.BeEquivalentTo(2))
+}
+
+// TestCompositeTxnRollbackHooks checks that a fallback commit failing
+// after the first broker runs the rollback hooks in the reverse order.
+func TestCompositeTxnRollbackHooks(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	connA := newFakeConnection()
+	connB := newFakeConnection()
+	first := &fakeBroker{conn: connA, prefix: "a/", participant: true}
+	second := &fakeBroker{conn: connB, prefix: "b/", participant: true}
+	// the second connection rejects the commit after the first broker
+	// has already committed
+	first.onCommit = func() {
+		connB.err = errors.New("connection lost")
+	}
+
+	var rollbacks []string
+	err := NewCompositeTxn(logrus.StandardLogger()).
+		Put(first, "key", []byte("1")).
+		Put(second, "key", []byte("2")).
+		OnRollback(func() { rollbacks = append(rollbacks, "first") }).
+		OnRollback(func() { rollbacks = append(rollbacks, "second") }).
+		Commit()
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(rollbacks).To(gomega.Equal([]string{"second", "first"}))
+	gomega.Expect(connA.data["a/key"]).To(gomega.BeEquivalentTo("1"))
+}
+
+// TestCompositeTxnAtomicFailure checks that a failed backend transaction
+// does not run the rollback hooks - nothing was committed.
+func TestCompositeTxnAtomicFailure(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	conn.err = errors.New("connection lost")
+	broker := &fakeBroker{conn: conn, prefix: "a/", participant: true}
+
+	rollbacks := 0
+	err := NewCompositeTxn(logrus.StandardLogger()).
+		Put(broker, "key", []byte("1")).
+		OnRollback(func() { rollbacks++ }).
+		Commit()
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(rollbacks).To(gomega.BeEquivalentTo(0))
+}
+
+// TestCompositeTxnEmpty checks that committing an empty transaction is
+// a no-op.
+func TestCompositeTxnEmpty(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	err := NewCompositeTxn(logrus.StandardLogger()).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+}
@@ -15,12 +15,14 @@
 package etcd
 
 import (
+	"sync"
 	"time"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/namespace"
 	"github.com/coreos/etcd/mvcc/mvccpb"
 	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/discovery/dnssrv"
 	"github.com/ligato/cn-infra/logging"
 	"github.com/ligato/cn-infra/logging/logrus"
 	"golang.org/x/net/context"
@@ -28,12 +30,16 @@ import (
 
 // BytesBrokerEtcd allows to store, read and watch values from etcd.
 type BytesBrokerEtcd struct {
+	log        logging.Logger
 	etcdClient *clientv3.Client
 	lessor     clientv3.Lease
 
 	// closeCh is a channel closed when Close method of data broker is closed.
 	// It is used for giving go routines a signal to stop.
 	closeCh chan struct{}
+
+	// srvDone is closed when the SRV poller goroutine exits.
+	srvDone <-chan struct{}
 }
 
 // BytesPluginBrokerEtcd is a wrapper of Broker. It allows a plugin to access etcd. Since the PluginBroker uses Broker's connection
@@ -41,10 +47,13 @@ type BytesBrokerEtcd struct {
 // pairs and watch distinct set of etcd keys. BytesPluginBrokerEtcd allows also to define prefix that will be automatically prepended to
 // all keys in its requests.
 type BytesPluginBrokerEtcd struct {
-	closeCh chan struct{}
-	lessor  clientv3.Lease
-	kv      clientv3.KV
-	watcher clientv3.Watcher
+	log        logging.Logger
+	closeCh    chan struct{}
+	lessor     clientv3.Lease
+	kv         clientv3.KV
+	watcher    clientv3.Watcher
+	etcdClient *clientv3.Client
+	prefix     string
 }
 
 // bytesKeyValIterator is an iterator returned by ListValues call
@@ -69,15 +78,28 @@ type bytesKeyVal struct {
 	revision int64
 }
 
-var log logging.Logger
+// defaultLogger is used by the brokers that were not given their own
+// logger. It is guarded by a mutex instead of being mutated directly,
+// so that multiple agents in one process can create brokers concurrently.
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   logging.Logger = logrus.StandardLogger()
+)
 
-func init() {
-	log = logrus.StandardLogger()
+// SetLogger sets the logger used by the brokers created afterwards.
+// Brokers hold their own logger instance, so already created brokers
+// are not affected.
+func SetLogger(l logging.Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = l
 }
 
-// SetLogger sets a logger that will be used for library logging.
-func SetLogger(l logging.Logger) {
-	log = l
+// getDefaultLogger returns the logger for new brokers.
+func getDefaultLogger() logging.Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
 }
 
 // NewBytesBrokerEtcd creates a new instance of the Etcdv3 Data Broker. Connection
@@ -85,19 +107,37 @@ func SetLogger(l logging.Logger) {
 // Data Broker is a facade (visible front-end) to the configuration
 // data store.
 func NewBytesBrokerEtcd(config string) (*BytesBrokerEtcd, error) {
-	etcdClientKV, err := initRemoteClient(config)
+	etcdClientKV, srv, err := initRemoteClient(config)
 	if err != nil {
 		return nil, err
 	}
-	return NewBytesBrokerUsingClient(etcdClientKV)
+	broker, err := NewBytesBrokerUsingClient(etcdClientKV)
+	if err != nil {
+		return nil, err
+	}
+	if srv != nil && srv.PollPeriod > 0 {
+		// follow the cluster membership changes published in DNS
+		broker.srvDone = dnssrv.Poll(broker.log, srv, broker.closeCh, func(endpoints []string) {
+			etcdClientKV.SetEndpoints(endpoints...)
+		})
+	}
+	return broker, nil
 }
 
 // NewBytesBrokerUsingClient creates a new instance of BytesBrokerEtcd using the provided
 // etcdv3 client
 func NewBytesBrokerUsingClient(etcdClient *clientv3.Client) (*BytesBrokerEtcd, error) {
+	return NewBytesBrokerUsingClientWithLogger(etcdClient, getDefaultLogger())
+}
+
+// NewBytesBrokerUsingClientWithLogger creates a new instance of BytesBrokerEtcd
+// using the provided etcdv3 client and logger. The broker keeps the logger,
+// so brokers of different agent instances can log independently.
+func NewBytesBrokerUsingClientWithLogger(etcdClient *clientv3.Client, log logging.Logger) (*BytesBrokerEtcd, error) {
 	log.Debug("NewBytesBrokerEtcd", etcdClient)
 
 	dataBroker := BytesBrokerEtcd{}
+	dataBroker.log = log
 	dataBroker.etcdClient = etcdClient
 	dataBroker.closeCh = make(chan struct{})
 	dataBroker.lessor = clientv3.NewLease(etcdClient)
@@ -107,6 +147,10 @@ func NewBytesBrokerUsingClient(etcdClient *clientv3.Client) (*BytesBrokerEtcd, e
 // Close closes the connection to ETCD.
 func (db *BytesBrokerEtcd) Close() error {
 	close(db.closeCh)
+	if db.srvDone != nil {
+		// wait for the SRV poller before closing the client
+		<-db.srvDone
+	}
 	if db.etcdClient != nil {
 		return db.etcdClient.Close()
 	}
@@ -117,12 +161,32 @@ func (db *BytesBrokerEtcd) Close() error {
 // a plugin access to BytesBrokerEtcd.
 // Prefix (empty string is valid value) will be prepend to key argument in all calls on created BytesPluginBrokerEtcd.
 func (db *BytesBrokerEtcd) NewPluginBroker(prefix string) *BytesPluginBrokerEtcd {
-	return &BytesPluginBrokerEtcd{kv: namespace.NewKV(db.etcdClient, prefix), lessor: db.lessor, watcher: namespace.NewWatcher(db.etcdClient, prefix), closeCh: db.closeCh}
+	return &BytesPluginBrokerEtcd{log: db.log, kv: namespace.NewKV(db.etcdClient, prefix), lessor: db.lessor,
+		watcher: namespace.NewWatcher(db.etcdClient, prefix), closeCh: db.closeCh, etcdClient: db.etcdClient, prefix: prefix}
+}
+
+// TxnHandle identifies the underlying etcd connection. Plugin brokers
+// created from the same connection return the same handle and can share
+// one backend transaction in a composite transaction.
+func (pdb *BytesPluginBrokerEtcd) TxnHandle() interface{} {
+	return pdb.etcdClient
+}
+
+// NewConnectionTxn creates a transaction bound to the underlying etcd
+// connection; the broker prefix is not prepended to the keys.
+func (pdb *BytesPluginBrokerEtcd) NewConnectionTxn() keyval.BytesTxn {
+	return newTxnInternal(pdb.etcdClient)
+}
+
+// PrefixKey returns the key as stored in etcd, i.e. with the broker
+// prefix prepended.
+func (pdb *BytesPluginBrokerEtcd) PrefixKey(key string) string {
+	return pdb.prefix + key
 }
 
 // Put calls Put function of BytesBrokerEtcd. BytesPluginBrokerEtcd's prefix is prepended to key argument.
 func (pdb *BytesPluginBrokerEtcd) Put(key string, data []byte, opts ...keyval.PutOption) error {
-	return putInternal(pdb.kv, pdb.lessor, key, data, opts)
+	return putInternal(pdb.log, pdb.kv, pdb.lessor, key, data, opts)
 }
 
 // NewTxn creates new transaction. BytesPluginBrokerEtcd's prefix will be prepended to all key arguments in the transaction.
@@ -132,27 +196,27 @@ func (pdb *BytesPluginBrokerEtcd) NewTxn() keyval.BytesTxn {
 
 // GetValue call GetValue function of databroker. BytesPluginBrokerEtcd's prefix is prepended to key argument.
 func (pdb *BytesPluginBrokerEtcd) GetValue(key string) (data []byte, found bool, revision int64, err error) {
-	return getValueInternal(pdb.kv, key)
+	return getValueInternal(pdb.log, pdb.kv, key)
 }
 
 // ListValues calls ListValues function of databroker. BytesPluginBrokerEtcd's prefix is prepended to key argument.
 func (pdb *BytesPluginBrokerEtcd) ListValues(key string) (keyval.BytesKeyValIterator, error) {
-	return listValuesInternal(pdb.kv, key)
+	return listValuesInternal(pdb.log, pdb.kv, key)
 }
 
 // ListValuesRange calls ListValuesRange function of databroker. BytesPluginBrokerEtcd's prefix is prepended to the arguments.
 func (pdb *BytesPluginBrokerEtcd) ListValuesRange(fromPrefix string, toPrefix string) (keyval.BytesKeyValIterator, error) {
-	return listValuesRangeInternal(pdb.kv, fromPrefix, toPrefix)
+	return listValuesRangeInternal(pdb.log, pdb.kv, fromPrefix, toPrefix)
 }
 
 // ListKeys calls ListKeys function of databroker. BytesPluginBrokerEtcd's prefix is prepended to the argument.
 func (pdb *BytesPluginBrokerEtcd) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
-	return listKeysInternal(pdb.kv, prefix)
+	return listKeysInternal(pdb.log, pdb.kv, prefix)
 }
 
 // Delete calls delete function of databroker. BytesPluginBrokerEtcd's prefix is prepended to key argument.
 func (pdb *BytesPluginBrokerEtcd) Delete(key string) (bool, error) {
-	return deleteInternal(pdb.kv, key)
+	return deleteInternal(pdb.log, pdb.kv, key)
 }
 
 // Watch starts subscription for changes associated with the selected key. Watch events will be delivered to respChan.
@@ -160,7 +224,7 @@ func (pdb *BytesPluginBrokerEtcd) Delete(key string) (bool, error) {
 func (pdb *BytesPluginBrokerEtcd) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
 	var err error
 	for _, k := range keys {
-		err = watchInternal(pdb.watcher, pdb.closeCh, k, respChan)
+		err = watchInternal(pdb.log, pdb.watcher, pdb.closeCh, k, respChan)
 		if err != nil {
 			break
 		}
@@ -168,7 +232,7 @@ func (pdb *BytesPluginBrokerEtcd) Watch(respChan chan keyval.BytesWatchResp, key
 	return err
 }
 
-func handleWatchEvent(respChan chan keyval.BytesWatchResp, ev *clientv3.Event) {
+func handleWatchEvent(log logging.Logger, respChan chan keyval.BytesWatchResp, ev *clientv3.Event) {
 
 	var resp keyval.BytesWatchResp
 	if ev.Type == mvccpb.DELETE {
@@ -198,6 +262,23 @@ func (db *BytesBrokerEtcd) NewTxn() keyval.BytesTxn {
 	return newTxnInternal(db.etcdClient)
 }
 
+// TxnHandle identifies the etcd connection; plugin brokers created from
+// this broker return the same handle.
+func (db *BytesBrokerEtcd) TxnHandle() interface{} {
+	return db.etcdClient
+}
+
+// NewConnectionTxn creates a transaction bound to the etcd connection.
+func (db *BytesBrokerEtcd) NewConnectionTxn() keyval.BytesTxn {
+	return db.NewTxn()
+}
+
+// PrefixKey returns the key unchanged - the connection broker stores
+// the keys without a prefix.
+func (db *BytesBrokerEtcd) PrefixKey(key string) string {
+	return key
+}
+
 func newTxnInternal(kv clientv3.KV) keyval.BytesTxn {
 	tx := bytesTxn{}
 	tx.kv = kv
@@ -209,7 +290,7 @@ func newTxnInternal(kv clientv3.KV) keyval.BytesTxn {
 func (db *BytesBrokerEtcd) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
 	var err error
 	for _, k := range keys {
-		err = watchInternal(db.etcdClient, db.closeCh, k, respChan)
+		err = watchInternal(db.log, db.etcdClient, db.closeCh, k, respChan)
 		if err != nil {
 			break
 		}
@@ -219,7 +300,7 @@ func (db *BytesBrokerEtcd) Watch(respChan chan keyval.BytesWatchResp, keys ...st
 
 // watchInternal starts the watch subscription for key. Name argument identifies the subscriber, it allows to cancel the subscription.
 // BytesPluginBrokerEtcd fills this field automatically. BytesBrokerEtcd uses predefined defaultWatchID.
-func watchInternal(watcher clientv3.Watcher, closeCh chan struct{}, key string, respChan chan keyval.BytesWatchResp) error {
+func watchInternal(log logging.Logger, watcher clientv3.Watcher, closeCh chan struct{}, key string, respChan chan keyval.BytesWatchResp) error {
 
 	recvChan := watcher.Watch(context.Background(), key, clientv3.WithPrefix(), clientv3.WithPrevKV())
 
@@ -228,7 +309,7 @@ func watchInternal(watcher clientv3.Watcher, closeCh chan struct{}, key string,
 			select {
 			case wresp := <-recvChan:
 				for _, ev := range wresp.Events {
-					handleWatchEvent(respChan, ev)
+					handleWatchEvent(log, respChan, ev)
 				}
 			case <-closeCh:
 				log.WithField("key", key).Debug("Watch ended")
@@ -242,10 +323,10 @@ func watchInternal(watcher clientv3.Watcher, closeCh chan struct{}, key string,
 // Put writes the provided key-value item into the data store.
 // Returns an error if the item could not be written, nil otherwise.
 func (db *BytesBrokerEtcd) Put(key string, binData []byte, opts ...keyval.PutOption) error {
-	return putInternal(db.etcdClient, db.lessor, key, binData, opts...)
+	return putInternal(db.log, db.etcdClient, db.lessor, key, binData, opts...)
 }
 
-func putInternal(kv clientv3.KV, lessor clientv3.Lease, key string, binData []byte, opts ...keyval.PutOption) error {
+func putInternal(log logging.Logger, kv clientv3.KV, lessor clientv3.Lease, key string, binData []byte, opts ...keyval.PutOption) error {
 
 	var etcdOpts []clientv3.OpOption
 	for _, o := range opts {
@@ -268,10 +349,10 @@ func putInternal(kv clientv3.KV, lessor clientv3.Lease, key string, binData []by
 
 // Delete removes data identified by the key.
 func (db *BytesBrokerEtcd) Delete(key string) (bool, error) {
-	return deleteInternal(db.etcdClient, key)
+	return deleteInternal(db.log, db.etcdClient, key)
 }
 
-func deleteInternal(kv clientv3.KV, key string) (bool, error) {
+func deleteInternal(log logging.Logger, kv clientv3.KV, key string) (bool, error) {
 	// delete data from etcdv3
 	resp, err := kv.Delete(context.Background(), key)
 	if err != nil {
@@ -289,10 +370,10 @@ func deleteInternal(kv clientv3.KV, key string) (bool, error) {
 // GetValue retrieves one key-value item from the data store. The item
 // is identified by the provided key.
 func (db *BytesBrokerEtcd) GetValue(key string) (data []byte, found bool, revision int64, err error) {
-	return getValueInternal(db.etcdClient, key)
+	return getValueInternal(db.log, db.etcdClient, key)
 }
 
-func getValueInternal(kv clientv3.KV, key string) (data []byte, found bool, revision int64, err error) {
+func getValueInternal(log logging.Logger, kv clientv3.KV, key string) (data []byte, found bool, revision int64, err error) {
 	// get data from etcdv3
 	resp, err := kv.Get(context.Background(), key)
 	if err != nil {
@@ -308,10 +389,10 @@ func getValueInternal(kv clientv3.KV, key string) (data []byte, found bool, revi
 
 // ListValues returns an iterator that enables to traverse values stored under the provided key.
 func (db *BytesBrokerEtcd) ListValues(key string) (keyval.BytesKeyValIterator, error) {
-	return listValuesInternal(db.etcdClient, key)
+	return listValuesInternal(db.log, db.etcdClient, key)
 }
 
-func listValuesInternal(kv clientv3.KV, key string) (keyval.BytesKeyValIterator, error) {
+func listValuesInternal(log logging.Logger, kv clientv3.KV, key string) (keyval.BytesKeyValIterator, error) {
 	// get data from etcdv3
 	resp, err := kv.Get(context.Background(), key, clientv3.WithPrefix())
 	if err != nil {
@@ -324,10 +405,10 @@ func listValuesInternal(kv clientv3.KV, key string) (keyval.BytesKeyValIterator,
 
 // ListKeys is similar to the ListValues the difference is that values are not fetched
 func (db *BytesBrokerEtcd) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
-	return listKeysInternal(db.etcdClient, prefix)
+	return listKeysInternal(db.log, db.etcdClient, prefix)
 }
 
-func listKeysInternal(kv clientv3.KV, prefix string) (keyval.BytesKeyIterator, error) {
+func listKeysInternal(log logging.Logger, kv clientv3.KV, prefix string) (keyval.BytesKeyIterator, error) {
 	// get data from etcdv3
 	resp, err := kv.Get(context.Background(), prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
 	if err != nil {
@@ -340,10 +421,10 @@ func listKeysInternal(kv clientv3.KV, prefix string) (keyval.BytesKeyIterator, e
 
 // ListValuesRange returns an iterator that enables to traverse values stored under the provided key.
 func (db *BytesBrokerEtcd) ListValuesRange(fromPrefix string, toPrefix string) (keyval.BytesKeyValIterator, error) {
-	return listValuesRangeInternal(db.etcdClient, fromPrefix, toPrefix)
+	return listValuesRangeInternal(db.log, db.etcdClient, fromPrefix, toPrefix)
 }
 
-func listValuesRangeInternal(kv clientv3.KV, fromPrefix string, toPrefix string) (keyval.BytesKeyValIterator, error) {
+func listValuesRangeInternal(log logging.Logger, kv clientv3.KV, fromPrefix string, toPrefix string) (keyval.BytesKeyValIterator, error) {
 	// get data from etcdv3
 	resp, err := kv.Get(context.Background(), fromPrefix, clientv3.WithRange(toPrefix))
 	if err != nil {
@@ -26,16 +26,19 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/pkg/tlsutil"
 	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/discovery/dnssrv"
+	"github.com/ligato/cn-infra/utils/tlsreload"
 )
 
 type yamlConfig struct {
-	Endpoints             []string      `json:"endpoints"`
-	DialTimeout           time.Duration `json:"dial-timeout"`
-	InsecureTransport     bool          `json:"insecure-transport"`
-	InsecureSkipTLSVerify bool          `json:"insecure-skip-tls-verify"`
-	Certfile              string        `json:"cert-file"`
-	Keyfile               string        `json:"key-file"`
-	CAfile                string        `json:"ca-file"`
+	Endpoints             []string       `json:"endpoints"`
+	SRV                   *dnssrv.Config `json:"srv"`
+	DialTimeout           time.Duration  `json:"dial-timeout"`
+	InsecureTransport     bool           `json:"insecure-transport"`
+	InsecureSkipTLSVerify bool           `json:"insecure-skip-tls-verify"`
+	Certfile              string         `json:"cert-file"`
+	Keyfile               string         `json:"key-file"`
+	CAfile                string         `json:"ca-file"`
 }
 
 // default timeout for connecting to etcd.
@@ -46,16 +49,25 @@ const defaultTimeout = 1 * time.Second
 // valid configuration, the parsed client configuration is
 // returned; otherwise, an error is returned.
 func configFromFile(fpath string) (*clientv3.Config, error) {
+	cfg, _, err := configWithDiscoveryFromFile(fpath)
+	return cfg, err
+}
+
+// configWithDiscoveryFromFile loads the Etcd client configuration from
+// the specified file together with the SRV discovery settings, when
+// configured. When no static endpoints are configured, the initial
+// endpoints are resolved from the SRV name.
+func configWithDiscoveryFromFile(fpath string) (*clientv3.Config, *dnssrv.Config, error) {
 	b, err := ioutil.ReadFile(fpath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	yc := &yamlConfig{}
 
 	err = yaml.Unmarshal(b, yc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	timeout := defaultTimeout
@@ -69,27 +81,24 @@ func configFromFile(fpath string) (*clientv3.Config, error) {
 		DialTimeout: timeout,
 	}
 
+	if len(cfg.Endpoints) == 0 && yc.SRV != nil && yc.SRV.Name != "" {
+		cfg.Endpoints, err = dnssrv.Resolve(yc.SRV.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if yc.InsecureTransport {
 		cfg.TLS = nil
-		return cfg, nil
+		return cfg, yc.SRV, nil
 	}
 
-	var (
-		cert *tls.Certificate
-		cp   *x509.CertPool
-	)
-
-	if yc.Certfile != "" && yc.Keyfile != "" {
-		cert, err = tlsutil.NewCert(yc.Certfile, yc.Keyfile, nil)
-		if err != nil {
-			return nil, err
-		}
-	}
+	var cp *x509.CertPool
 
 	if yc.CAfile != "" {
 		cp, err = tlsutil.NewCertPool([]string{yc.CAfile})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -98,25 +107,32 @@ func configFromFile(fpath string) (*clientv3.Config, error) {
 		InsecureSkipVerify: yc.InsecureSkipTLSVerify,
 		RootCAs:            cp,
 	}
-	if cert != nil {
-		tlscfg.Certificates = []tls.Certificate{*cert}
+	if yc.Certfile != "" && yc.Keyfile != "" {
+		// the client certificate is transparently reloaded when rotated
+		keyPair, err := tlsreload.NewKeyPair(getDefaultLogger(), yc.Certfile, yc.Keyfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlscfg.GetClientCertificate = keyPair.GetClientCertificate
 	}
 	cfg.TLS = tlscfg
 
-	return cfg, nil
+	return cfg, yc.SRV, nil
 }
 
 // initRemoteClient initializes the Connection to ETCD. ETCD clientv3
 // config file contains the settings of the ETCD connection. A new clientv3
-// is created in the function and returned to the caller.
-func initRemoteClient(configFile string) (*clientv3.Client, error) {
+// is created in the function and returned to the caller, together with
+// the SRV discovery settings when the config file contains them.
+func initRemoteClient(configFile string) (*clientv3.Client, *dnssrv.Config, error) {
 	var config *clientv3.Config
+	var srv *dnssrv.Config
 	var err error
 
 	if configFile != "" {
-		config, err = configFromFile(configFile)
+		config, srv, err = configWithDiscoveryFromFile(configFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	} else if ep := os.Getenv("ETCDV3_ENDPOINTS"); ep != "" {
 		config = &clientv3.Config{
@@ -131,8 +147,8 @@ func initRemoteClient(configFile string) (*clientv3.Client, error) {
 	var etcdClient *clientv3.Client
 	etcdClient, err = clientv3.New(*config)
 	if err != nil {
-		log.Errorf("Failed to connect to Etcd etcd(s) %v, Error: '%s'", config.Endpoints, err)
-		return nil, err
+		getDefaultLogger().Errorf("Failed to connect to Etcd etcd(s) %v, Error: '%s'", config.Endpoints, err)
+		return nil, nil, err
 	}
-	return etcdClient, nil
+	return etcdClient, srv, nil
 }
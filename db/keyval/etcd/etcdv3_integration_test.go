@@ -15,51 +15,43 @@
 package etcd
 
 import (
-	"context"
-	"github.com/coreos/etcd/clientv3"
-	"github.com/coreos/etcd/embed"
-	"github.com/coreos/etcd/etcdserver/api/v3client"
-	"github.com/ligato/cn-infra/db/keyval"
-	"github.com/onsi/gomega"
-	"io/ioutil"
-	"os"
-	"sync"
 	"testing"
 	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/db/keyval/etcd/mocks"
+	"github.com/onsi/gomega"
 )
 
 const (
-	etcdStartTimeout = 30
-	prefix           = "/my/prefix/"
-	key              = "key"
-	watchKey         = "vals/"
+	prefix   = "/my/prefix/"
+	key      = "key"
+	watchKey = "vals/"
 )
 
 var (
 	broker *BytesBrokerEtcd
 	pdb    keyval.BytesPluginBroker
-	embd   embededEtcd
+	embd   mocks.EmbeddedEtcd
 )
 
-type embededEtcd struct {
-	tmpDir string
-	etcd   *embed.Etcd
-	client *clientv3.Client
-}
-
 func TestDataBroker(t *testing.T) {
 
 	//setup
-	embd.start(t)
-	defer embd.stop()
+	err := embd.Start()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer embd.Stop()
 	gomega.RegisterTestingT(t)
 
 	t.Run("putGetValue", testPutGetValuePrefixed)
-	embd.cleanDs()
+	embd.CleanDs()
 	t.Run("simpleWatcher", testPrefixedWatcher)
-	embd.cleanDs()
+	embd.CleanDs()
 	t.Run("listValues", testPrefixedListValues)
-	embd.cleanDs()
+	embd.CleanDs()
 	t.Run("txn", testPrefixedTxn)
 }
 
@@ -101,17 +93,13 @@ func testPrefixedWatcher(t *testing.T) {
 	err := pdb.Watch(watchCh, watchKey)
 	gomega.Expect(err).To(gomega.BeNil())
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go expectWatchEvent(t, &wg, watchCh, watchKey+"val1")
-
 	// insert kv that doesn't match the watcher subscription
 	broker.Put(prefix+"/something/else/val1", []byte{0, 0, 7})
 
 	// insert kv for watcher
 	broker.Put(prefix+watchKey+"val1", []byte{0, 0, 7})
 
-	wg.Wait()
+	expectWatchEvent(t, watchCh, watchKey+"val1")
 }
 
 func testPrefixedTxn(t *testing.T) {
@@ -167,7 +155,7 @@ func testPrefixedListValues(t *testing.T) {
 	}
 }
 
-func expectWatchEvent(t *testing.T, wg *sync.WaitGroup, watchCh chan keyval.BytesWatchResp, expectedKey string) {
+func expectWatchEvent(t *testing.T, watchCh chan keyval.BytesWatchResp, expectedKey string) {
 	select {
 	case resp := <-watchCh:
 		gomega.Expect(resp).NotTo(gomega.BeNil())
@@ -176,54 +164,14 @@ func expectWatchEvent(t *testing.T, wg *sync.WaitGroup, watchCh chan keyval.Byte
 		t.Error("Watch resp not received")
 		t.FailNow()
 	}
-	wg.Done()
-}
-
-func (embd *embededEtcd) start(t *testing.T) {
-	dir, err := ioutil.TempDir("", "etcd")
-	if err != nil {
-		t.Error(err)
-		t.FailNow()
-	}
-
-	cfg := embed.NewConfig()
-	cfg.Dir = dir
-	embd.etcd, err = embed.StartEtcd(cfg)
-	if err != nil {
-		t.Error(err)
-		t.FailNow()
-
-	}
-
-	select {
-	case <-embd.etcd.Server.ReadyNotify():
-		log.Debug("Server is ready!")
-	case <-time.After(etcdStartTimeout * time.Second):
-		embd.etcd.Server.Stop() // trigger a shutdown
-		t.Error("Server took too long to start!")
-		t.FailNow()
-	}
-	embd.client = v3client.New(embd.etcd.Server)
-}
-
-func (embd *embededEtcd) stop() {
-	embd.etcd.Close()
-	os.RemoveAll(embd.tmpDir)
-}
-
-// cleanDs deletes all key-value pair stored
-func (embd *embededEtcd) cleanDs() {
-	if embd.client != nil {
-		embd.client.Delete(context.Background(), "", clientv3.WithPrefix())
-	}
 }
 
 func setupBrokers(t *testing.T) {
 	var err error
-	broker, err = NewBytesBrokerUsingClient(v3client.New(embd.etcd.Server))
+	broker, err = NewBytesBrokerUsingClient(embd.NewClient())
 
-	gomega.Expect(err).To(gomega.BeNil())
 	gomega.Expect(broker).NotTo(gomega.BeNil())
+	gomega.Expect(err).To(gomega.BeNil())
 	// create BytesPluginBrokerEtcd with prefix
 	pdb = broker.NewPluginBroker(prefix)
 	gomega.Expect(pdb).NotTo(gomega.BeNil())
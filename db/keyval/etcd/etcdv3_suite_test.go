@@ -137,10 +137,10 @@ func (mock *MockTxn) Commit() (*clientv3.TxnResponse, error) {
 func init() {
 	mockKv := &MockKV{}
 	mockKvErr := &MockKVErr{}
-	dataBroker = &BytesBrokerEtcd{etcdClient: &clientv3.Client{KV: mockKv, Watcher: mockKv}}
-	dataBrokerErr = &BytesBrokerEtcd{etcdClient: &clientv3.Client{KV: mockKvErr, Watcher: mockKvErr}}
-	pluginDataBroker = &BytesPluginBrokerEtcd{kv: mockKv, watcher: mockKv}
-	pluginDataBrokerErr = &BytesPluginBrokerEtcd{kv: mockKvErr, watcher: mockKvErr}
+	dataBroker = &BytesBrokerEtcd{log: getDefaultLogger(), etcdClient: &clientv3.Client{KV: mockKv, Watcher: mockKv}}
+	dataBrokerErr = &BytesBrokerEtcd{log: getDefaultLogger(), etcdClient: &clientv3.Client{KV: mockKvErr, Watcher: mockKvErr}}
+	pluginDataBroker = &BytesPluginBrokerEtcd{log: getDefaultLogger(), kv: mockKv, watcher: mockKv}
+	pluginDataBrokerErr = &BytesPluginBrokerEtcd{log: getDefaultLogger(), kv: mockKvErr, watcher: mockKvErr}
 }
 
 func TestNewTxn(t *testing.T) {
@@ -0,0 +1,130 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/embed"
+	"github.com/coreos/etcd/etcdserver/api/v3client"
+	"golang.org/x/net/context"
+)
+
+// startTimeout bounds how long the embedded server may take to become
+// ready.
+const startTimeout = 30 * time.Second
+
+// EmbeddedEtcd is an in-process etcd server for integration tests. The
+// server listens on randomly allocated localhost ports and stores its
+// data in a temporary directory, so parallel test runs do not collide.
+type EmbeddedEtcd struct {
+	tmpDir string
+	server *embed.Etcd
+	client *clientv3.Client
+}
+
+// Start launches the embedded server and waits until it is ready.
+func (embd *EmbeddedEtcd) Start() error {
+	dir, err := ioutil.TempDir("", "etcd")
+	if err != nil {
+		return err
+	}
+	embd.tmpDir = dir
+
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	clientURL, err := freeLocalhostURL()
+	if err != nil {
+		return err
+	}
+	peerURL, err := freeLocalhostURL()
+	if err != nil {
+		return err
+	}
+	cfg.LCUrls = []url.URL{*clientURL}
+	cfg.ACUrls = []url.URL{*clientURL}
+	cfg.LPUrls = []url.URL{*peerURL}
+	cfg.APUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	embd.server, err = embed.StartEtcd(cfg)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-embd.server.Server.ReadyNotify():
+	case <-time.After(startTimeout):
+		embd.server.Server.Stop()
+		return errors.New("embedded etcd took too long to start")
+	}
+	embd.client = v3client.New(embd.server.Server)
+	return nil
+}
+
+// Stop shuts the server down and removes its data directory.
+func (embd *EmbeddedEtcd) Stop() error {
+	if embd.server != nil {
+		embd.server.Close()
+		embd.server = nil
+	}
+	if embd.tmpDir != "" {
+		return os.RemoveAll(embd.tmpDir)
+	}
+	return nil
+}
+
+// CleanDs deletes all stored key-value pairs, giving each test case
+// a clean datastore.
+func (embd *EmbeddedEtcd) CleanDs() error {
+	if embd.client == nil {
+		return errors.New("embedded etcd is not running")
+	}
+	_, err := embd.client.Delete(context.Background(), "", clientv3.WithPrefix())
+	return err
+}
+
+// Client returns the client of the harness itself. Do not close it;
+// tests that hand the client over to code that closes it should use
+// NewClient instead.
+func (embd *EmbeddedEtcd) Client() *clientv3.Client {
+	return embd.client
+}
+
+// NewClient creates a new client connected to the embedded server. The
+// caller is responsible for closing it.
+func (embd *EmbeddedEtcd) NewClient() *clientv3.Client {
+	return v3client.New(embd.server.Server)
+}
+
+// freeLocalhostURL allocates a free localhost port and returns it as
+// a URL the server can listen on.
+func freeLocalhostURL() (*url.URL, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	address := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		return nil, err
+	}
+	return url.Parse("http://" + address)
+}
@@ -17,22 +17,33 @@ package etcd
 import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/discovery/dnssrv"
 	"github.com/ligato/cn-infra/utils/safeclose"
 )
 
 // DbMuxEtcd implements Plugin interface therefore can be loaded with other plugins
 type DbMuxEtcd struct {
-	client *clientv3.Client
-	dbRoot *ProtoBrokerEtcd
+	client  *clientv3.Client
+	dbRoot  *ProtoBrokerEtcd
+	closeCh chan struct{}
+	srvDone <-chan struct{}
 }
 
 // NewEtcdPlugin creates a new instance of DbMuxEtcd. Configuration of etcd connection is loaded from file.
 func NewEtcdPlugin(etcdConfig string) (*DbMuxEtcd, error) {
-	client, err := initRemoteClient(etcdConfig)
+	client, srv, err := initRemoteClient(etcdConfig)
 	if err != nil {
 		return nil, err
 	}
-	return NewEtcdPluginUsingClient(client), nil
+	plugin := NewEtcdPluginUsingClient(client)
+	if srv != nil && srv.PollPeriod > 0 {
+		// follow the cluster membership changes published in DNS
+		plugin.closeCh = make(chan struct{})
+		plugin.srvDone = dnssrv.Poll(getDefaultLogger(), srv, plugin.closeCh, func(endpoints []string) {
+			client.SetEndpoints(endpoints...)
+		})
+	}
+	return plugin, nil
 }
 
 // NewEtcdPluginUsingClient creates a new instance of DbMuxEtcd using given etcd client
@@ -51,12 +62,12 @@ func (plugin *DbMuxEtcd) Init() (err error) {
 
 	coreBroker, err := NewBytesBrokerUsingClient(plugin.client)
 	if err != nil {
-		log.Error(err)
+		getDefaultLogger().Error(err)
 		return err
 	}
 	plugin.dbRoot = NewProtoBrokerWithSerializer(coreBroker, &keyval.SerializerJSON{})
 
-	log.Debug("initEtcdClient success ", coreBroker)
+	coreBroker.log.Debug("initEtcdClient success ", coreBroker)
 	return nil
 }
 
@@ -83,5 +94,10 @@ func (plugin *DbMuxEtcd) NewPrefixedWatcher(keyPrefix string) keyval.ProtoWatche
 
 // Close cleans up the resources
 func (plugin *DbMuxEtcd) Close() error {
+	if plugin.closeCh != nil {
+		close(plugin.closeCh)
+		// wait for the SRV poller before closing the client
+		<-plugin.srvDone
+	}
 	return safeclose.Close(plugin.dbRoot)
 }
@@ -0,0 +1,209 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvaltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/onsi/gomega"
+)
+
+// Broker is the set of interfaces a backend must implement to run the
+// suite.
+type Broker interface {
+	keyval.BytesBroker
+	keyval.BytesWatcher
+}
+
+// Factory creates a fresh, empty broker for one test of the suite. The
+// returned closeFunc releases the broker afterwards; it may be nil.
+type Factory func(t *testing.T) (broker Broker, closeFunc func())
+
+// watchTimeout bounds the wait for a watch response.
+const watchTimeout = 5 * time.Second
+
+// RunSuite runs the conformance tests as subtests, each against a fresh
+// broker created by the factory.
+func RunSuite(t *testing.T, factory Factory) {
+	suite := []struct {
+		name string
+		test func(t *testing.T, broker Broker)
+	}{
+		{"PutAndGet", testPutAndGet},
+		{"GetMissing", testGetMissing},
+		{"Delete", testDelete},
+		{"ListKeys", testListKeys},
+		{"ListValues", testListValues},
+		{"Txn", testTxn},
+		{"Watch", testWatch},
+	}
+	for _, entry := range suite {
+		t.Run(entry.name, func(t *testing.T) {
+			gomega.RegisterTestingT(t)
+			broker, closeFunc := factory(t)
+			if closeFunc != nil {
+				defer closeFunc()
+			}
+			entry.test(t, broker)
+		})
+	}
+}
+
+// testPutAndGet verifies that a stored value is retrieved back and that
+// a put overwrites the previous value.
+func testPutAndGet(t *testing.T, broker Broker) {
+	gomega.Expect(broker.Put("/suite/key1", []byte("value1"))).To(gomega.BeNil())
+
+	data, found, _, err := broker.GetValue("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.Equal([]byte("value1")))
+
+	gomega.Expect(broker.Put("/suite/key1", []byte("value2"))).To(gomega.BeNil())
+	data, found, _, err = broker.GetValue("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.Equal([]byte("value2")))
+}
+
+// testGetMissing verifies that a missing key is reported as not found,
+// not as an error.
+func testGetMissing(t *testing.T, broker Broker) {
+	data, found, _, err := broker.GetValue("/suite/missing")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeFalse())
+	gomega.Expect(data).To(gomega.BeNil())
+}
+
+// testDelete verifies that a delete reports whether the key existed and
+// that the key is gone afterwards.
+func testDelete(t *testing.T, broker Broker) {
+	gomega.Expect(broker.Put("/suite/key1", []byte("value1"))).To(gomega.BeNil())
+
+	existed, err := broker.Delete("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(existed).To(gomega.BeTrue())
+
+	_, found, _, err := broker.GetValue("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeFalse())
+
+	existed, err = broker.Delete("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(existed).To(gomega.BeFalse())
+}
+
+// testListKeys verifies that the listing matches the prefix only and
+// that the keys arrive in the ascending order.
+func testListKeys(t *testing.T, broker Broker) {
+	gomega.Expect(broker.Put("/suite/b", []byte("2"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/suite/a", []byte("1"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/suite/c", []byte("3"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/other/d", []byte("4"))).To(gomega.BeNil())
+
+	iterator, err := broker.ListKeys("/suite/")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(collectKeys(iterator)).To(gomega.Equal([]string{"/suite/a", "/suite/b", "/suite/c"}))
+}
+
+// testListValues verifies that the listed pairs carry the stored values
+// and arrive ordered by the key.
+func testListValues(t *testing.T, broker Broker) {
+	gomega.Expect(broker.Put("/suite/b", []byte("2"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/suite/a", []byte("1"))).To(gomega.BeNil())
+
+	iterator, err := broker.ListValues("/suite/")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	kv, allReceived := iterator.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeFalse())
+	gomega.Expect(kv.GetKey()).To(gomega.Equal("/suite/a"))
+	gomega.Expect(kv.GetValue()).To(gomega.Equal([]byte("1")))
+
+	kv, allReceived = iterator.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeFalse())
+	gomega.Expect(kv.GetKey()).To(gomega.Equal("/suite/b"))
+	gomega.Expect(kv.GetValue()).To(gomega.Equal([]byte("2")))
+
+	_, allReceived = iterator.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeTrue())
+}
+
+// testTxn verifies that the operations of a transaction are all applied
+// by the commit.
+func testTxn(t *testing.T, broker Broker) {
+	gomega.Expect(broker.Put("/suite/old", []byte("old"))).To(gomega.BeNil())
+
+	txn := broker.NewTxn()
+	txn.Put("/suite/new1", []byte("1")).Put("/suite/new2", []byte("2")).Delete("/suite/old")
+	gomega.Expect(txn.Commit()).To(gomega.BeNil())
+
+	_, found, _, _ := broker.GetValue("/suite/old")
+	gomega.Expect(found).To(gomega.BeFalse())
+	data, found, _, _ := broker.GetValue("/suite/new1")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.Equal([]byte("1")))
+	_, found, _, _ = broker.GetValue("/suite/new2")
+	gomega.Expect(found).To(gomega.BeTrue())
+}
+
+// testWatch verifies that a put and a delete of a watched key are
+// reported with the proper change type and that the keys outside of the
+// watched prefix are not.
+func testWatch(t *testing.T, broker Broker) {
+	watchChan := make(chan keyval.BytesWatchResp, 10)
+	gomega.Expect(broker.Watch(watchChan, "/suite/")).To(gomega.BeNil())
+
+	gomega.Expect(broker.Put("/suite/key1", []byte("value1"))).To(gomega.BeNil())
+	resp := receiveWatchResp(t, watchChan)
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Put))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("/suite/key1"))
+	gomega.Expect(resp.GetValue()).To(gomega.Equal([]byte("value1")))
+
+	// a change outside of the watched prefix must not be reported
+	gomega.Expect(broker.Put("/other/key2", []byte("value2"))).To(gomega.BeNil())
+
+	_, err := broker.Delete("/suite/key1")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp = receiveWatchResp(t, watchChan)
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Delete))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("/suite/key1"))
+}
+
+// collectKeys drains a key iterator.
+func collectKeys(iterator keyval.BytesKeyIterator) []string {
+	var keys []string
+	for {
+		key, _, allReceived := iterator.GetNext()
+		if allReceived {
+			return keys
+		}
+		keys = append(keys, key)
+	}
+}
+
+// receiveWatchResp waits for the delivery of one watch response.
+func receiveWatchResp(t *testing.T, watchChan chan keyval.BytesWatchResp) keyval.BytesWatchResp {
+	select {
+	case resp := <-watchChan:
+		return resp
+	case <-time.After(watchTimeout):
+		t.Fatal("timeout waiting for a watch response")
+		return nil
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// BytesBrokerRedis implements the keyval.BytesBroker and
+// keyval.BytesWatcher interfaces on top of a Redis connection.
+type BytesBrokerRedis struct {
+	log  logging.Logger
+	conn Connection
+}
+
+// NewBytesBrokerUsingConnection creates a broker on top of an already
+// established connection.
+func NewBytesBrokerUsingConnection(log logging.Logger, conn Connection) *BytesBrokerRedis {
+	return &BytesBrokerRedis{log: log, conn: conn}
+}
+
+// NewBytesBroker creates a broker with the connection created by the
+// ConnectionFactory from the configuration loaded from the file.
+func NewBytesBroker(log logging.Logger, configFile string) (*BytesBrokerRedis, error) {
+	if ConnectionFactory == nil {
+		return nil, errors.New("no Redis connection was injected and no ConnectionFactory is registered")
+	}
+	config, err := ConfigFromFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := ConnectionFactory(config)
+	if err != nil {
+		log.Errorf("Failed to connect to Redis %s, Error: '%s'", config.Endpoint, err)
+		return nil, err
+	}
+	return &BytesBrokerRedis{log: log, conn: conn}, nil
+}
+
+// Close releases the connection.
+func (broker *BytesBrokerRedis) Close() error {
+	return broker.conn.Close()
+}
+
+// NewPluginBroker creates a broker that prepends the given prefix to
+// all keys in its calls and trims it from the returned keys.
+func (broker *BytesBrokerRedis) NewPluginBroker(prefix string) *BytesPluginBrokerRedis {
+	return &BytesPluginBrokerRedis{broker: broker, prefix: prefix}
+}
+
+// Put stores the value under the key.
+func (broker *BytesBrokerRedis) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	return broker.conn.Set(key, data)
+}
+
+// GetValue retrieves the value of the key. Redis does not version the
+// values, the returned revision is always zero.
+func (broker *BytesBrokerRedis) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	data, found, err = broker.conn.Get(key)
+	return data, found, 0, err
+}
+
+// ListKeys returns an iterator over the keys matching the prefix,
+// sorted lexicographically.
+func (broker *BytesBrokerRedis) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	keys, err := broker.conn.Scan(prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return &bytesKeyIterator{keys: keys}, nil
+}
+
+// ListValues returns an iterator over the key-value pairs matching the
+// prefix, sorted lexicographically by the key.
+func (broker *BytesBrokerRedis) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	keys, err := broker.conn.Scan(prefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	pairs := make([]bytesKeyVal, 0, len(keys))
+	for _, key := range keys {
+		data, found, err := broker.conn.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			// the key disappeared between the scan and the get
+			continue
+		}
+		pairs = append(pairs, bytesKeyVal{key: key, value: data})
+	}
+	return &bytesKeyValIterator{pairs: pairs}, nil
+}
+
+// Delete removes the key.
+func (broker *BytesBrokerRedis) Delete(key string) (bool, error) {
+	return broker.conn.Del(key)
+}
+
+// NewTxn creates a transaction. The operations are buffered and applied
+// together by Commit.
+func (broker *BytesBrokerRedis) NewTxn() keyval.BytesTxn {
+	return &bytesTxn{broker: broker}
+}
+
+// Watch starts the delivery of the changes of the keys matching the
+// given prefixes to respChan.
+func (broker *BytesBrokerRedis) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	for _, prefix := range keys {
+		if err := broker.watchPrefix(respChan, prefix, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchPrefix subscribes for the keyspace events of one prefix. The
+// trimPrefix is removed from the keys of the delivered responses.
+func (broker *BytesBrokerRedis) watchPrefix(respChan chan keyval.BytesWatchResp, prefix string, trimPrefix string) error {
+	notifChan := make(chan Notification)
+	if err := broker.conn.SubscribeKeyspaceEvents(prefix, notifChan); err != nil {
+		return err
+	}
+	go func() {
+		for notif := range notifChan {
+			resp := &bytesWatchResp{
+				key:        strings.TrimPrefix(notif.Key, trimPrefix),
+				changeType: notif.Op,
+			}
+			if notif.Op == db.Put {
+				data, found, err := broker.conn.Get(notif.Key)
+				if err != nil || !found {
+					broker.log.Warn("Failed to fetch the value of changed key ", notif.Key)
+					continue
+				}
+				resp.value = data
+			}
+			respChan <- resp
+		}
+	}()
+	return nil
+}
+
+// BytesPluginBrokerRedis is a wrapper of BytesBrokerRedis that prepends
+// a prefix to all keys in its calls and trims it from the returned keys.
+type BytesPluginBrokerRedis struct {
+	broker *BytesBrokerRedis
+	prefix string
+}
+
+// Put stores the value under the prefixed key.
+func (pdb *BytesPluginBrokerRedis) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	return pdb.broker.Put(pdb.prefix+key, data, opts...)
+}
+
+// GetValue retrieves the value of the prefixed key.
+func (pdb *BytesPluginBrokerRedis) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	return pdb.broker.GetValue(pdb.prefix + key)
+}
+
+// ListKeys returns an iterator over the matching keys with the broker
+// prefix trimmed.
+func (pdb *BytesPluginBrokerRedis) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	iterator, err := pdb.broker.ListKeys(pdb.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	keyIterator := iterator.(*bytesKeyIterator)
+	for i, key := range keyIterator.keys {
+		keyIterator.keys[i] = strings.TrimPrefix(key, pdb.prefix)
+	}
+	return keyIterator, nil
+}
+
+// ListValues returns an iterator over the matching key-value pairs with
+// the broker prefix trimmed from the keys.
+func (pdb *BytesPluginBrokerRedis) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	iterator, err := pdb.broker.ListValues(pdb.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	kvIterator := iterator.(*bytesKeyValIterator)
+	for i := range kvIterator.pairs {
+		kvIterator.pairs[i].key = strings.TrimPrefix(kvIterator.pairs[i].key, pdb.prefix)
+	}
+	return kvIterator, nil
+}
+
+// Delete removes the prefixed key.
+func (pdb *BytesPluginBrokerRedis) Delete(key string) (bool, error) {
+	return pdb.broker.Delete(pdb.prefix + key)
+}
+
+// NewTxn creates a transaction operating on the prefixed keys.
+func (pdb *BytesPluginBrokerRedis) NewTxn() keyval.BytesTxn {
+	return &bytesTxn{broker: pdb.broker, prefix: pdb.prefix}
+}
+
+// Watch starts the delivery of the changes of the prefixed keys to
+// respChan; the broker prefix is trimmed from the keys of the responses.
+func (pdb *BytesPluginBrokerRedis) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	for _, prefix := range keys {
+		if err := pdb.broker.watchPrefix(respChan, pdb.prefix+prefix, pdb.prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// txnOp is one buffered operation of a transaction.
+type txnOp struct {
+	key  string
+	data []byte
+	del  bool
+}
+
+// bytesTxn buffers the operations until Commit.
+type bytesTxn struct {
+	broker *BytesBrokerRedis
+	prefix string
+	ops    []txnOp
+}
+
+// Put adds a store operation into the transaction.
+func (txn *bytesTxn) Put(key string, data []byte) keyval.BytesTxn {
+	txn.ops = append(txn.ops, txnOp{key: key, data: data})
+	return txn
+}
+
+// Delete adds a delete operation into the transaction.
+func (txn *bytesTxn) Delete(key string) keyval.BytesTxn {
+	txn.ops = append(txn.ops, txnOp{key: key, del: true})
+	return txn
+}
+
+// Commit applies the buffered operations.
+func (txn *bytesTxn) Commit() error {
+	for _, op := range txn.ops {
+		if op.del {
+			if _, err := txn.broker.Delete(txn.prefix + op.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := txn.broker.Put(txn.prefix+op.key, op.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bytesKeyVal is one listed key-value pair.
+type bytesKeyVal struct {
+	key   string
+	value []byte
+}
+
+// GetKey returns the key of the pair.
+func (kv bytesKeyVal) GetKey() string {
+	return kv.key
+}
+
+// GetValue returns the value of the pair.
+func (kv bytesKeyVal) GetValue() []byte {
+	return kv.value
+}
+
+// GetRevision returns zero, Redis does not version the values.
+func (kv bytesKeyVal) GetRevision() int64 {
+	return 0
+}
+
+// bytesKeyValIterator iterates over listed key-value pairs.
+type bytesKeyValIterator struct {
+	pairs []bytesKeyVal
+	index int
+}
+
+// GetNext retrieves the following item from the iterator.
+func (it *bytesKeyValIterator) GetNext() (keyval.BytesKeyVal, bool) {
+	if it.index >= len(it.pairs) {
+		return nil, true
+	}
+	kv := it.pairs[it.index]
+	it.index++
+	return kv, false
+}
+
+// bytesKeyIterator iterates over listed keys.
+type bytesKeyIterator struct {
+	keys  []string
+	index int
+}
+
+// GetNext retrieves the following key from the iterator.
+func (it *bytesKeyIterator) GetNext() (string, int64, bool) {
+	if it.index >= len(it.keys) {
+		return "", 0, true
+	}
+	key := it.keys[it.index]
+	it.index++
+	return key, 0, false
+}
+
+// bytesWatchResp is a notification about a change of one key.
+type bytesWatchResp struct {
+	key        string
+	value      []byte
+	changeType db.PutDel
+}
+
+// GetKey returns the key the change is about.
+func (resp *bytesWatchResp) GetKey() string {
+	return resp.key
+}
+
+// GetValue returns the changed value; nil for a delete.
+func (resp *bytesWatchResp) GetValue() []byte {
+	return resp.value
+}
+
+// GetChangeType distinguishes a put from a delete.
+func (resp *bytesWatchResp) GetChangeType() db.PutDel {
+	return resp.changeType
+}
+
+// GetRevision returns zero, Redis does not version the values.
+func (resp *bytesWatchResp) GetRevision() int64 {
+	return 0
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumSlots is the number of hash slots of a Redis Cluster.
+const NumSlots = 16384
+
+// Slot returns the cluster hash slot of the key: the CRC16 of the key
+// modulo NumSlots. When the key contains a non-empty hash tag (the
+// content of the first {} pair), only the tag is hashed, so related
+// keys can be pinned to one node.
+func Slot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16([]byte(key))) % NumSlots
+}
+
+// Redirect is a parsed MOVED or ASK redirection returned by a cluster
+// node that does not serve the hash slot of the key.
+type Redirect struct {
+	// Ask distinguishes a one-shot ASK redirection (the slot is being
+	// migrated) from a permanent MOVED one.
+	Ask bool
+	// Slot is the hash slot the redirection is about.
+	Slot int
+	// Addr is the address of the node serving the slot.
+	Addr string
+}
+
+// ParseRedirect parses a "MOVED <slot> <addr>" or "ASK <slot> <addr>"
+// error message of a cluster node. Connection implementations use it to
+// follow the redirections up to the configured limit.
+func ParseRedirect(message string) (redirect Redirect, ok bool) {
+	fields := strings.Fields(message)
+	if len(fields) != 3 {
+		return redirect, false
+	}
+	switch fields[0] {
+	case "MOVED":
+	case "ASK":
+		redirect.Ask = true
+	default:
+		return redirect, false
+	}
+	slot, err := strconv.Atoi(fields[1])
+	if err != nil || slot < 0 || slot >= NumSlots {
+		return Redirect{}, false
+	}
+	redirect.Slot = slot
+	redirect.Addr = fields[2]
+	return redirect, true
+}
+
+// crc16 computes the CRC16-CCITT (XMODEM) checksum used by Redis
+// Cluster for the hash slot assignment.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
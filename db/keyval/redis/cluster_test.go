@@ -0,0 +1,95 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+// TestSlot checks the hash slot computation against the values the
+// Redis Cluster specification documents.
+func TestSlot(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	gomega.Expect(Slot("123456789")).To(gomega.BeEquivalentTo(12739))
+	gomega.Expect(Slot("foo")).To(gomega.BeEquivalentTo(12182))
+	gomega.Expect(Slot("bar")).To(gomega.BeEquivalentTo(5061))
+}
+
+// TestSlotHashTags checks that only the content of the first non-empty
+// {} pair is hashed.
+func TestSlotHashTags(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	gomega.Expect(Slot("{user1000}.following")).To(gomega.Equal(Slot("user1000")))
+	gomega.Expect(Slot("{user1000}.followers")).To(gomega.Equal(Slot("user1000")))
+	// an empty tag does not pin the key, the whole key is hashed
+	gomega.Expect(Slot("foo{}{bar}")).To(gomega.Equal(int(crc16([]byte("foo{}{bar}"))) % NumSlots))
+	// only the first pair counts
+	gomega.Expect(Slot("foo{bar}{zap}")).To(gomega.Equal(Slot("bar")))
+}
+
+// TestParseRedirect checks the parsing of the MOVED/ASK redirections.
+func TestParseRedirect(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	redirect, ok := ParseRedirect("MOVED 3999 127.0.0.1:6381")
+	gomega.Expect(ok).To(gomega.BeTrue())
+	gomega.Expect(redirect).To(gomega.Equal(Redirect{Slot: 3999, Addr: "127.0.0.1:6381"}))
+
+	redirect, ok = ParseRedirect("ASK 3999 127.0.0.1:6381")
+	gomega.Expect(ok).To(gomega.BeTrue())
+	gomega.Expect(redirect.Ask).To(gomega.BeTrue())
+
+	_, ok = ParseRedirect("ERR unknown command")
+	gomega.Expect(ok).To(gomega.BeFalse())
+	_, ok = ParseRedirect("MOVED 99999 127.0.0.1:6381")
+	gomega.Expect(ok).To(gomega.BeFalse())
+	_, ok = ParseRedirect("MOVED abc 127.0.0.1:6381")
+	gomega.Expect(ok).To(gomega.BeFalse())
+}
+
+// TestTopologyValidation checks the consistency checks of the topology
+// configuration.
+func TestTopologyValidation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	standalone := &Config{Endpoint: "127.0.0.1:6379"}
+	gomega.Expect(standalone.Topology()).To(gomega.Equal(TopologyStandalone))
+	gomega.Expect(standalone.Validate()).To(gomega.BeNil())
+
+	sentinel := &Config{Sentinel: &SentinelConfig{
+		MasterName: "mymaster",
+		Endpoints:  []string{"127.0.0.1:26379"},
+	}}
+	gomega.Expect(sentinel.Topology()).To(gomega.Equal(TopologySentinel))
+	gomega.Expect(sentinel.Validate()).To(gomega.BeNil())
+	gomega.Expect((&Config{Sentinel: &SentinelConfig{MasterName: "mymaster"}}).Validate()).
+		NotTo(gomega.BeNil())
+	gomega.Expect((&Config{Sentinel: &SentinelConfig{Endpoints: []string{"127.0.0.1:26379"}}}).Validate()).
+		NotTo(gomega.BeNil())
+
+	cluster := &Config{Cluster: &ClusterConfig{Endpoints: []string{"127.0.0.1:7000"}}}
+	gomega.Expect(cluster.Topology()).To(gomega.Equal(TopologyCluster))
+	gomega.Expect(cluster.Validate()).To(gomega.BeNil())
+	gomega.Expect((&Config{Cluster: &ClusterConfig{}}).Validate()).NotTo(gomega.BeNil())
+	gomega.Expect((&Config{DB: 1, Cluster: &ClusterConfig{Endpoints: []string{"127.0.0.1:7000"}}}).Validate()).
+		NotTo(gomega.BeNil())
+
+	both := &Config{Sentinel: sentinel.Sentinel, Cluster: cluster.Cluster}
+	gomega.Expect(both.Validate()).NotTo(gomega.BeNil())
+}
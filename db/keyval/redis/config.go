@@ -0,0 +1,140 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the Redis connection.
+type Config struct {
+	// Endpoint is the address of the Redis server. It is ignored when
+	// a Sentinel or Cluster topology is configured.
+	Endpoint string `json:"endpoint"`
+	// DB selects the Redis logical database. Redis Cluster supports
+	// only database 0.
+	DB int `json:"db"`
+	// Password authenticates the agent against the server.
+	Password string `json:"password"`
+	// Sentinel configures a Sentinel-managed failover topology.
+	// Mutually exclusive with Cluster.
+	Sentinel *SentinelConfig `json:"sentinel"`
+	// Cluster configures a Redis Cluster topology. Mutually exclusive
+	// with Sentinel.
+	Cluster *ClusterConfig `json:"cluster"`
+}
+
+// SentinelConfig holds the settings of a Sentinel-managed topology. The
+// connection asks the sentinels for the address of the current master
+// and follows the failovers.
+type SentinelConfig struct {
+	// MasterName is the name the master is registered under in the
+	// sentinels.
+	MasterName string `json:"master-name"`
+	// Endpoints are the addresses of the sentinels.
+	Endpoints []string `json:"endpoints"`
+}
+
+// ClusterConfig holds the settings of a Redis Cluster topology. The
+// connection routes each key to the node serving its hash slot and
+// follows the MOVED/ASK redirections.
+type ClusterConfig struct {
+	// Endpoints are the addresses of the seed nodes used to discover
+	// the cluster topology.
+	Endpoints []string `json:"endpoints"`
+	// MaxRedirects bounds the number of followed MOVED/ASK
+	// redirections of one command; defaultMaxRedirects when zero.
+	MaxRedirects int `json:"max-redirects"`
+}
+
+// Topology distinguishes the supported Redis deployment modes.
+type Topology string
+
+// The supported Redis deployment modes.
+const (
+	TopologyStandalone Topology = "standalone"
+	TopologySentinel   Topology = "sentinel"
+	TopologyCluster    Topology = "cluster"
+)
+
+const (
+	// defaultEndpoint is used when no endpoint is configured.
+	defaultEndpoint = "127.0.0.1:6379"
+	// defaultMaxRedirects bounds the followed redirections when the
+	// cluster configuration does not.
+	defaultMaxRedirects = 3
+)
+
+// Topology returns the deployment mode selected by the configuration.
+func (config *Config) Topology() Topology {
+	switch {
+	case config.Sentinel != nil:
+		return TopologySentinel
+	case config.Cluster != nil:
+		return TopologyCluster
+	default:
+		return TopologyStandalone
+	}
+}
+
+// Validate checks the consistency of the configuration.
+func (config *Config) Validate() error {
+	if config.Sentinel != nil && config.Cluster != nil {
+		return errors.New("sentinel and cluster topologies are mutually exclusive")
+	}
+	if config.Sentinel != nil {
+		if config.Sentinel.MasterName == "" {
+			return errors.New("sentinel topology requires master-name")
+		}
+		if len(config.Sentinel.Endpoints) == 0 {
+			return errors.New("sentinel topology requires at least one sentinel endpoint")
+		}
+	}
+	if config.Cluster != nil {
+		if len(config.Cluster.Endpoints) == 0 {
+			return errors.New("cluster topology requires at least one seed endpoint")
+		}
+		if config.DB != 0 {
+			return errors.New("cluster topology supports only database 0")
+		}
+	}
+	return nil
+}
+
+// ConfigFromFile loads the Redis configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Endpoint == "" && config.Topology() == TopologyStandalone {
+		config.Endpoint = defaultEndpoint
+	}
+	if config.Cluster != nil && config.Cluster.MaxRedirects == 0 {
+		config.Cluster.MaxRedirects = defaultMaxRedirects
+	}
+	return config, nil
+}
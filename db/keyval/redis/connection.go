@@ -0,0 +1,54 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import "github.com/ligato/cn-infra/db"
+
+// Notification is a keyspace event of one key, delivered to the
+// subscribers of a matching prefix.
+type Notification struct {
+	// Key the event is about.
+	Key string
+	// Op distinguishes a put from a delete.
+	Op db.PutDel
+}
+
+// Connection represents the connection to the Redis server. It is the
+// extension point between the broker and a concrete Redis client
+// library.
+type Connection interface {
+	// Set stores the value under the key.
+	Set(key string, value []byte) error
+	// Get retrieves the value of the key.
+	Get(key string) (value []byte, found bool, err error)
+	// Del removes the key; it returns true when the key existed.
+	Del(key string) (existed bool, err error)
+	// Scan returns the keys matching the prefix.
+	Scan(prefix string) (keys []string, err error)
+	// SubscribeKeyspaceEvents begins delivery of the keyspace events
+	// of the keys matching the prefix to notifChan.
+	SubscribeKeyspaceEvents(prefix string, notifChan chan Notification) error
+	// UnsubscribeKeyspaceEvents cancels the delivery of the keyspace
+	// events of the prefix.
+	UnsubscribeKeyspaceEvents(prefix string) error
+	// Close releases the resources of the connection.
+	Close() error
+}
+
+// ConnectionFactory creates the connection to the Redis server described
+// by the configuration. The embedding application is supposed to assign
+// a factory backed by a concrete Redis client library before the broker
+// is initialized from a config file.
+var ConnectionFactory func(config *Config) (Connection, error)
@@ -0,0 +1,194 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval/redis"
+)
+
+// FakeRedis is an in-memory implementation of redis.Connection. Set and
+// Del emit the keyspace notifications to the subscribed channels like a
+// Redis server with the notifications enabled, so the watch logic of the
+// broker can be tested too.
+//
+// The notifications are delivered synchronously from the Set and Del
+// calls; the watching tests should use buffered channels or concurrent
+// readers.
+type FakeRedis struct {
+	mu          sync.Mutex
+	data        map[string][]byte
+	subscribers map[string][]chan redis.Notification
+	err         error
+	closed      bool
+}
+
+// NewFakeRedis creates an empty fake server.
+func NewFakeRedis() *FakeRedis {
+	return &FakeRedis{
+		data:        map[string][]byte{},
+		subscribers: map[string][]chan redis.Notification{},
+	}
+}
+
+// SetError injects an error returned by all the subsequent calls; nil
+// removes the injection.
+func (fake *FakeRedis) SetError(err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.err = err
+}
+
+// IsClosed returns true after Close was called.
+func (fake *FakeRedis) IsClosed() bool {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return fake.closed
+}
+
+// Set stores the value under the key and notifies the subscribers.
+func (fake *FakeRedis) Set(key string, value []byte) error {
+	fake.mu.Lock()
+	if fake.err != nil {
+		fake.mu.Unlock()
+		return fake.err
+	}
+	stored := make([]byte, len(value))
+	copy(stored, value)
+	fake.data[key] = stored
+	receivers := fake.receiversFor(key)
+	fake.mu.Unlock()
+
+	fake.notify(receivers, redis.Notification{Key: key, Op: db.Put})
+	return nil
+}
+
+// Get retrieves the value of the key.
+func (fake *FakeRedis) Get(key string) (value []byte, found bool, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.err != nil {
+		return nil, false, fake.err
+	}
+	stored, found := fake.data[key]
+	if !found {
+		return nil, false, nil
+	}
+	value = make([]byte, len(stored))
+	copy(value, stored)
+	return value, true, nil
+}
+
+// Del removes the key and notifies the subscribers when it existed.
+func (fake *FakeRedis) Del(key string) (existed bool, err error) {
+	fake.mu.Lock()
+	if fake.err != nil {
+		fake.mu.Unlock()
+		return false, fake.err
+	}
+	_, existed = fake.data[key]
+	delete(fake.data, key)
+	var receivers []chan redis.Notification
+	if existed {
+		receivers = fake.receiversFor(key)
+	}
+	fake.mu.Unlock()
+
+	if existed {
+		fake.notify(receivers, redis.Notification{Key: key, Op: db.Delete})
+	}
+	return existed, nil
+}
+
+// Scan returns the keys matching the prefix.
+func (fake *FakeRedis) Scan(prefix string) (keys []string, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.err != nil {
+		return nil, fake.err
+	}
+	for key := range fake.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// SubscribeKeyspaceEvents begins delivery of the keyspace events of the
+// keys matching the prefix to notifChan.
+func (fake *FakeRedis) SubscribeKeyspaceEvents(prefix string, notifChan chan redis.Notification) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.err != nil {
+		return fake.err
+	}
+	fake.subscribers[prefix] = append(fake.subscribers[prefix], notifChan)
+	return nil
+}
+
+// UnsubscribeKeyspaceEvents cancels the delivery of the keyspace events
+// of the prefix and closes the subscribed channels.
+func (fake *FakeRedis) UnsubscribeKeyspaceEvents(prefix string) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	channels, found := fake.subscribers[prefix]
+	if !found {
+		return fmt.Errorf("prefix %q is not subscribed", prefix)
+	}
+	delete(fake.subscribers, prefix)
+	for _, notifChan := range channels {
+		close(notifChan)
+	}
+	return nil
+}
+
+// Close marks the connection closed and closes all the subscribed
+// channels.
+func (fake *FakeRedis) Close() error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.closed = true
+	for prefix, channels := range fake.subscribers {
+		delete(fake.subscribers, prefix)
+		for _, notifChan := range channels {
+			close(notifChan)
+		}
+	}
+	return nil
+}
+
+// receiversFor collects the channels subscribed to a prefix of the key.
+// The caller must hold the mutex.
+func (fake *FakeRedis) receiversFor(key string) []chan redis.Notification {
+	var receivers []chan redis.Notification
+	for prefix, channels := range fake.subscribers {
+		if strings.HasPrefix(key, prefix) {
+			receivers = append(receivers, channels...)
+		}
+	}
+	return receivers
+}
+
+// notify delivers the notification to the collected channels.
+func (fake *FakeRedis) notify(receivers []chan redis.Notification, notif redis.Notification) {
+	for _, notifChan := range receivers {
+		notifChan <- notif
+	}
+}
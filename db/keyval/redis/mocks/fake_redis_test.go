@@ -0,0 +1,175 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/db/keyval/redis"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestCrudThroughBroker(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), NewFakeRedis())
+
+	gomega.Expect(broker.Put("/agent/if/gbe0", []byte("up"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put("/agent/if/gbe1", []byte("down"))).To(gomega.BeNil())
+
+	data, found, rev, err := broker.GetValue("/agent/if/gbe0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(rev).To(gomega.BeEquivalentTo(0))
+	gomega.Expect(data).To(gomega.Equal([]byte("up")))
+
+	_, found, _, err = broker.GetValue("/agent/if/unknown")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeFalse())
+
+	keys, err := broker.ListKeys("/agent/if/")
+	gomega.Expect(err).To(gomega.BeNil())
+	key, _, allReceived := keys.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeFalse())
+	gomega.Expect(key).To(gomega.Equal("/agent/if/gbe0"))
+	key, _, allReceived = keys.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeFalse())
+	gomega.Expect(key).To(gomega.Equal("/agent/if/gbe1"))
+	_, _, allReceived = keys.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeTrue())
+
+	existed, err := broker.Delete("/agent/if/gbe1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(existed).To(gomega.BeTrue())
+	existed, err = broker.Delete("/agent/if/gbe1")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(existed).To(gomega.BeFalse())
+}
+
+func TestPluginBrokerTrimsPrefix(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	fake := NewFakeRedis()
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), fake)
+	plugin := broker.NewPluginBroker("/agent/")
+
+	gomega.Expect(plugin.Put("if/gbe0", []byte("up"))).To(gomega.BeNil())
+
+	// the key was stored with the plugin prefix
+	_, found, err := fake.Get("/agent/if/gbe0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(found).To(gomega.BeTrue())
+
+	pairs, err := plugin.ListValues("if/")
+	gomega.Expect(err).To(gomega.BeNil())
+	kv, allReceived := pairs.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeFalse())
+	gomega.Expect(kv.GetKey()).To(gomega.Equal("if/gbe0"))
+	gomega.Expect(kv.GetValue()).To(gomega.Equal([]byte("up")))
+	_, allReceived = pairs.GetNext()
+	gomega.Expect(allReceived).To(gomega.BeTrue())
+}
+
+func TestTxnThroughBroker(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), NewFakeRedis())
+	gomega.Expect(broker.Put("/agent/if/gbe0", []byte("up"))).To(gomega.BeNil())
+
+	txn := broker.NewTxn()
+	txn.Put("/agent/if/gbe1", []byte("down")).Delete("/agent/if/gbe0")
+	gomega.Expect(txn.Commit()).To(gomega.BeNil())
+
+	_, found, _, _ := broker.GetValue("/agent/if/gbe0")
+	gomega.Expect(found).To(gomega.BeFalse())
+	data, found, _, _ := broker.GetValue("/agent/if/gbe1")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data).To(gomega.Equal([]byte("down")))
+}
+
+func TestWatchThroughBroker(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), NewFakeRedis())
+
+	watchChan := make(chan keyval.BytesWatchResp, 10)
+	gomega.Expect(broker.Watch(watchChan, "/agent/if/")).To(gomega.BeNil())
+
+	gomega.Expect(broker.Put("/agent/if/gbe0", []byte("up"))).To(gomega.BeNil())
+	resp := expectWatchResp(watchChan)
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Put))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("/agent/if/gbe0"))
+	gomega.Expect(resp.GetValue()).To(gomega.Equal([]byte("up")))
+
+	// a key outside of the watched prefix is not reported
+	gomega.Expect(broker.Put("/agent/bd/bd1", []byte("cfg"))).To(gomega.BeNil())
+
+	_, err := broker.Delete("/agent/if/gbe0")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp = expectWatchResp(watchChan)
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Delete))
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("/agent/if/gbe0"))
+	gomega.Expect(resp.GetValue()).To(gomega.BeNil())
+}
+
+func TestWatchThroughPluginBroker(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), NewFakeRedis())
+	plugin := broker.NewPluginBroker("/agent/")
+
+	watchChan := make(chan keyval.BytesWatchResp, 10)
+	gomega.Expect(plugin.Watch(watchChan, "if/")).To(gomega.BeNil())
+
+	gomega.Expect(plugin.Put("if/gbe0", []byte("up"))).To(gomega.BeNil())
+	resp := expectWatchResp(watchChan)
+	gomega.Expect(resp.GetChangeType()).To(gomega.Equal(db.Put))
+
+	// the plugin prefix is trimmed from the reported key
+	gomega.Expect(resp.GetKey()).To(gomega.Equal("if/gbe0"))
+}
+
+func TestInjectedConnectionError(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	fake := NewFakeRedis()
+	broker := redis.NewBytesBrokerUsingConnection(logrus.StandardLogger(), fake)
+
+	fake.SetError(errors.New("connection is down"))
+	gomega.Expect(broker.Put("/agent/if/gbe0", []byte("up"))).NotTo(gomega.BeNil())
+	_, _, _, err := broker.GetValue("/agent/if/gbe0")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	_, err = broker.ListKeys("/agent/if/")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	fake.SetError(nil)
+	gomega.Expect(broker.Put("/agent/if/gbe0", []byte("up"))).To(gomega.BeNil())
+}
+
+// expectWatchResp waits for the delivery of one watch response.
+func expectWatchResp(watchChan chan keyval.BytesWatchResp) keyval.BytesWatchResp {
+	select {
+	case resp := <-watchChan:
+		return resp
+	case <-time.After(time.Second):
+		gomega.Expect(false).To(gomega.BeTrue(), "timeout waiting for a watch response")
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package consul implements registration of the agent in the Consul
+// catalog and discovery of other services. The agent is registered with
+// an HTTP health check (typically the liveness probe of the probe
+// plugin) on Init and deregistered on Close, so the catalog follows the
+// agent lifecycle. Other plugins (gRPC clients, Kafka) can resolve the
+// addresses of the healthy instances of their peers.
+package consul
@@ -0,0 +1,299 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// requestTimeout bounds every request to the Consul agent.
+const requestTimeout = 10 * time.Second
+
+// defaultCheckInterval is used when no health check interval is
+// configured.
+const defaultCheckInterval = 10 * time.Second
+
+// Config holds the settings of the Consul registration.
+type Config struct {
+	// Address is the URL of the local Consul agent, e.g.
+	// "http://127.0.0.1:8500".
+	Address string `json:"address"`
+	// Token authenticates the agent against Consul.
+	Token string `json:"token"`
+	// ServiceName is the name the agent is registered under in the
+	// catalog.
+	ServiceName string `json:"service-name"`
+	// ServiceID distinguishes multiple instances of the service;
+	// ServiceName when empty.
+	ServiceID string `json:"service-id"`
+	// ServiceAddress is the address the service is reachable on; the
+	// node address of the Consul agent when empty.
+	ServiceAddress string `json:"service-address"`
+	// ServicePort is the port the service is reachable on.
+	ServicePort int `json:"service-port"`
+	// Tags are attached to the registration.
+	Tags []string `json:"tags"`
+	// CheckURL is the URL of the HTTP health check, typically the
+	// liveness probe of the probe plugin on the shared HTTP server.
+	// No health check is registered when empty.
+	CheckURL string `json:"check-url"`
+	// CheckInterval is the period of the health check;
+	// defaultCheckInterval when zero.
+	CheckInterval time.Duration `json:"check-interval"`
+	// DeregisterAfter makes Consul drop the registration once the check
+	// has been failing for the given duration, so crashed agents do not
+	// linger in the catalog. Zero keeps the registration.
+	DeregisterAfter time.Duration `json:"deregister-after"`
+}
+
+// ConfigFromFile loads the Consul configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ServiceInstance is one healthy instance of a resolved service.
+type ServiceInstance struct {
+	// Address is the address the instance is reachable on.
+	Address string
+	// Port is the port the instance is reachable on.
+	Port int
+	// Tags are the tags of the registration.
+	Tags []string
+}
+
+// Plugin registers the agent in the Consul catalog and resolves the
+// addresses of other services. It implements the core.Plugin interface,
+// therefore it can be loaded with other plugins.
+type Plugin struct {
+	log    logging.Logger
+	config *Config
+	client *http.Client
+
+	registered bool
+}
+
+// NewPlugin creates a new instance of the Consul discovery plugin.
+func NewPlugin(log logging.Logger, config *Config) *Plugin {
+	return &Plugin{
+		log:    log,
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Init validates the configuration and registers the agent in the
+// catalog.
+func (plugin *Plugin) Init() error {
+	if plugin.config.Address == "" {
+		return errors.New("the address of the Consul agent is not configured")
+	}
+	if plugin.config.ServiceName == "" {
+		return errors.New("the service name is not configured")
+	}
+	if err := plugin.register(); err != nil {
+		return err
+	}
+	plugin.registered = true
+	plugin.log.WithFields(logging.Fields{
+		"service": plugin.config.ServiceName,
+		"id":      plugin.serviceID(),
+	}).Info("Registered the agent in the Consul catalog")
+	return nil
+}
+
+// Close deregisters the agent from the catalog.
+func (plugin *Plugin) Close() error {
+	if !plugin.registered {
+		return nil
+	}
+	plugin.registered = false
+	return plugin.deregister()
+}
+
+// Resolve returns the healthy instances of the service.
+func (plugin *Plugin) Resolve(service string) ([]ServiceInstance, error) {
+	req, err := http.NewRequest("GET", plugin.url("v1/health/service/"+service)+"?passing=true", nil)
+	if err != nil {
+		return nil, err
+	}
+	plugin.authorize(req)
+
+	resp, err := plugin.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Consul responded with status %s to the resolution of %s", resp.Status, service)
+	}
+
+	var entries []struct {
+		Node struct {
+			Address string `json:"Address"`
+		} `json:"Node"`
+		Service struct {
+			Address string   `json:"Address"`
+			Port    int      `json:"Port"`
+			Tags    []string `json:"Tags"`
+		} `json:"Service"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	instances := make([]ServiceInstance, 0, len(entries))
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if address == "" {
+			// services registered without an address inherit the one
+			// of their node
+			address = entry.Node.Address
+		}
+		instances = append(instances, ServiceInstance{
+			Address: address,
+			Port:    entry.Service.Port,
+			Tags:    entry.Service.Tags,
+		})
+	}
+	return instances, nil
+}
+
+// ResolveAddrs returns the healthy instances of the service as
+// "host:port" addresses, in the form the gRPC and Kafka plugins expect
+// their endpoints.
+func (plugin *Plugin) ResolveAddrs(service string) ([]string, error) {
+	instances, err := plugin.Resolve(service)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		addrs = append(addrs, fmt.Sprintf("%s:%d", instance.Address, instance.Port))
+	}
+	return addrs, nil
+}
+
+// registration is the JSON body of the service registration.
+type registration struct {
+	ID      string             `json:"ID"`
+	Name    string             `json:"Name"`
+	Address string             `json:"Address,omitempty"`
+	Port    int                `json:"Port,omitempty"`
+	Tags    []string           `json:"Tags,omitempty"`
+	Check   *registrationCheck `json:"Check,omitempty"`
+}
+
+// registrationCheck is the health check part of the registration.
+type registrationCheck struct {
+	HTTP                           string `json:"HTTP"`
+	Interval                       string `json:"Interval"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter,omitempty"`
+}
+
+// register puts the agent into the catalog.
+func (plugin *Plugin) register() error {
+	reg := &registration{
+		ID:      plugin.serviceID(),
+		Name:    plugin.config.ServiceName,
+		Address: plugin.config.ServiceAddress,
+		Port:    plugin.config.ServicePort,
+		Tags:    plugin.config.Tags,
+	}
+	if plugin.config.CheckURL != "" {
+		interval := plugin.config.CheckInterval
+		if interval == 0 {
+			interval = defaultCheckInterval
+		}
+		reg.Check = &registrationCheck{
+			HTTP:     plugin.config.CheckURL,
+			Interval: interval.String(),
+		}
+		if plugin.config.DeregisterAfter > 0 {
+			reg.Check.DeregisterCriticalServiceAfter = plugin.config.DeregisterAfter.String()
+		}
+	}
+
+	body, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+	return plugin.put("v1/agent/service/register", body)
+}
+
+// deregister removes the agent from the catalog.
+func (plugin *Plugin) deregister() error {
+	return plugin.put("v1/agent/service/deregister/"+plugin.serviceID(), nil)
+}
+
+// put performs one PUT API call.
+func (plugin *Plugin) put(path string, body []byte) error {
+	req, err := http.NewRequest("PUT", plugin.url(path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	plugin.authorize(req)
+
+	resp, err := plugin.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Consul responded with status %s to %s", resp.Status, path)
+	}
+	return nil
+}
+
+// authorize attaches the token to the request.
+func (plugin *Plugin) authorize(req *http.Request) {
+	if plugin.config.Token != "" {
+		req.Header.Set("X-Consul-Token", plugin.config.Token)
+	}
+}
+
+// serviceID returns the identifier of the registration.
+func (plugin *Plugin) serviceID() string {
+	if plugin.config.ServiceID != "" {
+		return plugin.config.ServiceID
+	}
+	return plugin.config.ServiceName
+}
+
+// url composes the URL of one API call.
+func (plugin *Plugin) url(path string) string {
+	return strings.TrimRight(plugin.config.Address, "/") + "/" + path
+}
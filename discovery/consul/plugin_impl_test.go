@@ -0,0 +1,144 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeConsul is an HTTP test double of the Consul agent API.
+type fakeConsul struct {
+	server *httptest.Server
+
+	registrations   []registration
+	deregistrations []string
+	lastToken       string
+}
+
+func newFakeConsul() *fakeConsul {
+	fake := &fakeConsul{}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	return fake
+}
+
+func (fake *fakeConsul) handle(w http.ResponseWriter, req *http.Request) {
+	fake.lastToken = req.Header.Get("X-Consul-Token")
+	switch {
+	case req.URL.Path == "/v1/agent/service/register":
+		reg := registration{}
+		if err := json.NewDecoder(req.Body).Decode(&reg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fake.registrations = append(fake.registrations, reg)
+	case req.URL.Path == "/v1/agent/service/deregister/vpp-agent-1":
+		fake.deregistrations = append(fake.deregistrations, "vpp-agent-1")
+	case req.URL.Path == "/v1/health/service/kafka":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"Node": {"Address": "10.0.0.1"}, "Service": {"Address": "", "Port": 9092, "Tags": ["broker"]}},
+			{"Node": {"Address": "10.0.0.2"}, "Service": {"Address": "192.168.1.2", "Port": 9092}}
+		]`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestPlugin creates an initialized plugin talking to the fake.
+func newTestPlugin(t *testing.T, fake *fakeConsul) *Plugin {
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{
+		Address:         fake.server.URL,
+		Token:           "secret",
+		ServiceName:     "vpp-agent",
+		ServiceID:       "vpp-agent-1",
+		ServiceAddress:  "10.0.0.1",
+		ServicePort:     9111,
+		Tags:            []string{"vpp"},
+		CheckURL:        "http://10.0.0.1:9191/liveness",
+		CheckInterval:   5 * time.Second,
+		DeregisterAfter: time.Minute,
+	})
+	if err := plugin.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return plugin
+}
+
+// TestRegistrationLifecycle checks that Init registers the agent with
+// the health check and Close deregisters it.
+func TestRegistrationLifecycle(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := newFakeConsul()
+	defer fake.server.Close()
+
+	plugin := newTestPlugin(t, fake)
+	gomega.Expect(fake.registrations).To(gomega.HaveLen(1))
+	reg := fake.registrations[0]
+	gomega.Expect(reg.ID).To(gomega.BeEquivalentTo("vpp-agent-1"))
+	gomega.Expect(reg.Name).To(gomega.BeEquivalentTo("vpp-agent"))
+	gomega.Expect(reg.Port).To(gomega.BeEquivalentTo(9111))
+	gomega.Expect(reg.Check).NotTo(gomega.BeNil())
+	gomega.Expect(reg.Check.HTTP).To(gomega.BeEquivalentTo("http://10.0.0.1:9191/liveness"))
+	gomega.Expect(reg.Check.Interval).To(gomega.BeEquivalentTo("5s"))
+	gomega.Expect(reg.Check.DeregisterCriticalServiceAfter).To(gomega.BeEquivalentTo("1m0s"))
+	gomega.Expect(fake.lastToken).To(gomega.BeEquivalentTo("secret"))
+
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+	gomega.Expect(fake.deregistrations).To(gomega.Equal([]string{"vpp-agent-1"}))
+}
+
+// TestResolve checks the resolution of the healthy instances, including
+// the fallback to the node address.
+func TestResolve(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := newFakeConsul()
+	defer fake.server.Close()
+
+	plugin := newTestPlugin(t, fake)
+	defer plugin.Close()
+
+	instances, err := plugin.Resolve("kafka")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(instances).To(gomega.HaveLen(2))
+	gomega.Expect(instances[0].Address).To(gomega.BeEquivalentTo("10.0.0.1"))
+	gomega.Expect(instances[0].Tags).To(gomega.Equal([]string{"broker"}))
+	gomega.Expect(instances[1].Address).To(gomega.BeEquivalentTo("192.168.1.2"))
+
+	addrs, err := plugin.ResolveAddrs("kafka")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(addrs).To(gomega.Equal([]string{"10.0.0.1:9092", "192.168.1.2:9092"}))
+
+	_, err = plugin.Resolve("unknown")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+// TestInitValidation checks that Init fails without the mandatory
+// settings.
+func TestInitValidation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{ServiceName: "vpp-agent"})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+
+	plugin = NewPlugin(logrus.StandardLogger(), &Config{Address: "http://127.0.0.1:8500"})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dnssrv resolves service endpoints from DNS SRV records.
+// The etcd and Kafka plugins use it to discover their cluster members
+// from a single configured name (e.g. a Kubernetes headless service)
+// instead of a static endpoint list, and to follow the membership
+// changes by periodic re-resolution.
+package dnssrv
@@ -0,0 +1,128 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnssrv
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// Config holds the settings of one SRV-resolved endpoint list.
+type Config struct {
+	// Name is the SRV name to resolve, e.g.
+	// "_etcd-client._tcp.etcd.default.svc.cluster.local".
+	Name string `json:"name"`
+	// PollPeriod is the interval of the re-resolution. Zero resolves
+	// the name only once, at startup.
+	PollPeriod time.Duration `json:"poll-period"`
+}
+
+// LookupSRV resolves an SRV name. It is a seam for the tests; the
+// default delegates to the system resolver.
+var LookupSRV = func(name string) ([]*net.SRV, error) {
+	_, records, err := net.LookupSRV("", "", name)
+	return records, err
+}
+
+// Resolve returns the "host:port" endpoints of the SRV name, ordered by
+// priority and descending weight, so equal record sets always yield the
+// same endpoint list.
+func Resolve(name string) ([]string, error) {
+	records, err := LookupSRV(name)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Priority != records[j].Priority {
+			return records[i].Priority < records[j].Priority
+		}
+		if records[i].Weight != records[j].Weight {
+			return records[i].Weight > records[j].Weight
+		}
+		return records[i].Target < records[j].Target
+	})
+	endpoints := make([]string, 0, len(records))
+	for _, record := range records {
+		endpoints = append(endpoints,
+			fmt.Sprintf("%s:%d", strings.TrimSuffix(record.Target, "."), record.Port))
+	}
+	return endpoints, nil
+}
+
+// Poll re-resolves the name with the configured period and invokes
+// update with the new endpoint list whenever it differs from the
+// previous one. A non-positive period resolves the name only once, at
+// startup. The polling stops when closeCh is closed. Resolution
+// failures are logged and the previous endpoints stay in use.
+//
+// The returned channel is closed once the polling goroutine has exited
+// and no further update can fire; wait on it before tearing down the
+// state shared with the update callback.
+func Poll(log logging.Logger, config *Config, closeCh chan struct{}, update func(endpoints []string)) (done <-chan struct{}) {
+	stopped := make(chan struct{})
+	previous, err := Resolve(config.Name)
+	if err != nil {
+		log.Warn("Failed to resolve the SRV name ", config.Name, ": ", err)
+	}
+	if config.PollPeriod <= 0 {
+		close(stopped)
+		return stopped
+	}
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(config.PollPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closeCh:
+				return
+			case <-ticker.C:
+				endpoints, err := Resolve(config.Name)
+				if err != nil {
+					log.Warn("Failed to resolve the SRV name ", config.Name, ": ", err)
+					continue
+				}
+				if equal(endpoints, previous) {
+					continue
+				}
+				previous = endpoints
+				log.WithFields(logging.Fields{
+					"name":      config.Name,
+					"endpoints": endpoints,
+				}).Info("SRV endpoints changed")
+				update(endpoints)
+			}
+		}
+	}()
+	return stopped
+}
+
+// equal reports whether the endpoint lists are the same.
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
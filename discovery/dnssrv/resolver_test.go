@@ -0,0 +1,148 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnssrv
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeLookup replaces the system resolver for the duration of the test.
+type fakeLookup struct {
+	mu      sync.Mutex
+	records []*net.SRV
+	err     error
+}
+
+func (fake *fakeLookup) install() (restore func()) {
+	previous := LookupSRV
+	LookupSRV = func(name string) ([]*net.SRV, error) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.records, fake.err
+	}
+	return func() { LookupSRV = previous }
+}
+
+func (fake *fakeLookup) set(records []*net.SRV, err error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.records = records
+	fake.err = err
+}
+
+// TestResolveOrdering checks that the endpoints are ordered by priority
+// and descending weight.
+func TestResolveOrdering(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := &fakeLookup{}
+	defer fake.install()()
+	fake.set([]*net.SRV{
+		{Target: "etcd-2.etcd.", Port: 2379, Priority: 10, Weight: 50},
+		{Target: "etcd-0.etcd.", Port: 2379, Priority: 0, Weight: 10},
+		{Target: "etcd-1.etcd.", Port: 2379, Priority: 10, Weight: 100},
+	}, nil)
+
+	endpoints, err := Resolve("_etcd-client._tcp.etcd")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(endpoints).To(gomega.Equal([]string{
+		"etcd-0.etcd:2379", "etcd-1.etcd:2379", "etcd-2.etcd:2379",
+	}))
+}
+
+// TestResolveFailure checks that a resolution failure is propagated.
+func TestResolveFailure(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := &fakeLookup{}
+	defer fake.install()()
+	fake.set(nil, errors.New("no such host"))
+
+	_, err := Resolve("_etcd-client._tcp.etcd")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+// TestPoll checks that the update callback is invoked only when the
+// resolved endpoints change.
+func TestPoll(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := &fakeLookup{}
+	defer fake.install()()
+	fake.set([]*net.SRV{{Target: "kafka-0.", Port: 9092}}, nil)
+
+	var mu sync.Mutex
+	var updates [][]string
+	closeCh := make(chan struct{})
+	done := Poll(logrus.StandardLogger(), &Config{
+		Name:       "_kafka._tcp.kafka",
+		PollPeriod: 10 * time.Millisecond,
+	}, closeCh, func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, endpoints)
+	})
+	// join the polling goroutine before the seam is restored
+	defer func() {
+		close(closeCh)
+		<-done
+	}()
+
+	// the unchanged record set does not trigger an update
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	gomega.Expect(updates).To(gomega.BeEmpty())
+	mu.Unlock()
+
+	fake.set([]*net.SRV{{Target: "kafka-0.", Port: 9092}, {Target: "kafka-1.", Port: 9092}}, nil)
+	gomega.Eventually(func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([][]string(nil), updates...)
+	}).Should(gomega.Equal([][]string{{"kafka-0:9092", "kafka-1:9092"}}))
+}
+
+// TestPollZeroPeriod checks that a zero period resolves the name only
+// once and never invokes the update callback afterwards.
+func TestPollZeroPeriod(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	fake := &fakeLookup{}
+	defer fake.install()()
+	fake.set([]*net.SRV{{Target: "kafka-0.", Port: 9092}}, nil)
+
+	var mu sync.Mutex
+	var updates [][]string
+	closeCh := make(chan struct{})
+	defer close(closeCh)
+	done := Poll(logrus.StandardLogger(), &Config{
+		Name: "_kafka._tcp.kafka",
+	}, closeCh, func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, endpoints)
+	})
+	// the done channel of a one-shot resolution is already closed
+	gomega.Expect(done).To(gomega.BeClosed())
+
+	fake.set([]*net.SRV{{Target: "kafka-1.", Port: 9092}}, nil)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	gomega.Expect(updates).To(gomega.BeEmpty())
+	mu.Unlock()
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package featureflags implements a plugin that watches a dedicated
+// key-value prefix and exposes the stored flags to the other plugins of
+// the agent. Experimental behaviors guarded by IsEnabled can be toggled
+// at runtime per agent or per fleet by writing to the datastore, without
+// a redeploy; change callbacks let the plugins react to a toggle
+// immediately.
+package featureflags
@@ -0,0 +1,203 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// FlagsPrefix is the well-known key prefix the flags are stored under,
+// one key per flag. A flag is enabled when its value reads "true", "1"
+// or "on" (case-insensitive); any other value and a missing key fall
+// back to the registered default.
+const FlagsPrefix = "flags/"
+
+// ChangeCallback is invoked after the state of a watched flag changed.
+type ChangeCallback func(flag string, enabled bool)
+
+// Broker is the keyval access the plugin needs: the initial listing and
+// the watch of the flags prefix. The prefixed brokers of the keyval
+// plugins satisfy it.
+type Broker interface {
+	keyval.BytesBroker
+	keyval.BytesWatcher
+}
+
+// Plugin exposes the feature flags of the agent. It implements the
+// core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log    logging.Logger
+	broker Broker
+
+	mu        sync.RWMutex
+	flags     map[string]bool
+	defaults  map[string]bool
+	callbacks map[string][]ChangeCallback
+
+	respChan chan keyval.BytesWatchResp
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the plugin on top of the broker,
+// typically the broker prefixed with the prefix of the agent.
+func NewPlugin(log logging.Logger, broker Broker) *Plugin {
+	return &Plugin{
+		log:       log,
+		broker:    broker,
+		flags:     map[string]bool{},
+		defaults:  map[string]bool{},
+		callbacks: map[string][]ChangeCallback{},
+		respChan:  make(chan keyval.BytesWatchResp, 10),
+		closeCh:   make(chan struct{}),
+	}
+}
+
+// SetDefault registers the state a flag reports while the datastore
+// holds no value for it. Call it before Init.
+func (plugin *Plugin) SetDefault(flag string, enabled bool) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	plugin.defaults[flag] = enabled
+}
+
+// Init loads the stored flags and starts watching the prefix.
+func (plugin *Plugin) Init() error {
+	iterator, err := plugin.broker.ListValues(FlagsPrefix)
+	if err != nil {
+		return err
+	}
+	for {
+		kv, allReceived := iterator.GetNext()
+		if allReceived {
+			break
+		}
+		plugin.flags[flagName(kv.GetKey())] = parseEnabled(kv.GetValue())
+	}
+	if err := plugin.broker.Watch(plugin.respChan, FlagsPrefix); err != nil {
+		return err
+	}
+	plugin.wg.Add(1)
+	go plugin.watchFlags()
+	return nil
+}
+
+// Close stops the watching.
+func (plugin *Plugin) Close() error {
+	close(plugin.closeCh)
+	plugin.wg.Wait()
+	return nil
+}
+
+// IsEnabled returns the current state of the flag, the registered
+// default when the datastore holds no value and false otherwise.
+func (plugin *Plugin) IsEnabled(flag string) bool {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+	if enabled, stored := plugin.flags[flag]; stored {
+		return enabled
+	}
+	return plugin.defaults[flag]
+}
+
+// ListFlags returns the current state of every known flag, the stored
+// ones merged over the registered defaults.
+func (plugin *Plugin) ListFlags() map[string]bool {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+	flags := map[string]bool{}
+	for flag, enabled := range plugin.defaults {
+		flags[flag] = enabled
+	}
+	for flag, enabled := range plugin.flags {
+		flags[flag] = enabled
+	}
+	return flags
+}
+
+// OnChange registers a callback invoked after the state of the flag
+// changed; an empty flag name subscribes to all flags. The callbacks
+// run on the watch goroutine, so they must not block.
+func (plugin *Plugin) OnChange(flag string, callback ChangeCallback) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	plugin.callbacks[flag] = append(plugin.callbacks[flag], callback)
+}
+
+// watchFlags applies the change events of the flags prefix.
+func (plugin *Plugin) watchFlags() {
+	defer plugin.wg.Done()
+	for {
+		select {
+		case <-plugin.closeCh:
+			return
+		case resp := <-plugin.respChan:
+			plugin.applyChange(resp)
+		}
+	}
+}
+
+// applyChange updates the flag of the event and fires the callbacks
+// when its effective state changed.
+func (plugin *Plugin) applyChange(resp keyval.BytesWatchResp) {
+	flag := flagName(resp.GetKey())
+
+	plugin.mu.Lock()
+	before := plugin.flags[flag]
+	if _, stored := plugin.flags[flag]; !stored {
+		before = plugin.defaults[flag]
+	}
+	var after bool
+	if resp.GetChangeType() == db.Delete {
+		delete(plugin.flags, flag)
+		after = plugin.defaults[flag]
+	} else {
+		after = parseEnabled(resp.GetValue())
+		plugin.flags[flag] = after
+	}
+	var callbacks []ChangeCallback
+	if before != after {
+		callbacks = append(callbacks, plugin.callbacks[flag]...)
+		callbacks = append(callbacks, plugin.callbacks[""]...)
+	}
+	plugin.mu.Unlock()
+
+	if before != after {
+		plugin.log.WithFields(logging.Fields{"flag": flag, "enabled": after}).
+			Info("Feature flag changed")
+	}
+	for _, callback := range callbacks {
+		callback(flag, after)
+	}
+}
+
+// flagName strips the flags prefix from the key of an event.
+func flagName(key string) string {
+	return strings.TrimPrefix(key, FlagsPrefix)
+}
+
+// parseEnabled interprets the stored value of a flag.
+func parseEnabled(value []byte) bool {
+	switch strings.ToLower(strings.TrimSpace(string(value))) {
+	case "true", "1", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflags
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/datasync/datasynctest"
+	"github.com/ligato/cn-infra/db"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// flagsBroker combines the in-memory broker with a manual watch seam.
+type flagsBroker struct {
+	*datasynctest.MemBroker
+	respChan chan keyval.BytesWatchResp
+}
+
+func (broker *flagsBroker) Watch(respChan chan keyval.BytesWatchResp, keys ...string) error {
+	broker.respChan = respChan
+	return nil
+}
+
+// flagEvent is one emitted change event of a flag key.
+type flagEvent struct {
+	key        string
+	value      []byte
+	changeType db.PutDel
+}
+
+func (event *flagEvent) GetKey() string           { return event.key }
+func (event *flagEvent) GetValue() []byte         { return event.value }
+func (event *flagEvent) GetChangeType() db.PutDel { return event.changeType }
+func (event *flagEvent) GetRevision() int64       { return 0 }
+
+// newTestPlugin builds an initialized plugin over a broker prefilled
+// with one enabled flag.
+func newTestPlugin(t *testing.T) (*Plugin, *flagsBroker) {
+	broker := &flagsBroker{MemBroker: datasynctest.NewMemBroker()}
+	gomega.Expect(broker.Put(FlagsPrefix+"new-parser", []byte("true"))).To(gomega.BeNil())
+	gomega.Expect(broker.Put(FlagsPrefix+"slow-path", []byte("off"))).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), broker)
+	plugin.SetDefault("tracing", true)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	return plugin, broker
+}
+
+// TestInitialFlags checks the states loaded from the datastore and the
+// registered defaults.
+func TestInitialFlags(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin, _ := newTestPlugin(t)
+	defer plugin.Close()
+
+	gomega.Expect(plugin.IsEnabled("new-parser")).To(gomega.BeTrue())
+	gomega.Expect(plugin.IsEnabled("slow-path")).To(gomega.BeFalse())
+	// the default applies while the datastore holds no value
+	gomega.Expect(plugin.IsEnabled("tracing")).To(gomega.BeTrue())
+	// unknown flags are disabled
+	gomega.Expect(plugin.IsEnabled("unknown")).To(gomega.BeFalse())
+
+	gomega.Expect(plugin.ListFlags()).To(gomega.Equal(map[string]bool{
+		"new-parser": true,
+		"slow-path":  false,
+		"tracing":    true,
+	}))
+}
+
+// TestRuntimeToggle checks that a change event flips the flag and fires
+// the callbacks.
+func TestRuntimeToggle(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin, broker := newTestPlugin(t)
+	defer plugin.Close()
+
+	var mu sync.Mutex
+	var notified []string
+	plugin.OnChange("new-parser", func(flag string, enabled bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, flag)
+		gomega.Expect(enabled).To(gomega.BeFalse())
+	})
+
+	broker.respChan <- &flagEvent{key: FlagsPrefix + "new-parser", value: []byte("false")}
+	gomega.Eventually(func() bool {
+		return plugin.IsEnabled("new-parser")
+	}, time.Second).Should(gomega.BeFalse())
+	gomega.Eventually(func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return notified
+	}, time.Second).Should(gomega.Equal([]string{"new-parser"}))
+}
+
+// TestDeleteFallsBackToDefault checks that removing the key restores
+// the registered default.
+func TestDeleteFallsBackToDefault(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin, broker := newTestPlugin(t)
+	defer plugin.Close()
+
+	broker.respChan <- &flagEvent{key: FlagsPrefix + "tracing", value: []byte("false")}
+	gomega.Eventually(func() bool {
+		return plugin.IsEnabled("tracing")
+	}, time.Second).Should(gomega.BeFalse())
+
+	broker.respChan <- &flagEvent{key: FlagsPrefix + "tracing", changeType: db.Delete}
+	gomega.Eventually(func() bool {
+		return plugin.IsEnabled("tracing")
+	}, time.Second).Should(gomega.BeTrue())
+}
+
+// TestWildcardCallback checks the subscription to all flags and that an
+// unchanged state fires no callback.
+func TestWildcardCallback(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin, broker := newTestPlugin(t)
+	defer plugin.Close()
+
+	var mu sync.Mutex
+	var notified []string
+	plugin.OnChange("", func(flag string, enabled bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, flag)
+	})
+
+	// rewriting the same state is not a change
+	broker.respChan <- &flagEvent{key: FlagsPrefix + "new-parser", value: []byte("true")}
+	broker.respChan <- &flagEvent{key: FlagsPrefix + "slow-path", value: []byte("on")}
+
+	gomega.Eventually(func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return notified
+	}, time.Second).Should(gomega.Equal([]string{"slow-path"}))
+}
+
+// TestParseEnabled checks the accepted value spellings.
+func TestParseEnabled(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	for _, value := range []string{"true", "TRUE", " 1 ", "on", "On"} {
+		gomega.Expect(parseEnabled([]byte(value))).To(gomega.BeTrue(), value)
+	}
+	for _, value := range []string{"", "false", "0", "off", "garbage"} {
+		gomega.Expect(parseEnabled([]byte(value))).To(gomega.BeFalse(), value)
+	}
+}
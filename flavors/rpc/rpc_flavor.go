@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpc defines the flavor of the management plugins: REST and gRPC
+// servers together with the log manager, status check, probes and metrics.
+package rpc
+
+import (
+	"github.com/ligato/cn-infra/core"
+	"github.com/ligato/cn-infra/health/statuscheck"
+	"github.com/ligato/cn-infra/logging/logmanager"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/grpc"
+	"github.com/ligato/cn-infra/rpc/probe"
+	"github.com/ligato/cn-infra/rpc/prometheus"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// FlavorRPC glues together the management plugins of an agent. An
+// application can embed the flavor and override individual plugins by
+// setting the fields before the first use.
+type FlavorRPC struct {
+	HTTP        *rest.Plugin
+	LogManager  *logmanager.Plugin
+	StatusCheck *statuscheck.Plugin
+	Probe       *probe.Plugin
+	Prometheus  *prometheus.Plugin
+	GRPC        *grpc.Plugin
+
+	injected bool
+}
+
+// Inject creates the plugins that were not set by the application and
+// wires the dependencies between them. It returns false when it was
+// called already.
+func (flavor *FlavorRPC) Inject() bool {
+	if flavor.injected {
+		return false
+	}
+	flavor.injected = true
+
+	log := logrus.StandardLogger()
+	if flavor.HTTP == nil {
+		flavor.HTTP = rest.NewPlugin(log, "")
+	}
+	if flavor.LogManager == nil {
+		flavor.LogManager = logmanager.NewPlugin(log, logrus.LoggerRegistry, flavor.HTTP)
+	}
+	if flavor.StatusCheck == nil {
+		flavor.StatusCheck = statuscheck.NewPlugin(log)
+	}
+	if flavor.Probe == nil {
+		flavor.Probe = probe.NewPlugin(log, flavor.HTTP, flavor.StatusCheck, nil)
+	}
+	if flavor.Prometheus == nil {
+		flavor.Prometheus = prometheus.NewPlugin(log, flavor.HTTP)
+	}
+	if flavor.GRPC == nil {
+		flavor.GRPC = grpc.NewPlugin(log, "")
+	}
+	return true
+}
+
+// Plugins returns the plugins of the flavor in the initialization order.
+func (flavor *FlavorRPC) Plugins() []*core.NamedPlugin {
+	flavor.Inject()
+	return core.ListPluginsInFlavor(flavor)
+}
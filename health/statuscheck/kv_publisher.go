@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// defaultPublishInterval is the period of writing the status records
+// to the KV store.
+const defaultPublishInterval = 30 * time.Second
+
+// Build information of the agent, to be overridden during the build
+// with ldflags and included in the published status records.
+var (
+	BuildVersion string
+	BuildDate    string
+)
+
+// publishedAgentStatus is the JSON record of the overall agent status
+// written to the KV store.
+type publishedAgentStatus struct {
+	AgentStatus
+	BuildVersion string `json:"build_version,omitempty"`
+	BuildDate    string `json:"build_date,omitempty"`
+}
+
+// EnableKVPublishing makes the plugin periodically write the per-plugin
+// and overall status records to the KV store under the service label of
+// the agent (/<service-label>/check/status/...). It must be called before
+// Init.
+func (plugin *Plugin) EnableKVPublishing(broker keyval.BytesBroker, serviceLabel string) {
+	plugin.broker = broker
+	plugin.statusKeyPrefix = "/" + serviceLabel + "/check/status/v1/"
+}
+
+// periodicPublishing writes the status records to the KV store until
+// the plugin is closed.
+func (plugin *Plugin) periodicPublishing() {
+	defer plugin.wg.Done()
+
+	for {
+		select {
+		case <-time.After(plugin.publishInterval):
+			plugin.publishStatus()
+		case <-plugin.closeChan:
+			return
+		}
+	}
+}
+
+// publishStatus writes the overall and the per-plugin status records
+// to the KV store.
+func (plugin *Plugin) publishStatus() {
+	agent := publishedAgentStatus{
+		AgentStatus:  plugin.GetAgentStatus(),
+		BuildVersion: BuildVersion,
+		BuildDate:    BuildDate,
+	}
+	plugin.publishRecord("agent", agent)
+	for name, status := range plugin.GetAllPluginStatus() {
+		plugin.publishRecord("plugin/"+name, status)
+	}
+}
+
+// publishRecord writes one JSON status record to the KV store.
+func (plugin *Plugin) publishRecord(suffix string, record interface{}) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		plugin.log.Error("Failed to marshal status record: ", err)
+		return
+	}
+	if err := plugin.broker.Put(plugin.statusKeyPrefix+suffix, data); err != nil {
+		plugin.log.Error("Failed to publish status record: ", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// mockBroker is an in-memory BytesBroker recording the published records.
+type mockBroker struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMockBroker() *mockBroker {
+	return &mockBroker{data: map[string][]byte{}}
+}
+
+func (broker *mockBroker) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	broker.data[key] = data
+	return nil
+}
+
+func (broker *mockBroker) get(key string) []byte {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return broker.data[key]
+}
+
+func (broker *mockBroker) NewTxn() keyval.BytesTxn {
+	return nil
+}
+
+func (broker *mockBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	return nil, false, 0, nil
+}
+
+func (broker *mockBroker) ListValues(key string) (keyval.BytesKeyValIterator, error) {
+	return nil, nil
+}
+
+func (broker *mockBroker) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	return nil, nil
+}
+
+func (broker *mockBroker) Delete(key string) (bool, error) {
+	return false, nil
+}
+
+func TestKVPublishing(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := newMockBroker()
+	plugin := NewPlugin(logrus.StandardLogger())
+	plugin.publishInterval = time.Millisecond
+	plugin.EnableKVPublishing(broker, "vpp1")
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	plugin.Register("pluginA", nil)
+	plugin.ReportStateChange("pluginA", OK, nil)
+
+	gomega.Eventually(func() []byte {
+		return broker.get("/vpp1/check/status/v1/agent")
+	}).ShouldNot(gomega.BeNil())
+
+	agent := publishedAgentStatus{}
+	err := json.Unmarshal(broker.get("/vpp1/check/status/v1/agent"), &agent)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(agent.State).To(gomega.Equal(OK))
+
+	gomega.Eventually(func() []byte {
+		return broker.get("/vpp1/check/status/v1/plugin/pluginA")
+	}).ShouldNot(gomega.BeNil())
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsRegistry is the part of the metrics plugin API used to register
+// the health collector.
+type MetricsRegistry interface {
+	// RegisterCollector registers a custom collector on the shared
+	// registry.
+	RegisterCollector(collector prometheus.Collector) error
+}
+
+// Descriptors of the exported health metrics.
+var (
+	agentStateDesc = prometheus.NewDesc("agent_state",
+		"Aggregated state of the agent: 0 error, 1 init, 2 ok.",
+		nil, nil)
+	agentTransitionsDesc = prometheus.NewDesc("agent_state_transitions_total",
+		"Number of transitions of the aggregated agent state.",
+		nil, nil)
+	pluginStateDesc = prometheus.NewDesc("agent_plugin_state",
+		"State of one plugin of the agent: 0 error, 1 init, 2 ok.",
+		[]string{"plugin"}, nil)
+	pluginTransitionsDesc = prometheus.NewDesc("agent_plugin_state_transitions_total",
+		"Number of state transitions of one plugin of the agent.",
+		[]string{"plugin"}, nil)
+)
+
+// RegisterMetrics exports the health states as Prometheus gauges and
+// the state transitions as counters on the metrics registry.
+func (plugin *Plugin) RegisterMetrics(registry MetricsRegistry) error {
+	return registry.RegisterCollector(&stateCollector{plugin: plugin})
+}
+
+// stateCollector reads the current health states on every scrape.
+type stateCollector struct {
+	plugin *Plugin
+}
+
+// Describe implements the prometheus.Collector interface.
+func (collector *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- agentStateDesc
+	ch <- agentTransitionsDesc
+	ch <- pluginStateDesc
+	ch <- pluginTransitionsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (collector *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	plugin := collector.plugin
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(agentStateDesc, prometheus.GaugeValue,
+		stateValue(plugin.agent.State))
+	ch <- prometheus.MustNewConstMetric(agentTransitionsDesc, prometheus.CounterValue,
+		plugin.agentTransitions)
+	for name, status := range plugin.plugins {
+		ch <- prometheus.MustNewConstMetric(pluginStateDesc, prometheus.GaugeValue,
+			stateValue(status.State), name)
+		ch <- prometheus.MustNewConstMetric(pluginTransitionsDesc, prometheus.CounterValue,
+			plugin.transitions[name], name)
+	}
+}
+
+// stateValue maps a health state to the numeric gauge value.
+func stateValue(state PluginState) float64 {
+	switch state {
+	case OK:
+		return 2
+	case Init:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collectorRegistry adapts a prometheus registry to the MetricsRegistry
+// interface of the metrics plugin.
+type collectorRegistry struct {
+	registry *prometheus.Registry
+}
+
+func (r collectorRegistry) RegisterCollector(collector prometheus.Collector) error {
+	return r.registry.Register(collector)
+}
+
+func TestHealthMetrics(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	registry := prometheus.NewRegistry()
+	gomega.Expect(plugin.RegisterMetrics(collectorRegistry{registry})).To(gomega.BeNil())
+
+	plugin.Register("pluginA", nil)
+	plugin.ReportStateChange("pluginA", OK, nil)
+
+	families, err := registry.Gather()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	byName := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	gomega.Expect(byName["agent_state"].GetMetric()[0].GetGauge().GetValue()).
+		To(gomega.Equal(float64(2)))
+	pluginState := byName["agent_plugin_state"].GetMetric()[0]
+	gomega.Expect(pluginState.GetLabel()[0].GetValue()).To(gomega.Equal("pluginA"))
+	gomega.Expect(pluginState.GetGauge().GetValue()).To(gomega.Equal(float64(2)))
+	gomega.Expect(byName["agent_plugin_state_transitions_total"].GetMetric()[0].
+		GetCounter().GetValue()).To(gomega.Equal(float64(1)))
+}
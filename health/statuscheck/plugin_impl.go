@@ -0,0 +1,231 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// defaultProbeInterval is the period of calling the registered probe
+// functions.
+const defaultProbeInterval = 10 * time.Second
+
+// Plugin aggregates the health state of all plugins of the agent.
+// It implements the core.Plugin interface, therefore it can be loaded
+// with other plugins.
+type Plugin struct {
+	log           logging.Logger
+	probeInterval time.Duration
+
+	broker          keyval.BytesBroker
+	statusKeyPrefix string
+	publishInterval time.Duration
+
+	mu      sync.Mutex
+	plugins map[string]*PluginStatus
+	probes  map[string]ProbeFunc
+	agent   AgentStatus
+
+	transitions      map[string]float64
+	agentTransitions float64
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the plugin.
+func NewPlugin(log logging.Logger) *Plugin {
+	return &Plugin{
+		log:             log,
+		probeInterval:   defaultProbeInterval,
+		publishInterval: defaultPublishInterval,
+		plugins:         map[string]*PluginStatus{},
+		probes:          map[string]ProbeFunc{},
+		agent:           AgentStatus{State: Init},
+		transitions:     map[string]float64{},
+		closeChan:       make(chan struct{}),
+	}
+}
+
+// Init starts the periodic probing of the registered plugins.
+func (plugin *Plugin) Init() error {
+	plugin.wg.Add(1)
+	go plugin.periodicProbing()
+	if plugin.broker != nil {
+		plugin.wg.Add(1)
+		go plugin.periodicPublishing()
+	}
+	return nil
+}
+
+// Close stops the periodic probing.
+func (plugin *Plugin) Close() error {
+	close(plugin.closeChan)
+	plugin.wg.Wait()
+	return nil
+}
+
+// Register starts the health monitoring of the named plugin in the Init
+// state. A non-nil probe is called periodically to refresh the state.
+func (plugin *Plugin) Register(pluginName string, probe ProbeFunc) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	now := time.Now()
+	plugin.plugins[pluginName] = &PluginStatus{
+		State:      Init,
+		LastChange: now,
+		LastUpdate: now,
+	}
+	if probe != nil {
+		plugin.probes[pluginName] = probe
+	}
+	plugin.log.Debugf("Health monitoring of plugin '%s' started", pluginName)
+	plugin.refreshAgentStatus()
+}
+
+// ReportStateChange pushes a new state of the named plugin.
+func (plugin *Plugin) ReportStateChange(pluginName string, state PluginState, err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	plugin.updatePluginStatus(pluginName, state, err)
+}
+
+// GetAgentStatus returns the aggregated health of the agent.
+func (plugin *Plugin) GetAgentStatus() AgentStatus {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	return plugin.agent
+}
+
+// GetAllPluginStatus returns the health of every monitored plugin.
+func (plugin *Plugin) GetAllPluginStatus() map[string]PluginStatus {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	status := make(map[string]PluginStatus, len(plugin.plugins))
+	for name, pluginStatus := range plugin.plugins {
+		status[name] = *pluginStatus
+	}
+	return status
+}
+
+// Status returns nil when all plugins of the agent are OK. It implements
+// the status provider interface of the probe plugin.
+func (plugin *Plugin) Status() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.agent.State == OK {
+		return nil
+	}
+	for name, pluginStatus := range plugin.plugins {
+		if pluginStatus.State != OK {
+			return fmt.Errorf("plugin '%s' is in state '%s': %s",
+				name, pluginStatus.State, pluginStatus.Error)
+		}
+	}
+	return fmt.Errorf("agent is in state '%s'", plugin.agent.State)
+}
+
+// updatePluginStatus stores a new state of the named plugin and refreshes
+// the aggregated state. The caller must hold the mutex.
+func (plugin *Plugin) updatePluginStatus(pluginName string, state PluginState, err error) {
+	status, registered := plugin.plugins[pluginName]
+	if !registered {
+		status = &PluginStatus{}
+		plugin.plugins[pluginName] = status
+	}
+
+	if err != nil {
+		state = Error
+	}
+	now := time.Now()
+	if status.State != state {
+		plugin.log.Infof("Plugin '%s' changed state from '%s' to '%s'",
+			pluginName, status.State, state)
+		status.State = state
+		status.LastChange = now
+		plugin.transitions[pluginName]++
+	}
+	status.LastUpdate = now
+	status.Error = ""
+	if err != nil {
+		status.Error = err.Error()
+	}
+	plugin.refreshAgentStatus()
+}
+
+// refreshAgentStatus recomputes the aggregated state of the agent.
+// The caller must hold the mutex.
+func (plugin *Plugin) refreshAgentStatus() {
+	state := OK
+	for _, pluginStatus := range plugin.plugins {
+		if pluginStatus.State == Error {
+			state = Error
+			break
+		}
+		if pluginStatus.State == Init {
+			state = Init
+		}
+	}
+
+	now := time.Now()
+	if plugin.agent.State != state {
+		plugin.log.Infof("Agent changed state from '%s' to '%s'", plugin.agent.State, state)
+		plugin.agent.State = state
+		plugin.agent.LastChange = now
+		plugin.agentTransitions++
+	}
+	plugin.agent.LastUpdate = now
+}
+
+// periodicProbing refreshes the state of the plugins with a registered
+// probe function until the plugin is closed.
+func (plugin *Plugin) periodicProbing() {
+	defer plugin.wg.Done()
+
+	for {
+		select {
+		case <-time.After(plugin.probeInterval):
+			plugin.runProbes()
+		case <-plugin.closeChan:
+			return
+		}
+	}
+}
+
+// runProbes calls all registered probe functions and stores the states.
+func (plugin *Plugin) runProbes() {
+	plugin.mu.Lock()
+	probes := make(map[string]ProbeFunc, len(plugin.probes))
+	for name, probe := range plugin.probes {
+		probes[name] = probe
+	}
+	plugin.mu.Unlock()
+
+	for name, probe := range probes {
+		state, err := probe()
+
+		plugin.mu.Lock()
+		plugin.updatePluginStatus(name, state, err)
+		plugin.mu.Unlock()
+	}
+}
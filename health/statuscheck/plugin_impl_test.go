@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestReportStateChange(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	plugin.Register("pluginA", nil)
+	plugin.Register("pluginB", nil)
+	gomega.Expect(plugin.GetAgentStatus().State).To(gomega.Equal(Init))
+	gomega.Expect(plugin.Status()).NotTo(gomega.BeNil())
+
+	plugin.ReportStateChange("pluginA", OK, nil)
+	gomega.Expect(plugin.GetAgentStatus().State).To(gomega.Equal(Init))
+
+	plugin.ReportStateChange("pluginB", OK, nil)
+	gomega.Expect(plugin.GetAgentStatus().State).To(gomega.Equal(OK))
+	gomega.Expect(plugin.Status()).To(gomega.BeNil())
+
+	// an error of a single plugin degrades the whole agent
+	plugin.ReportStateChange("pluginB", OK, errors.New("connection lost"))
+	gomega.Expect(plugin.GetAgentStatus().State).To(gomega.Equal(Error))
+	status := plugin.GetAllPluginStatus()["pluginB"]
+	gomega.Expect(status.State).To(gomega.Equal(Error))
+	gomega.Expect(status.Error).To(gomega.Equal("connection lost"))
+}
+
+func TestPeriodicProbe(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	plugin.probeInterval = time.Millisecond
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	plugin.Register("probed", func() (PluginState, error) {
+		return OK, nil
+	})
+
+	gomega.Eventually(func() PluginState {
+		return plugin.GetAgentStatus().State
+	}).Should(gomega.Equal(OK))
+}
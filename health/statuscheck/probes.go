@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"net"
+	"time"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"google.golang.org/grpc"
+)
+
+// defaultProbeTimeout bounds a single connectivity check of an external
+// dependency.
+const defaultProbeTimeout = 5 * time.Second
+
+// KVProbe verifies the connectivity to the KV store by reading the given
+// key. A missing key is not an error, only a failed request is. Register
+// it under the name of the dependency to degrade the overall readiness
+// when the store is down.
+func KVProbe(broker keyval.BytesBroker, key string) ProbeFunc {
+	return func() (PluginState, error) {
+		if _, _, _, err := broker.GetValue(key); err != nil {
+			return Error, err
+		}
+		return OK, nil
+	}
+}
+
+// TCPProbe verifies that a TCP connection to the given address (e.g.
+// a message broker endpoint) can be established within the timeout.
+// Zero timeout uses the default.
+func TCPProbe(addr string, timeout time.Duration) ProbeFunc {
+	if timeout == 0 {
+		timeout = defaultProbeTimeout
+	}
+	return func() (PluginState, error) {
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			return Error, err
+		}
+		conn.Close()
+		return OK, nil
+	}
+}
+
+// GRPCProbe verifies that a gRPC connection to the given downstream
+// service can be established within the timeout. Zero timeout uses
+// the default.
+func GRPCProbe(addr string, timeout time.Duration) ProbeFunc {
+	if timeout == 0 {
+		timeout = defaultProbeTimeout
+	}
+	return func() (PluginState, error) {
+		conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock(),
+			grpc.WithTimeout(timeout))
+		if err != nil {
+			return Error, err
+		}
+		conn.Close()
+		return OK, nil
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestTCPProbe(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	probe := TCPProbe(listener.Addr().String(), time.Second)
+	state, err := probe()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(state).To(gomega.Equal(OK))
+
+	// the dependency went down
+	listener.Close()
+	state, err = probe()
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(state).To(gomega.Equal(Error))
+}
+
+func TestKVProbe(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	probe := KVProbe(newMockBroker(), "/probe")
+	state, err := probe()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(state).To(gomega.Equal(OK))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the health endpoints on the HTTP server.
+const (
+	HealthPath       = "/health"
+	PluginHealthPath = "/health/plugins/{name}"
+)
+
+// healthResponse is the JSON body of the /health endpoint.
+type healthResponse struct {
+	Agent   AgentStatus             `json:"agent"`
+	Plugins map[string]PluginStatus `json:"plugins"`
+}
+
+// RegisterHTTPHandlers exposes the aggregated and the per-plugin health
+// on the HTTP server.
+func (plugin *Plugin) RegisterHTTPHandlers(http rest.HTTPHandlers) {
+	http.RegisterHTTPHandler(HealthPath, httphandler(plugin.healthHandler), "GET")
+	http.RegisterHTTPHandler(PluginHealthPath, httphandler(plugin.pluginHealthHandler), "GET")
+}
+
+// httphandler adapts a handler function to the http.Handler interface.
+func httphandler(handler func(w http.ResponseWriter, req *http.Request)) http.Handler {
+	return http.HandlerFunc(handler)
+}
+
+// healthHandler returns the aggregated and the per-plugin health as JSON.
+func (plugin *Plugin) healthHandler(w http.ResponseWriter, req *http.Request) {
+	resp := healthResponse{
+		Agent:   plugin.GetAgentStatus(),
+		Plugins: plugin.GetAllPluginStatus(),
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pluginHealthHandler returns the health of one plugin as JSON, or 404
+// when the plugin is not monitored.
+func (plugin *Plugin) pluginHealthHandler(w http.ResponseWriter, req *http.Request) {
+	name := mux.Vars(req)["name"]
+	status, found := plugin.GetAllPluginStatus()[name]
+	if !found {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": "plugin '" + name + "' is not monitored",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// writeJSON writes the value as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"github.com/onsi/gomega"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	httpPlugin := rest.NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(httpPlugin.Init()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+	plugin.RegisterHTTPHandlers(httpPlugin)
+
+	plugin.Register("pluginA", nil)
+	plugin.ReportStateChange("pluginA", OK, nil)
+
+	gomega.Expect(httpPlugin.AfterInit()).To(gomega.BeNil())
+	defer httpPlugin.Close()
+
+	resp, err := http.Get("http://" + httpPlugin.GetAddress() + HealthPath)
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+	health := healthResponse{}
+	gomega.Expect(json.NewDecoder(resp.Body).Decode(&health)).To(gomega.BeNil())
+	gomega.Expect(health.Agent.State).To(gomega.Equal(OK))
+	gomega.Expect(health.Plugins["pluginA"].State).To(gomega.Equal(OK))
+
+	resp, err = http.Get("http://" + httpPlugin.GetAddress() + "/health/plugins/pluginA")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+
+	resp, err = http.Get("http://" + httpPlugin.GetAddress() + "/health/plugins/unknown")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusNotFound))
+}
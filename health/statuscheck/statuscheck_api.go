@@ -0,0 +1,84 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuscheck
+
+import "time"
+
+// PluginState is the health state of one plugin.
+type PluginState string
+
+const (
+	// Init means that the plugin has not reported yet or is still
+	// initializing.
+	Init PluginState = "init"
+	// OK means that the plugin is healthy.
+	OK PluginState = "ok"
+	// Error means that the plugin reported an error.
+	Error PluginState = "error"
+)
+
+// PluginStatus describes the health of one plugin.
+type PluginStatus struct {
+	// State is the current health state of the plugin.
+	State PluginState `json:"state"`
+	// LastChange is the time of the last state transition.
+	LastChange time.Time `json:"last_change"`
+	// LastUpdate is the time of the last report or probe, even without
+	// a state transition.
+	LastUpdate time.Time `json:"last_update"`
+	// Error is the message of the last reported error, empty in OK state.
+	Error string `json:"error,omitempty"`
+}
+
+// AgentStatus describes the aggregated health of the whole agent.
+type AgentStatus struct {
+	// State is Error if any plugin reports an error, Init if any plugin
+	// is still initializing and OK otherwise.
+	State PluginState `json:"state"`
+	// LastChange is the time of the last transition of the aggregated
+	// state.
+	LastChange time.Time `json:"last_change"`
+	// LastUpdate is the time of the last recomputation of the aggregated
+	// state.
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// ProbeFunc is called periodically by the plugin to refresh the state
+// of the registered plugin. A non-nil error puts the plugin into the
+// Error state.
+type ProbeFunc func() (PluginState, error)
+
+// PluginStatusWriter is the API used by the monitored plugins to report
+// their health.
+type PluginStatusWriter interface {
+	// Register starts the health monitoring of the named plugin in the
+	// Init state. A non-nil probe is called periodically to refresh
+	// the state; plugins without a probe push their state changes with
+	// ReportStateChange.
+	Register(pluginName string, probe ProbeFunc)
+	// ReportStateChange pushes a new state of the named plugin; a non-nil
+	// error puts the plugin into the Error state regardless of the state
+	// argument.
+	ReportStateChange(pluginName string, state PluginState, err error)
+}
+
+// AgentStatusReader is the API used by the consumers of the aggregated
+// health state (probes, gRPC health, metrics).
+type AgentStatusReader interface {
+	// GetAgentStatus returns the aggregated health of the agent.
+	GetAgentStatus() AgentStatus
+	// GetAllPluginStatus returns the health of every monitored plugin.
+	GetAllPluginStatus() map[string]PluginStatus
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idxmap
+
+// IndexFunction derives the secondary indexes of one entry. It returns
+// a map of index field name to the values of the entry in that index,
+// e.g. {"ip": ["10.0.0.1", "10.0.0.2"]}.
+type IndexFunction func(metadata interface{}) map[string][]string
+
+// NamedMappingEvent describes a single change of the mapping. It is
+// delivered to the subscribers registered via Watch.
+type NamedMappingEvent struct {
+	// RegistryTitle is the title of the mapping the change comes from.
+	RegistryTitle string
+	// Name of the changed entry.
+	Name string
+	// Metadata of the changed entry (the removed metadata for Del).
+	Metadata interface{}
+	// Del is true when the entry was removed.
+	Del bool
+	// Update is true when an existing entry was overwritten.
+	Update bool
+}
+
+// NamedMapping is the read access to a name→metadata mapping. Plugins
+// use it to cache "name → generated ID + attributes" relations.
+type NamedMapping interface {
+	// GetRegistryTitle returns the title assigned to the mapping.
+	GetRegistryTitle() string
+	// GetValue returns the metadata of the entry with the given name.
+	GetValue(name string) (metadata interface{}, exists bool)
+	// ListAllNames returns the names of all entries.
+	ListAllNames() []string
+	// LookupByIndex returns the names of the entries that have the
+	// given value in the given secondary index.
+	LookupByIndex(field string, value string) []string
+	// Watch subscribes to the changes of the mapping. The callback is
+	// executed synchronously with the change; use ToChan to deliver
+	// the notifications through a channel instead.
+	Watch(subscriber string, callback func(NamedMappingEvent))
+}
+
+// ToChan creates a callback that delivers the notifications to the
+// given channel. Notifications are dropped when the channel is full,
+// so that a slow subscriber cannot block the mapping.
+func ToChan(ch chan NamedMappingEvent) func(NamedMappingEvent) {
+	return func(event NamedMappingEvent) {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// NamedMappingRW extends NamedMapping with the write access.
+type NamedMappingRW interface {
+	NamedMapping
+	// Put registers a new entry or overwrites an existing one;
+	// the secondary indexes are recomputed.
+	Put(name string, metadata interface{})
+	// Update replaces the metadata of an existing entry; it returns
+	// false when the entry does not exist.
+	Update(name string, metadata interface{}) bool
+	// Delete removes the entry and returns its metadata.
+	Delete(name string) (metadata interface{}, existed bool)
+}
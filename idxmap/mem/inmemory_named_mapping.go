@@ -0,0 +1,211 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"sync"
+
+	"github.com/ligato/cn-infra/idxmap"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// NamedMapping is the in-memory implementation of the idxmap API. All
+// operations are guarded by a read-write mutex, so the mapping is safe
+// for concurrent use.
+type NamedMapping struct {
+	log           logging.Logger
+	title         string
+	indexFunction idxmap.IndexFunction
+
+	mu      sync.RWMutex
+	entries map[string]interface{}
+	// field => value => set of entry names
+	indexes     map[string]map[string]map[string]struct{}
+	subscribers map[string]func(idxmap.NamedMappingEvent)
+}
+
+// NewNamedMapping creates an empty mapping with the given title. The
+// index function (optional) derives the secondary indexes of the entries.
+func NewNamedMapping(log logging.Logger, title string, indexFunction idxmap.IndexFunction) *NamedMapping {
+	return &NamedMapping{
+		log:           log,
+		title:         title,
+		indexFunction: indexFunction,
+		entries:       map[string]interface{}{},
+		indexes:       map[string]map[string]map[string]struct{}{},
+		subscribers:   map[string]func(idxmap.NamedMappingEvent){},
+	}
+}
+
+// GetRegistryTitle returns the title assigned to the mapping.
+func (mapping *NamedMapping) GetRegistryTitle() string {
+	return mapping.title
+}
+
+// GetValue returns the metadata of the entry with the given name.
+func (mapping *NamedMapping) GetValue(name string) (interface{}, bool) {
+	mapping.mu.RLock()
+	defer mapping.mu.RUnlock()
+	metadata, exists := mapping.entries[name]
+	return metadata, exists
+}
+
+// ListAllNames returns the names of all entries.
+func (mapping *NamedMapping) ListAllNames() []string {
+	mapping.mu.RLock()
+	defer mapping.mu.RUnlock()
+	names := make([]string, 0, len(mapping.entries))
+	for name := range mapping.entries {
+		names = append(names, name)
+	}
+	return names
+}
+
+// LookupByIndex returns the names of the entries that have the given
+// value in the given secondary index.
+func (mapping *NamedMapping) LookupByIndex(field string, value string) []string {
+	mapping.mu.RLock()
+	defer mapping.mu.RUnlock()
+	var names []string
+	for name := range mapping.indexes[field][value] {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Watch subscribes to the changes of the mapping. A later subscription
+// with the same subscriber name replaces the previous callback.
+func (mapping *NamedMapping) Watch(subscriber string, callback func(idxmap.NamedMappingEvent)) {
+	mapping.mu.Lock()
+	defer mapping.mu.Unlock()
+	mapping.subscribers[subscriber] = callback
+}
+
+// Put registers a new entry or overwrites an existing one; the secondary
+// indexes are recomputed.
+func (mapping *NamedMapping) Put(name string, metadata interface{}) {
+	mapping.mu.Lock()
+	_, existed := mapping.entries[name]
+	if existed {
+		mapping.removeFromIndexes(name)
+	}
+	mapping.entries[name] = metadata
+	mapping.addToIndexes(name, metadata)
+	mapping.mu.Unlock()
+
+	mapping.notify(idxmap.NamedMappingEvent{
+		RegistryTitle: mapping.title,
+		Name:          name,
+		Metadata:      metadata,
+		Update:        existed,
+	})
+}
+
+// Update replaces the metadata of an existing entry; it returns false
+// when the entry does not exist.
+func (mapping *NamedMapping) Update(name string, metadata interface{}) bool {
+	mapping.mu.Lock()
+	if _, exists := mapping.entries[name]; !exists {
+		mapping.mu.Unlock()
+		return false
+	}
+	mapping.removeFromIndexes(name)
+	mapping.entries[name] = metadata
+	mapping.addToIndexes(name, metadata)
+	mapping.mu.Unlock()
+
+	mapping.notify(idxmap.NamedMappingEvent{
+		RegistryTitle: mapping.title,
+		Name:          name,
+		Metadata:      metadata,
+		Update:        true,
+	})
+	return true
+}
+
+// Delete removes the entry and returns its metadata.
+func (mapping *NamedMapping) Delete(name string) (interface{}, bool) {
+	mapping.mu.Lock()
+	metadata, exists := mapping.entries[name]
+	if !exists {
+		mapping.mu.Unlock()
+		return nil, false
+	}
+	mapping.removeFromIndexes(name)
+	delete(mapping.entries, name)
+	mapping.mu.Unlock()
+
+	mapping.notify(idxmap.NamedMappingEvent{
+		RegistryTitle: mapping.title,
+		Name:          name,
+		Metadata:      metadata,
+		Del:           true,
+	})
+	return metadata, true
+}
+
+// notify delivers the event to all subscribers. The mutex must not be
+// held, so that a callback may read the mapping.
+func (mapping *NamedMapping) notify(event idxmap.NamedMappingEvent) {
+	mapping.mu.RLock()
+	callbacks := make([]func(idxmap.NamedMappingEvent), 0, len(mapping.subscribers))
+	for _, callback := range mapping.subscribers {
+		callbacks = append(callbacks, callback)
+	}
+	mapping.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
+
+// addToIndexes inserts the entry into the secondary indexes. The caller
+// must hold the mutex.
+func (mapping *NamedMapping) addToIndexes(name string, metadata interface{}) {
+	if mapping.indexFunction == nil {
+		return
+	}
+	for field, values := range mapping.indexFunction(metadata) {
+		byValue, known := mapping.indexes[field]
+		if !known {
+			byValue = map[string]map[string]struct{}{}
+			mapping.indexes[field] = byValue
+		}
+		for _, value := range values {
+			names, known := byValue[value]
+			if !known {
+				names = map[string]struct{}{}
+				byValue[value] = names
+			}
+			names[name] = struct{}{}
+		}
+	}
+}
+
+// removeFromIndexes removes the entry from the secondary indexes. The
+// caller must hold the mutex.
+func (mapping *NamedMapping) removeFromIndexes(name string) {
+	for field, byValue := range mapping.indexes {
+		for value, names := range byValue {
+			delete(names, name)
+			if len(names) == 0 {
+				delete(byValue, value)
+			}
+		}
+		if len(byValue) == 0 {
+			delete(mapping.indexes, field)
+		}
+	}
+}
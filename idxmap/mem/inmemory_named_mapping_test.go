@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"testing"
+
+	"github.com/ligato/cn-infra/idxmap"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+type ifaceMeta struct {
+	index uint32
+	ip    string
+}
+
+func ifaceIndexes(metadata interface{}) map[string][]string {
+	meta, ok := metadata.(*ifaceMeta)
+	if !ok {
+		return nil
+	}
+	return map[string][]string{"ip": {meta.ip}}
+}
+
+func TestPutLookupDelete(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	mapping := NewNamedMapping(logrus.StandardLogger(), "iface-index", ifaceIndexes)
+	gomega.Expect(mapping.GetRegistryTitle()).To(gomega.Equal("iface-index"))
+
+	mapping.Put("if0", &ifaceMeta{index: 1, ip: "10.0.0.1"})
+	mapping.Put("if1", &ifaceMeta{index: 2, ip: "10.0.0.2"})
+
+	metadata, exists := mapping.GetValue("if0")
+	gomega.Expect(exists).To(gomega.BeTrue())
+	gomega.Expect(metadata.(*ifaceMeta).index).To(gomega.BeEquivalentTo(1))
+
+	gomega.Expect(mapping.ListAllNames()).To(gomega.ConsistOf("if0", "if1"))
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.2")).To(gomega.ConsistOf("if1"))
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.3")).To(gomega.BeEmpty())
+
+	metadata, existed := mapping.Delete("if1")
+	gomega.Expect(existed).To(gomega.BeTrue())
+	gomega.Expect(metadata.(*ifaceMeta).ip).To(gomega.Equal("10.0.0.2"))
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.2")).To(gomega.BeEmpty())
+
+	_, existed = mapping.Delete("if1")
+	gomega.Expect(existed).To(gomega.BeFalse())
+}
+
+func TestUpdateReindexes(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	mapping := NewNamedMapping(logrus.StandardLogger(), "iface-index", ifaceIndexes)
+
+	gomega.Expect(mapping.Update("if0", &ifaceMeta{index: 1, ip: "10.0.0.1"})).To(gomega.BeFalse())
+
+	mapping.Put("if0", &ifaceMeta{index: 1, ip: "10.0.0.1"})
+	gomega.Expect(mapping.Update("if0", &ifaceMeta{index: 1, ip: "10.0.0.9"})).To(gomega.BeTrue())
+
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.1")).To(gomega.BeEmpty())
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.9")).To(gomega.ConsistOf("if0"))
+}
+
+func TestWatch(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	mapping := NewNamedMapping(logrus.StandardLogger(), "iface-index", ifaceIndexes)
+
+	events := make(chan idxmap.NamedMappingEvent, 10)
+	mapping.Watch("subscriber", idxmap.ToChan(events))
+
+	mapping.Put("if0", &ifaceMeta{index: 1, ip: "10.0.0.1"})
+	event := <-events
+	gomega.Expect(event.RegistryTitle).To(gomega.Equal("iface-index"))
+	gomega.Expect(event.Name).To(gomega.Equal("if0"))
+	gomega.Expect(event.Del).To(gomega.BeFalse())
+	gomega.Expect(event.Update).To(gomega.BeFalse())
+
+	mapping.Update("if0", &ifaceMeta{index: 1, ip: "10.0.0.2"})
+	event = <-events
+	gomega.Expect(event.Update).To(gomega.BeTrue())
+	gomega.Expect(event.Metadata.(*ifaceMeta).ip).To(gomega.Equal("10.0.0.2"))
+
+	mapping.Delete("if0")
+	event = <-events
+	gomega.Expect(event.Del).To(gomega.BeTrue())
+	gomega.Expect(event.Metadata.(*ifaceMeta).ip).To(gomega.Equal("10.0.0.2"))
+}
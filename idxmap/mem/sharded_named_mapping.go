@@ -0,0 +1,118 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"hash/fnv"
+
+	"github.com/ligato/cn-infra/idxmap"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// DefaultShardCount is the number of shards used by NewShardedNamedMapping.
+const DefaultShardCount = 32
+
+// ShardedNamedMapping is a lock-striped variant of NamedMapping. The
+// entries are distributed into shards by a hash of the name, so that
+// concurrent writers of different entries do not contend on a single
+// mutex. Use it instead of NamedMapping when thousands of entries are
+// updated in bursts (e.g. during resync).
+type ShardedNamedMapping struct {
+	title  string
+	shards []*NamedMapping
+}
+
+// NewShardedNamedMapping creates an empty sharded mapping with
+// DefaultShardCount shards.
+func NewShardedNamedMapping(log logging.Logger, title string, indexFunction idxmap.IndexFunction) *ShardedNamedMapping {
+	return NewShardedNamedMappingWithShards(log, title, indexFunction, DefaultShardCount)
+}
+
+// NewShardedNamedMappingWithShards creates an empty sharded mapping
+// with the given number of shards.
+func NewShardedNamedMappingWithShards(log logging.Logger, title string,
+	indexFunction idxmap.IndexFunction, shardCount int) *ShardedNamedMapping {
+
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	mapping := &ShardedNamedMapping{
+		title:  title,
+		shards: make([]*NamedMapping, shardCount),
+	}
+	for i := range mapping.shards {
+		mapping.shards[i] = NewNamedMapping(log, title, indexFunction)
+	}
+	return mapping
+}
+
+// GetRegistryTitle returns the title assigned to the mapping.
+func (mapping *ShardedNamedMapping) GetRegistryTitle() string {
+	return mapping.title
+}
+
+// GetValue returns the metadata of the entry with the given name.
+func (mapping *ShardedNamedMapping) GetValue(name string) (interface{}, bool) {
+	return mapping.shard(name).GetValue(name)
+}
+
+// ListAllNames returns the names of all entries.
+func (mapping *ShardedNamedMapping) ListAllNames() []string {
+	var names []string
+	for _, shard := range mapping.shards {
+		names = append(names, shard.ListAllNames()...)
+	}
+	return names
+}
+
+// LookupByIndex returns the names of the entries that have the given
+// value in the given secondary index.
+func (mapping *ShardedNamedMapping) LookupByIndex(field string, value string) []string {
+	var names []string
+	for _, shard := range mapping.shards {
+		names = append(names, shard.LookupByIndex(field, value)...)
+	}
+	return names
+}
+
+// Watch subscribes to the changes of the mapping.
+func (mapping *ShardedNamedMapping) Watch(subscriber string, callback func(idxmap.NamedMappingEvent)) {
+	for _, shard := range mapping.shards {
+		shard.Watch(subscriber, callback)
+	}
+}
+
+// Put registers a new entry or overwrites an existing one.
+func (mapping *ShardedNamedMapping) Put(name string, metadata interface{}) {
+	mapping.shard(name).Put(name, metadata)
+}
+
+// Update replaces the metadata of an existing entry; it returns false
+// when the entry does not exist.
+func (mapping *ShardedNamedMapping) Update(name string, metadata interface{}) bool {
+	return mapping.shard(name).Update(name, metadata)
+}
+
+// Delete removes the entry and returns its metadata.
+func (mapping *ShardedNamedMapping) Delete(name string) (interface{}, bool) {
+	return mapping.shard(name).Delete(name)
+}
+
+// shard selects the shard responsible for the given name.
+func (mapping *ShardedNamedMapping) shard(name string) *NamedMapping {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return mapping.shards[hash.Sum32()%uint32(len(mapping.shards))]
+}
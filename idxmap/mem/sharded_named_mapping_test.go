@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mem
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ligato/cn-infra/idxmap"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestShardedMapping(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	mapping := NewShardedNamedMapping(logrus.StandardLogger(), "iface-index", ifaceIndexes)
+	gomega.Expect(mapping.GetRegistryTitle()).To(gomega.Equal("iface-index"))
+
+	events := make(chan idxmap.NamedMappingEvent, 10)
+	mapping.Watch("subscriber", idxmap.ToChan(events))
+
+	mapping.Put("if0", &ifaceMeta{index: 1, ip: "10.0.0.1"})
+	mapping.Put("if1", &ifaceMeta{index: 2, ip: "10.0.0.2"})
+
+	metadata, exists := mapping.GetValue("if0")
+	gomega.Expect(exists).To(gomega.BeTrue())
+	gomega.Expect(metadata.(*ifaceMeta).index).To(gomega.BeEquivalentTo(1))
+
+	gomega.Expect(mapping.ListAllNames()).To(gomega.ConsistOf("if0", "if1"))
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.2")).To(gomega.ConsistOf("if1"))
+
+	event := <-events
+	gomega.Expect(event.Name).To(gomega.Equal("if0"))
+
+	gomega.Expect(mapping.Update("if0", &ifaceMeta{index: 1, ip: "10.0.0.9"})).To(gomega.BeTrue())
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.9")).To(gomega.ConsistOf("if0"))
+
+	_, existed := mapping.Delete("if1")
+	gomega.Expect(existed).To(gomega.BeTrue())
+	gomega.Expect(mapping.LookupByIndex("ip", "10.0.0.2")).To(gomega.BeEmpty())
+}
+
+func benchmarkPut(b *testing.B, mapping idxmap.NamedMappingRW) {
+	names := make([]string, 1024)
+	for i := range names {
+		names[i] = "entry" + strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			name := names[i%len(names)]
+			mapping.Put(name, &ifaceMeta{index: uint32(i), ip: "10.0.0.1"})
+			i++
+		}
+	})
+}
+
+func benchmarkGetValue(b *testing.B, mapping idxmap.NamedMappingRW) {
+	names := make([]string, 1024)
+	for i := range names {
+		names[i] = "entry" + strconv.Itoa(i)
+		mapping.Put(names[i], &ifaceMeta{index: uint32(i), ip: "10.0.0.1"})
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			mapping.GetValue(names[i%len(names)])
+			i++
+		}
+	})
+}
+
+func BenchmarkPutSingleMutex(b *testing.B) {
+	benchmarkPut(b, NewNamedMapping(logrus.StandardLogger(), "bench", ifaceIndexes))
+}
+
+func BenchmarkPutSharded(b *testing.B) {
+	benchmarkPut(b, NewShardedNamedMapping(logrus.StandardLogger(), "bench", ifaceIndexes))
+}
+
+func BenchmarkGetValueSingleMutex(b *testing.B) {
+	benchmarkGetValue(b, NewNamedMapping(logrus.StandardLogger(), "bench", ifaceIndexes))
+}
+
+func BenchmarkGetValueSharded(b *testing.B) {
+	benchmarkGetValue(b, NewShardedNamedMapping(logrus.StandardLogger(), "bench", ifaceIndexes))
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/idxmap"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// NamedMapping decorates an idxmap.NamedMappingRW with persistence to
+// a key-value store. Writes are applied to the decorated mapping and
+// mirrored under the configured prefix; a failure to persist is logged
+// but does not fail the in-memory operation.
+type NamedMapping struct {
+	idxmap.NamedMappingRW
+
+	log             logging.Logger
+	broker          keyval.BytesBroker
+	prefix          string
+	metadataFactory func() interface{}
+}
+
+// NewNamedMapping decorates the given mapping with persistence to the
+// prefix of the key-value store. The entries found under the prefix are
+// restored into the mapping before the decorator is returned; the
+// metadata factory creates the instances the persisted metadata is
+// unmarshalled into.
+func NewNamedMapping(log logging.Logger, broker keyval.BytesBroker, prefix string,
+	mapping idxmap.NamedMappingRW, metadataFactory func() interface{}) (*NamedMapping, error) {
+
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	persisted := &NamedMapping{
+		NamedMappingRW:  mapping,
+		log:             log,
+		broker:          broker,
+		prefix:          prefix,
+		metadataFactory: metadataFactory,
+	}
+	if err := persisted.restore(); err != nil {
+		return nil, err
+	}
+	return persisted, nil
+}
+
+// Put registers the entry in the decorated mapping and persists it.
+func (persisted *NamedMapping) Put(name string, metadata interface{}) {
+	persisted.NamedMappingRW.Put(name, metadata)
+	persisted.persist(name, metadata)
+}
+
+// Update replaces the metadata of an existing entry and persists the
+// new metadata.
+func (persisted *NamedMapping) Update(name string, metadata interface{}) bool {
+	if !persisted.NamedMappingRW.Update(name, metadata) {
+		return false
+	}
+	persisted.persist(name, metadata)
+	return true
+}
+
+// Delete removes the entry from the decorated mapping and from the
+// key-value store.
+func (persisted *NamedMapping) Delete(name string) (interface{}, bool) {
+	metadata, existed := persisted.NamedMappingRW.Delete(name)
+	if existed {
+		if _, err := persisted.broker.Delete(persisted.prefix + name); err != nil {
+			persisted.log.Error("Failed to remove persisted entry ", name, ": ", err)
+		}
+	}
+	return metadata, existed
+}
+
+// restore loads the entries found under the prefix into the decorated
+// mapping.
+func (persisted *NamedMapping) restore() error {
+	iterator, err := persisted.broker.ListValues(persisted.prefix)
+	if err != nil {
+		return err
+	}
+	for {
+		kv, allReceived := iterator.GetNext()
+		if allReceived {
+			return nil
+		}
+		metadata := persisted.metadataFactory()
+		if err := json.Unmarshal(kv.GetValue(), metadata); err != nil {
+			persisted.log.Error("Failed to restore persisted entry ", kv.GetKey(), ": ", err)
+			continue
+		}
+		name := strings.TrimPrefix(kv.GetKey(), persisted.prefix)
+		persisted.NamedMappingRW.Put(name, metadata)
+	}
+}
+
+// persist mirrors the entry into the key-value store.
+func (persisted *NamedMapping) persist(name string, metadata interface{}) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		persisted.log.Error("Failed to marshal metadata of entry ", name, ": ", err)
+		return
+	}
+	if err := persisted.broker.Put(persisted.prefix+name, data); err != nil {
+		persisted.log.Error("Failed to persist entry ", name, ": ", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/idxmap/mem"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// memBroker is a trivial in-memory stand-in for keyval.BytesBroker.
+type memBroker struct {
+	data map[string][]byte
+}
+
+func newMemBroker() *memBroker {
+	return &memBroker{data: map[string][]byte{}}
+}
+
+func (broker *memBroker) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	broker.data[key] = data
+	return nil
+}
+
+func (broker *memBroker) NewTxn() keyval.BytesTxn {
+	return nil
+}
+
+func (broker *memBroker) GetValue(key string) ([]byte, bool, int64, error) {
+	data, found := broker.data[key]
+	return data, found, 0, nil
+}
+
+func (broker *memBroker) ListValues(key string) (keyval.BytesKeyValIterator, error) {
+	it := &memIterator{}
+	for k, v := range broker.data {
+		if strings.HasPrefix(k, key) {
+			it.pairs = append(it.pairs, memKeyVal{key: k, value: v})
+		}
+	}
+	return it, nil
+}
+
+func (broker *memBroker) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	return nil, nil
+}
+
+func (broker *memBroker) Delete(key string) (bool, error) {
+	_, found := broker.data[key]
+	delete(broker.data, key)
+	return found, nil
+}
+
+type memKeyVal struct {
+	key   string
+	value []byte
+}
+
+func (kv memKeyVal) GetKey() string {
+	return kv.key
+}
+
+func (kv memKeyVal) GetValue() []byte {
+	return kv.value
+}
+
+func (kv memKeyVal) GetRevision() int64 {
+	return 0
+}
+
+type memIterator struct {
+	pairs []memKeyVal
+	index int
+}
+
+func (it *memIterator) GetNext() (keyval.BytesKeyVal, bool) {
+	if it.index >= len(it.pairs) {
+		return nil, true
+	}
+	kv := it.pairs[it.index]
+	it.index++
+	return kv, false
+}
+
+type testMeta struct {
+	Index uint32 `json:"index"`
+}
+
+func TestPersistAndRestore(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := newMemBroker()
+	log := logrus.StandardLogger()
+
+	mapping, err := NewNamedMapping(log, broker, "/vnf-agent/vpp1/idx/iface",
+		mem.NewNamedMapping(log, "iface-index", nil),
+		func() interface{} { return &testMeta{} })
+	gomega.Expect(err).To(gomega.BeNil())
+
+	mapping.Put("if0", &testMeta{Index: 1})
+	mapping.Put("if1", &testMeta{Index: 2})
+	gomega.Expect(broker.data).To(gomega.HaveKey("/vnf-agent/vpp1/idx/iface/if0"))
+
+	gomega.Expect(mapping.Update("if1", &testMeta{Index: 3})).To(gomega.BeTrue())
+	gomega.Expect(mapping.Update("missing", &testMeta{})).To(gomega.BeFalse())
+
+	_, existed := mapping.Delete("if0")
+	gomega.Expect(existed).To(gomega.BeTrue())
+	gomega.Expect(broker.data).NotTo(gomega.HaveKey("/vnf-agent/vpp1/idx/iface/if0"))
+
+	// a fresh mapping backed by the same broker restores the entries
+	restored, err := NewNamedMapping(log, broker, "/vnf-agent/vpp1/idx/iface",
+		mem.NewNamedMapping(log, "iface-index", nil),
+		func() interface{} { return &testMeta{} })
+	gomega.Expect(err).To(gomega.BeNil())
+
+	metadata, exists := restored.GetValue("if1")
+	gomega.Expect(exists).To(gomega.BeTrue())
+	gomega.Expect(metadata.(*testMeta).Index).To(gomega.BeEquivalentTo(3))
+	gomega.Expect(restored.ListAllNames()).To(gomega.ConsistOf("if1"))
+}
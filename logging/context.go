@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import "golang.org/x/net/context"
+
+// CorrelationIDKey is the name of the structured field carrying the
+// correlation ID of the request or transaction being processed.
+const CorrelationIDKey = "corrID"
+
+// correlationIDContextKey is the context key of the correlation ID.
+type correlationIDContextKey struct{}
+
+// WithCorrelationID attaches the correlation ID of a request or datasync
+// transaction to the context, so that the processing of a single change
+// can be traced across the plugins handling it.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, correlationID)
+}
+
+// CorrelationID returns the correlation ID attached to the context, or
+// an empty string if there is none.
+func CorrelationID(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return correlationID
+}
+
+// ForContext returns the logger decorated with the correlation ID attached
+// to the context. Without a correlation ID the logger itself is returned.
+func ForContext(ctx context.Context, log Logger) LogWithLevel {
+	if correlationID := CorrelationID(ctx); correlationID != "" {
+		return log.WithField(CorrelationIDKey, correlationID)
+	}
+	return log
+}
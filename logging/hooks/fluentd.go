@@ -0,0 +1,153 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Defaults of the fluentd hook configuration.
+const (
+	defaultFluentdBufferSize = 1024
+	defaultReconnectDelay    = time.Second
+)
+
+// FluentdConfig holds the settings of the fluentd/logstash forwarding
+// hook.
+type FluentdConfig struct {
+	// Address is the host:port of the fluentd/logstash endpoint.
+	Address string `json:"address"`
+	// Network is "tcp" (the default) or "udp".
+	Network string `json:"network"`
+	// Tag identifies the agent in the shipped entries.
+	Tag string `json:"tag"`
+	// BufferSize is the number of entries buffered while the endpoint
+	// is unreachable; the oldest entries over the limit are dropped.
+	// Zero uses the default.
+	BufferSize int `json:"buffer-size"`
+}
+
+// FluentdHook ships structured log entries to a fluentd or logstash
+// endpoint as JSON lines. Entries are buffered and shipped by a background
+// goroutine, reconnecting with a fixed delay, so that logging never blocks
+// on the network.
+type FluentdHook struct {
+	config  FluentdConfig
+	entries chan []byte
+	done    chan struct{}
+}
+
+// NewFluentdHook creates the hook and starts the background shipping
+// goroutine. The hook is to be added to the loggers with AddHook.
+func NewFluentdHook(config FluentdConfig) *FluentdHook {
+	if config.Network == "" {
+		config.Network = "tcp"
+	}
+	if config.BufferSize == 0 {
+		config.BufferSize = defaultFluentdBufferSize
+	}
+	hook := &FluentdHook{
+		config:  config,
+		entries: make(chan []byte, config.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go hook.forward()
+	return hook
+}
+
+// Levels implements the logrus Hook interface; all entries are shipped.
+func (hook *FluentdHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus Hook interface. It enqueues the entry,
+// dropping the oldest buffered entry when the buffer is full.
+func (hook *FluentdHook) Fire(entry *logrus.Entry) error {
+	payload := map[string]interface{}{
+		"tag":     hook.config.Tag,
+		"ts":      entry.Time.Format(time.RFC3339Nano),
+		"level":   entry.Level.String(),
+		"message": entry.Message,
+	}
+	for key, value := range entry.Data {
+		payload[key] = value
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case hook.entries <- append(data, '\n'):
+			return nil
+		default:
+			select {
+			case <-hook.entries:
+			default:
+			}
+		}
+	}
+}
+
+// Close stops the shipping goroutine.
+func (hook *FluentdHook) Close() error {
+	close(hook.done)
+	return nil
+}
+
+// forward ships the buffered entries to the endpoint, reconnecting
+// with a fixed delay on failures.
+func (hook *FluentdHook) forward() {
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case data := <-hook.entries:
+			for {
+				if conn == nil {
+					var err error
+					conn, err = net.Dial(hook.config.Network, hook.config.Address)
+					if err != nil {
+						conn = nil
+						select {
+						case <-time.After(defaultReconnectDelay):
+							continue
+						case <-hook.done:
+							return
+						}
+					}
+				}
+				if _, err := conn.Write(data); err != nil {
+					conn.Close()
+					conn = nil
+					continue
+				}
+				break
+			}
+		case <-hook.done:
+			return
+		}
+	}
+}
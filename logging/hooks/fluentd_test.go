@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestFluentdHook(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer listener.Close()
+
+	received := make(chan map[string]interface{}, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, err := bufio.NewReader(conn).ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		payload := map[string]interface{}{}
+		if json.Unmarshal(line, &payload) == nil {
+			received <- payload
+		}
+	}()
+
+	hook := NewFluentdHook(FluentdConfig{
+		Address: listener.Addr().String(),
+		Tag:     "agent1",
+	})
+	defer hook.Close()
+
+	err = hook.Fire(&logrus.Entry{
+		Time:    time.Now(),
+		Level:   logrus.InfoLevel,
+		Message: "connection established",
+		Data:    logrus.Fields{"plugin": "etcd"},
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	select {
+	case payload := <-received:
+		gomega.Expect(payload["tag"]).To(gomega.Equal("agent1"))
+		gomega.Expect(payload["message"]).To(gomega.Equal("connection established"))
+		gomega.Expect(payload["plugin"]).To(gomega.Equal("etcd"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("log entry was not shipped in time")
+	}
+}
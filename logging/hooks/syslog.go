@@ -0,0 +1,178 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// defaultSyslogFacility is local0.
+const defaultSyslogFacility = 16
+
+// SyslogConfig holds the settings of the syslog hook.
+type SyslogConfig struct {
+	// Address is the endpoint of the syslog daemon. Empty address uses
+	// the local /dev/log socket.
+	Address string `json:"address"`
+	// Network is "udp" (the default for remote daemons) or "tcp".
+	Network string `json:"network"`
+	// Facility is the syslog facility number (default local0).
+	Facility int `json:"facility"`
+	// AppName identifies the agent in the syslog records; defaults
+	// to the process name.
+	AppName string `json:"app-name"`
+}
+
+// SyslogHook ships log entries to a syslog daemon formatted according
+// to RFC 5424, with the severity mapped from the log level and the
+// structured fields carried in the structured-data element.
+type SyslogHook struct {
+	config   SyslogConfig
+	hostname string
+	procid   string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogHook creates the hook. The hook is to be added to the loggers
+// with AddHook.
+func NewSyslogHook(config SyslogConfig) *SyslogHook {
+	if config.Facility == 0 {
+		config.Facility = defaultSyslogFacility
+	}
+	if config.AppName == "" {
+		config.AppName = os.Args[0]
+		if idx := strings.LastIndex(config.AppName, "/"); idx >= 0 {
+			config.AppName = config.AppName[idx+1:]
+		}
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogHook{
+		config:   config,
+		hostname: hostname,
+		procid:   fmt.Sprintf("%d", os.Getpid()),
+	}
+}
+
+// Levels implements the logrus Hook interface; all entries are shipped.
+func (hook *SyslogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements the logrus Hook interface; it formats the entry
+// according to RFC 5424 and writes it to the daemon.
+func (hook *SyslogHook) Fire(entry *logrus.Entry) error {
+	record := hook.format(entry)
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	if hook.conn == nil {
+		if err := hook.connect(); err != nil {
+			return err
+		}
+	}
+	if _, err := hook.conn.Write([]byte(record)); err != nil {
+		// reconnect once, the daemon may have been restarted
+		hook.conn.Close()
+		if err := hook.connect(); err != nil {
+			return err
+		}
+		_, err = hook.conn.Write([]byte(record))
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection to the daemon.
+func (hook *SyslogHook) Close() error {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if hook.conn != nil {
+		return hook.conn.Close()
+	}
+	return nil
+}
+
+// connect dials the syslog daemon. The caller must hold the mutex.
+func (hook *SyslogHook) connect() error {
+	network, address := hook.config.Network, hook.config.Address
+	if address == "" {
+		network, address = "unixgram", "/dev/log"
+	} else if network == "" {
+		network = "udp"
+	}
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		hook.conn = nil
+		return err
+	}
+	hook.conn = conn
+	return nil
+}
+
+// format renders the entry as an RFC 5424 record.
+func (hook *SyslogHook) format(entry *logrus.Entry) string {
+	pri := hook.config.Facility*8 + severity(entry.Level)
+	timestamp := entry.Time.Format(time.RFC3339Nano)
+
+	structuredData := "-"
+	if len(entry.Data) > 0 {
+		keys := make([]string, 0, len(entry.Data))
+		for key := range entry.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value := fmt.Sprint(entry.Data[key])
+			value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+			pairs = append(pairs, fmt.Sprintf("%s=%q", key, value))
+		}
+		structuredData = "[fields " + strings.Join(pairs, " ") + "]"
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %s - %s %s\n",
+		pri, timestamp, hook.hostname, hook.config.AppName, hook.procid,
+		structuredData, entry.Message)
+}
+
+// severity maps a log level to the syslog severity number.
+func severity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return 2 // critical
+	case logrus.ErrorLevel:
+		return 3 // error
+	case logrus.WarnLevel:
+		return 4 // warning
+	case logrus.InfoLevel:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
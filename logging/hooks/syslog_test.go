@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestSyslogHook(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer server.Close()
+
+	hook := NewSyslogHook(SyslogConfig{
+		Address: server.LocalAddr().String(),
+		AppName: "agent1",
+	})
+	defer hook.Close()
+
+	err = hook.Fire(&logrus.Entry{
+		Time:    time.Now(),
+		Level:   logrus.ErrorLevel,
+		Message: "connection lost",
+		Data:    logrus.Fields{"plugin": "etcd"},
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	buf := make([]byte, 2048)
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := server.ReadFrom(buf)
+	gomega.Expect(err).To(gomega.BeNil())
+	record := string(buf[:n])
+
+	// local0.error => 16*8+3
+	gomega.Expect(strings.HasPrefix(record, "<131>1 ")).To(gomega.BeTrue())
+	gomega.Expect(record).To(gomega.ContainSubstring(" agent1 "))
+	gomega.Expect(record).To(gomega.ContainSubstring(`[fields plugin="etcd"]`))
+	gomega.Expect(record).To(gomega.ContainSubstring("connection lost"))
+}
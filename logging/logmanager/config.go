@@ -0,0 +1,112 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/logging/logrus"
+)
+
+// InitialLogLevelEnvVar is the environment variable overriding the default
+// log level of the initial configuration.
+const InitialLogLevelEnvVar = "INITIAL_LOGLVL"
+
+// Config holds the initial log configuration of the agent.
+type Config struct {
+	// DefaultLevel is the level applied to all loggers without
+	// a per-logger override, including loggers created later.
+	DefaultLevel string `json:"default-level"`
+	// Format selects the output format of all loggers: "text" (the
+	// default human-readable format), "json" or "logfmt".
+	Format string `json:"format"`
+	// Loggers holds the per-logger level overrides.
+	Loggers []LoggerConfig `json:"loggers"`
+}
+
+// LoggerConfig is the level override of one logger.
+type LoggerConfig struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// LoadConfig reads the initial log configuration from the file. An empty
+// file name returns an empty configuration. The INITIAL_LOGLVL environment
+// variable overrides the default level from the file.
+func LoadConfig(fpath string) (*Config, error) {
+	config := &Config{}
+	if fpath != "" {
+		b, err := ioutil.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		err = yaml.Unmarshal(b, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if level := os.Getenv(InitialLogLevelEnvVar); level != "" {
+		config.DefaultLevel = level
+	}
+	return config, nil
+}
+
+// ApplyConfig applies the initial log configuration to the registry.
+// It is intended to be called at agent start, before the plugin Init
+// phase, so that early init logs already honor the configured verbosity.
+func ApplyConfig(config *Config, registry logrus.Registry) error {
+	if config.DefaultLevel != "" {
+		level, err := parseLevel(config.DefaultLevel)
+		if err != nil {
+			return err
+		}
+		registry.SetDefaultLevel(level)
+	}
+	if config.Format != "" {
+		if err := registry.SetOutputFormat(config.Format); err != nil {
+			return err
+		}
+	}
+	for _, logger := range config.Loggers {
+		// make sure the logger exists, so that the level is not lost
+		registry.ForPlugin(logger.Name)
+		if err := registry.SetLevel(logger.Name, logger.Level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLevel converts a level name to the LogLevel value.
+func parseLevel(level string) (logging.LogLevel, error) {
+	levels := map[string]logging.LogLevel{
+		"debug":   logging.DebugLevel,
+		"info":    logging.InfoLevel,
+		"warning": logging.WarnLevel,
+		"warn":    logging.WarnLevel,
+		"error":   logging.ErrorLevel,
+		"fatal":   logging.FatalLevel,
+		"panic":   logging.PanicLevel,
+	}
+	parsed, known := levels[level]
+	if !known {
+		return 0, fmt.Errorf("unknown log level '%s'", level)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmanager
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestApplyConfig(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	defer logrus.LoggerRegistry.ClearRegistry()
+
+	configFile, err := ioutil.TempFile("", "logs-config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString(
+		"default-level: error\nloggers:\n  - name: noisy\n    level: debug\n")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(configFile.Close()).To(gomega.BeNil())
+
+	config, err := LoadConfig(configFile.Name())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(ApplyConfig(config, logrus.LoggerRegistry)).To(gomega.BeNil())
+
+	// the default level applies to existing and future loggers
+	gomega.Expect(logrus.StandardLogger().GetLevel()).To(gomega.Equal(logging.ErrorLevel))
+	created := logrus.LoggerRegistry.ForPlugin("created-later")
+	gomega.Expect(created.GetLevel()).To(gomega.Equal(logging.ErrorLevel))
+
+	// the per-logger override wins over the default
+	level, err := logrus.LoggerRegistry.GetLevel("noisy")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(level).To(gomega.Equal("debug"))
+}
+
+func TestInitialLogLevelEnvVar(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv(InitialLogLevelEnvVar, "debug")
+	defer os.Unsetenv(InitialLogLevelEnvVar)
+
+	config, err := LoadConfig("")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(config.DefaultLevel).To(gomega.Equal("debug"))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the log management endpoints on the HTTP server.
+const (
+	ListLoggersPath = "/log/list"
+	SetLevelPath    = "/log/{logger}/{level}"
+	ClearLevelPath  = "/log/{logger}"
+)
+
+// loggerEntry is one item of the logger list response.
+type loggerEntry struct {
+	Logger string `json:"logger"`
+	Level  string `json:"level"`
+}
+
+// Plugin exposes the log levels of the registered loggers over the shared
+// HTTP server. It implements the core.Plugin interface, therefore it can
+// be loaded with other plugins.
+type Plugin struct {
+	log      logging.Logger
+	registry logrus.Registry
+	http     rest.HTTPHandlers
+
+	mu        sync.Mutex
+	overrides map[string]string
+	defaults  map[string]string
+}
+
+// NewPlugin creates a new instance of the plugin managing the loggers
+// of the given registry.
+func NewPlugin(log logging.Logger, registry logrus.Registry, http rest.HTTPHandlers) *Plugin {
+	return &Plugin{
+		log:       log,
+		registry:  registry,
+		http:      http,
+		overrides: map[string]string{},
+		defaults:  map[string]string{},
+	}
+}
+
+// Init registers the log management endpoints on the HTTP server.
+func (plugin *Plugin) Init() error {
+	plugin.http.RegisterHTTPHandler(ListLoggersPath,
+		http.HandlerFunc(plugin.listLoggersHandler), "GET")
+	plugin.http.RegisterHTTPHandler(SetLevelPath,
+		http.HandlerFunc(plugin.setLevelHandler), "PUT")
+	plugin.http.RegisterHTTPHandler(ClearLevelPath,
+		http.HandlerFunc(plugin.clearLevelHandler), "DELETE")
+	return nil
+}
+
+// Close is a NOOP, the plugin does not hold any resources to be released.
+func (plugin *Plugin) Close() error {
+	return nil
+}
+
+// listLoggersHandler returns the registered loggers with their current
+// levels as JSON.
+func (plugin *Plugin) listLoggersHandler(w http.ResponseWriter, req *http.Request) {
+	loggers := []loggerEntry{}
+	for logger, level := range plugin.registry.ListLoggers() {
+		loggers = append(loggers, loggerEntry{Logger: logger, Level: level})
+	}
+	writeJSON(w, http.StatusOK, loggers)
+}
+
+// setLevelHandler overrides the level of one logger. The override stays
+// in effect until explicitly cleared.
+func (plugin *Plugin) setLevelHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	logger, level := vars["logger"], vars["level"]
+
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	previous, err := plugin.registry.GetLevel(logger)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := plugin.registry.SetLevel(logger, level); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	// remember the level the logger had before the first override,
+	// so that it can be restored when the override is cleared
+	if _, overridden := plugin.overrides[logger]; !overridden {
+		plugin.defaults[logger] = previous
+	}
+	plugin.overrides[logger] = level
+	plugin.log.Infof("Log level of logger '%s' set to '%s'", logger, level)
+	writeJSON(w, http.StatusOK, loggerEntry{Logger: logger, Level: level})
+}
+
+// clearLevelHandler removes the override of one logger, restoring the
+// level it had before the first override.
+func (plugin *Plugin) clearLevelHandler(w http.ResponseWriter, req *http.Request) {
+	logger := mux.Vars(req)["logger"]
+
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	defaultLevel, overridden := plugin.overrides[logger]
+	if !overridden {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": "logger '" + logger + "' has no override",
+		})
+		return
+	}
+	defaultLevel = plugin.defaults[logger]
+	if err := plugin.registry.SetLevel(logger, defaultLevel); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	delete(plugin.overrides, logger)
+	delete(plugin.defaults, logger)
+	plugin.log.Infof("Log level override of logger '%s' cleared", logger)
+	writeJSON(w, http.StatusOK, loggerEntry{Logger: logger, Level: defaultLevel})
+}
+
+// writeJSON writes the value as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(value)
+}
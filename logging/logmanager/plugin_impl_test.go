@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logmanager
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"github.com/onsi/gomega"
+)
+
+func TestLogLevelManagement(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	httpPlugin := rest.NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(httpPlugin.Init()).To(gomega.BeNil())
+
+	logger := logrus.LoggerRegistry.ForPlugin("managed")
+	defer logrus.LoggerRegistry.ClearRegistry()
+
+	plugin := NewPlugin(logrus.StandardLogger(), logrus.LoggerRegistry, httpPlugin)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Expect(httpPlugin.AfterInit()).To(gomega.BeNil())
+	defer httpPlugin.Close()
+
+	do := func(method, path string) int {
+		req, err := http.NewRequest(method, "http://"+httpPlugin.GetAddress()+path, nil)
+		gomega.Expect(err).To(gomega.BeNil())
+		resp, err := http.DefaultClient.Do(req)
+		gomega.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the logger list is served
+	gomega.Expect(do("GET", ListLoggersPath)).To(gomega.Equal(http.StatusOK))
+
+	// the level override is applied
+	gomega.Expect(do("PUT", "/log/managed/debug")).To(gomega.Equal(http.StatusOK))
+	level, err := logrus.LoggerRegistry.GetLevel("managed")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(level).To(gomega.Equal("debug"))
+
+	// clearing the override restores the previous level
+	gomega.Expect(do("DELETE", "/log/managed")).To(gomega.Equal(http.StatusOK))
+	level, err = logrus.LoggerRegistry.GetLevel("managed")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(level).To(gomega.Equal(logger.GetLevel().String()))
+
+	// an unknown logger is refused
+	gomega.Expect(do("PUT", "/log/unknown/debug")).To(gomega.Equal(http.StatusNotFound))
+}
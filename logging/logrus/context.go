@@ -0,0 +1,30 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"github.com/ligato/cn-infra/logging"
+	"golang.org/x/net/context"
+)
+
+// WithContext creates an entry carrying the correlation ID attached to the
+// context (see logging.WithCorrelationID), so that all entries emitted
+// while handling one request or transaction can be traced across the log.
+func (ref *Logger) WithContext(ctx context.Context) logging.LogWithLevel {
+	if correlationID := logging.CorrelationID(ctx); correlationID != "" {
+		return ref.withField(logging.CorrelationIDKey, correlationID)
+	}
+	return ref
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	lg "github.com/Sirupsen/logrus"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestWithContextCorrelationID(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	logger := New()
+	var buffer bytes.Buffer
+	logger.SetOutput(&buffer)
+	logger.SetFormatter(new(lg.JSONFormatter))
+
+	ctx := logging.WithCorrelationID(context.Background(), "req-42")
+	logger.WithContext(ctx).Info("handling change")
+
+	record := map[string]interface{}{}
+	err := json.Unmarshal(buffer.Bytes(), &record)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(record[logging.CorrelationIDKey]).To(gomega.Equal("req-42"))
+
+	// without a correlation ID the field is not included
+	buffer.Reset()
+	logger.WithContext(context.Background()).Info("handling change")
+	record = map[string]interface{}{}
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(record).NotTo(gomega.HaveKey(logging.CorrelationIDKey))
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	lg "github.com/Sirupsen/logrus"
+)
+
+// Names of the selectable log output formats.
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+// StableJSONFormatter renders entries as JSON objects with stable field
+// names (logger, level, ts, caller, msg), so that production logs are
+// machine-parseable regardless of the structured fields of the entry.
+type StableJSONFormatter struct {
+	// LoggerName is included as the "logger" field of every entry.
+	LoggerName string
+}
+
+// Format implements the logrus Formatter interface.
+func (formatter *StableJSONFormatter) Format(entry *lg.Entry) ([]byte, error) {
+	record := map[string]interface{}{
+		"ts":     entry.Time.Format(time.RFC3339Nano),
+		"level":  entry.Level.String(),
+		"logger": formatter.LoggerName,
+		"msg":    entry.Message,
+	}
+	for key, value := range entry.Data {
+		switch key {
+		case locKey:
+			record["caller"] = fmt.Sprint(value)
+		case tagKey:
+			record["tag"] = fmt.Sprint(value)
+		default:
+			record[key] = value
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// LogfmtFormatter renders entries as logfmt lines (key=value pairs).
+type LogfmtFormatter struct {
+	// LoggerName is included as the "logger" field of every entry.
+	LoggerName string
+}
+
+// Format implements the logrus Formatter interface.
+func (formatter *LogfmtFormatter) Format(entry *lg.Entry) ([]byte, error) {
+	pairs := []string{
+		"ts=" + logfmtValue(entry.Time.Format(time.RFC3339Nano)),
+		"level=" + logfmtValue(entry.Level.String()),
+		"logger=" + logfmtValue(formatter.LoggerName),
+		"msg=" + logfmtValue(entry.Message),
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key
+		if key == locKey {
+			name = "caller"
+		}
+		pairs = append(pairs, name+"="+logfmtValue(fmt.Sprint(entry.Data[key])))
+	}
+	return []byte(strings.Join(pairs, " ") + "\n"), nil
+}
+
+// logfmtValue quotes the value when it contains characters with a special
+// meaning in logfmt.
+func logfmtValue(value string) string {
+	if strings.ContainsAny(value, " =\"") {
+		return fmt.Sprintf("%q", value)
+	}
+	if value == "" {
+		return `""`
+	}
+	return value
+}
+
+// formatterFor creates the formatter of the given format for one logger.
+func formatterFor(format, loggerName string) (lg.Formatter, error) {
+	switch format {
+	case FormatJSON:
+		return &StableJSONFormatter{LoggerName: loggerName}, nil
+	case FormatLogfmt:
+		return &LogfmtFormatter{LoggerName: loggerName}, nil
+	case FormatText, "":
+		return NewCustomFormatter(), nil
+	default:
+		return nil, fmt.Errorf("unknown log output format '%s'", format)
+	}
+}
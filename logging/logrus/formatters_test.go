@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestJSONOutputFormat(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	LoggerRegistry.ClearRegistry()
+
+	logger := LoggerRegistry.ForPlugin("jsonFormatLogger")
+	var buffer bytes.Buffer
+	logger.SetOutput(&buffer)
+
+	err := LoggerRegistry.SetOutputFormat(FormatJSON)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	logger.WithField("plugin", "etcd").Info("connected")
+
+	record := map[string]interface{}{}
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(record["logger"]).To(gomega.Equal("jsonFormatLogger"))
+	gomega.Expect(record["level"]).To(gomega.Equal("info"))
+	gomega.Expect(record["msg"]).To(gomega.Equal("connected"))
+	gomega.Expect(record["plugin"]).To(gomega.Equal("etcd"))
+	gomega.Expect(record["ts"]).NotTo(gomega.BeNil())
+}
+
+func TestLogfmtOutputFormat(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	LoggerRegistry.ClearRegistry()
+
+	err := LoggerRegistry.SetOutputFormat(FormatLogfmt)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// the format also applies to loggers created later
+	logger := LoggerRegistry.ForPlugin("logfmtFormatLogger")
+	var buffer bytes.Buffer
+	logger.SetOutput(&buffer)
+
+	logger.WithField("plugin", "etcd").Warn("connection lost")
+
+	record := buffer.String()
+	gomega.Expect(record).To(gomega.ContainSubstring("level=warning"))
+	gomega.Expect(record).To(gomega.ContainSubstring("logger=logfmtFormatLogger"))
+	gomega.Expect(record).To(gomega.ContainSubstring(`msg="connection lost"`))
+	gomega.Expect(record).To(gomega.ContainSubstring("plugin=etcd"))
+}
+
+func TestUnknownOutputFormat(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	err := LoggerRegistry.SetOutputFormat("xml")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	lg "github.com/Sirupsen/logrus"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// HookFunc is called for every log entry of the subscribed levels; it can
+// fan selected entries out to custom destinations (alerting, audit stores)
+// without a dedicated hook implementation.
+type HookFunc func(level logging.LogLevel, message string, fields map[string]interface{}) error
+
+// funcHook adapts a HookFunc to the logrus Hook interface.
+type funcHook struct {
+	levels []lg.Level
+	fn     HookFunc
+}
+
+// Levels implements the logrus Hook interface.
+func (hook *funcHook) Levels() []lg.Level {
+	return hook.levels
+}
+
+// Fire implements the logrus Hook interface.
+func (hook *funcHook) Fire(entry *lg.Entry) error {
+	fields := make(map[string]interface{}, len(entry.Data))
+	for key, value := range entry.Data {
+		fields[key] = value
+	}
+	return hook.fn(logLevel(entry.Level), entry.Message, fields)
+}
+
+// logrusLevel converts the level to its logrus counterpart.
+func logrusLevel(level logging.LogLevel) lg.Level {
+	switch level {
+	case logging.PanicLevel:
+		return lg.PanicLevel
+	case logging.FatalLevel:
+		return lg.FatalLevel
+	case logging.ErrorLevel:
+		return lg.ErrorLevel
+	case logging.WarnLevel:
+		return lg.WarnLevel
+	case logging.InfoLevel:
+		return lg.InfoLevel
+	default:
+		return lg.DebugLevel
+	}
+}
+
+// logLevel converts the logrus level back to the logging level.
+func logLevel(level lg.Level) logging.LogLevel {
+	switch level {
+	case lg.PanicLevel:
+		return logging.PanicLevel
+	case lg.FatalLevel:
+		return logging.FatalLevel
+	case lg.ErrorLevel:
+		return logging.ErrorLevel
+	case lg.WarnLevel:
+		return logging.WarnLevel
+	case lg.InfoLevel:
+		return logging.InfoLevel
+	default:
+		return logging.DebugLevel
+	}
+}
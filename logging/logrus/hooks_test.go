@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/onsi/gomega"
+)
+
+func TestRegistryAddHook(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	LoggerRegistry.ClearRegistry()
+
+	type record struct {
+		level   logging.LogLevel
+		message string
+		fields  map[string]interface{}
+	}
+	var records []record
+
+	existing := LoggerRegistry.ForPlugin("hookedLogger")
+	existing.SetOutput(&bytes.Buffer{})
+
+	LoggerRegistry.AddHook([]logging.LogLevel{logging.ErrorLevel},
+		func(level logging.LogLevel, message string, fields map[string]interface{}) error {
+			records = append(records, record{level, message, fields})
+			return nil
+		})
+
+	existing.WithField("plugin", "etcd").Error("connection lost")
+	existing.Info("connected") // not subscribed
+
+	// the hook also applies to loggers created later
+	created := LoggerRegistry.ForPlugin("laterHookedLogger")
+	created.SetOutput(&bytes.Buffer{})
+	created.Error("another failure")
+
+	gomega.Expect(len(records)).To(gomega.Equal(2))
+	gomega.Expect(records[0].level).To(gomega.Equal(logging.ErrorLevel))
+	gomega.Expect(records[0].message).To(gomega.Equal("connection lost"))
+	gomega.Expect(records[0].fields["plugin"]).To(gomega.Equal("etcd"))
+	gomega.Expect(records[1].message).To(gomega.Equal("another failure"))
+}
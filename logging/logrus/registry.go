@@ -34,14 +34,95 @@ type Registry interface {
 	GetLevel(logger string) (string, error)
 	// GetLoggerByName returns a logger instance identified by name from registry
 	GetLoggerByName(name string) (*Logger, bool)
+	// ForPlugin returns the named logger from the registry, creating it
+	// when it does not exist yet
+	ForPlugin(name string) *Logger
+	// SetDefaultLevel sets the level applied to all current loggers and
+	// to the loggers created later, unless overridden per logger
+	SetDefaultLevel(level logging.LogLevel)
+	// SetOutputFormat selects the output format ("text", "json" or
+	// "logfmt") of all current loggers and of the loggers created later
+	SetOutputFormat(format string) error
+	// AddHook subscribes the function to the entries of the given levels
+	// of all current loggers and of the loggers created later
+	AddHook(levels []logging.LogLevel, fn HookFunc)
 	// ClearRegistry removes all loggers except the default one from registry
 	ClearRegistry()
 }
 
 // LogRegistry contains logger map and rwlock guarding access to it
 type LogRegistry struct {
-	mapping map[string]*Logger
-	rwmutex sync.RWMutex
+	mapping      map[string]*Logger
+	rwmutex      sync.RWMutex
+	defaultLevel logging.LogLevel
+	hasDefault   bool
+	outputFormat string
+	hooks        []*funcHook
+}
+
+// AddHook subscribes the function to the entries of the given levels of
+// all current loggers and of the loggers created later.
+func (lr *LogRegistry) AddHook(levels []logging.LogLevel, fn HookFunc) {
+	lgLevels := make([]logrus.Level, 0, len(levels))
+	for _, level := range levels {
+		lgLevels = append(lgLevels, logrusLevel(level))
+	}
+	hook := &funcHook{levels: lgLevels, fn: fn}
+
+	lr.rwmutex.Lock()
+	lr.hooks = append(lr.hooks, hook)
+	loggers := make([]*Logger, 0, len(lr.mapping))
+	for _, logger := range lr.mapping {
+		loggers = append(loggers, logger)
+	}
+	lr.rwmutex.Unlock()
+
+	for _, logger := range loggers {
+		logger.AddHook(hook)
+	}
+}
+
+// SetOutputFormat selects the output format ("text", "json" or "logfmt")
+// of all current loggers and of the loggers created later.
+func (lr *LogRegistry) SetOutputFormat(format string) error {
+	// validate the format name up front
+	if _, err := formatterFor(format, ""); err != nil {
+		return err
+	}
+
+	lr.rwmutex.Lock()
+	lr.outputFormat = format
+	loggers := make(map[string]*Logger, len(lr.mapping))
+	for name, logger := range lr.mapping {
+		loggers[name] = logger
+	}
+	lr.rwmutex.Unlock()
+
+	for name, logger := range loggers {
+		formatter, err := formatterFor(format, name)
+		if err != nil {
+			return err
+		}
+		logger.SetFormatter(formatter)
+	}
+	return nil
+}
+
+// SetDefaultLevel sets the level applied to all current loggers and to
+// the loggers created later, unless overridden per logger.
+func (lr *LogRegistry) SetDefaultLevel(level logging.LogLevel) {
+	lr.rwmutex.Lock()
+	lr.defaultLevel = level
+	lr.hasDefault = true
+	loggers := make([]*Logger, 0, len(lr.mapping))
+	for _, logger := range lr.mapping {
+		loggers = append(loggers, logger)
+	}
+	lr.rwmutex.Unlock()
+
+	for _, logger := range loggers {
+		logger.SetLevel(level)
+	}
 }
 
 // ListLoggers returns a map (loggerName => log level)
@@ -115,10 +196,43 @@ func (lr *LogRegistry) ClearRegistry() {
 	}
 }
 
+// ForPlugin returns the named logger from the registry, creating it when
+// it does not exist yet. It is the recommended way for a plugin to obtain
+// its own logger with an independently settable level.
+func (lr *LogRegistry) ForPlugin(name string) *Logger {
+	lr.rwmutex.RLock()
+	logger, found := lr.mapping[name]
+	lr.rwmutex.RUnlock()
+	if found {
+		return logger
+	}
+
+	logger, err := NewNamed(name)
+	if err != nil {
+		// the name is either invalid or was registered concurrently
+		logger, found = lr.GetLoggerByName(name)
+		if !found {
+			panic(err)
+		}
+	}
+	return logger
+}
+
 // addLogger inserts logger into map
 func (lr *LogRegistry) addLogger(name string, logger *Logger) {
 	lr.rwmutex.Lock()
 	defer lr.rwmutex.Unlock()
 
+	if lr.hasDefault {
+		logger.SetLevel(lr.defaultLevel)
+	}
+	if lr.outputFormat != "" {
+		if formatter, err := formatterFor(lr.outputFormat, name); err == nil {
+			logger.SetFormatter(formatter)
+		}
+	}
+	for _, hook := range lr.hooks {
+		logger.std.Hooks.Add(hook)
+	}
 	lr.mapping[name] = logger
 }
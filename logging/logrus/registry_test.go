@@ -17,6 +17,8 @@ package logrus
 import (
 	"github.com/onsi/gomega"
 	"testing"
+
+	"github.com/ligato/cn-infra/logging"
 )
 
 func TestListLoggers(t *testing.T) {
@@ -121,3 +123,19 @@ func TestClearRegistry(t *testing.T) {
 	_, found = LoggerRegistry.GetLoggerByName(defaultLoggerName)
 	gomega.Expect(found).To(gomega.BeTrue())
 }
+
+func TestForPlugin(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	defer LoggerRegistry.ClearRegistry()
+
+	logger := LoggerRegistry.ForPlugin("pluginlogger")
+	gomega.Expect(logger).NotTo(gomega.BeNil())
+
+	// the same instance is returned on subsequent calls
+	gomega.Expect(LoggerRegistry.ForPlugin("pluginlogger")).To(gomega.BeIdenticalTo(logger))
+
+	// the level of the plugin logger is independent of the others
+	logger.SetLevel(logging.DebugLevel)
+	gomega.Expect(logger.GetLevel()).To(gomega.BeEquivalentTo(logging.DebugLevel))
+	gomega.Expect(StandardLogger().GetLevel()).NotTo(gomega.BeEquivalentTo(logging.DebugLevel))
+}
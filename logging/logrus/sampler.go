@@ -0,0 +1,96 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	lg "github.com/Sirupsen/logrus"
+)
+
+// SetSampling limits the output of the logger to at most maxPerSecond
+// identical messages (same level and message) per second. Further
+// repetitions within the second are suppressed and summarized by a single
+// entry once the message appears again in a later second. This protects
+// the log output against event storms, e.g. watch reconnect loops.
+// A non-positive value disables the sampling.
+func (ref *Logger) SetSampling(maxPerSecond int) {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	if sampled, ok := ref.std.Formatter.(*samplingFormatter); ok {
+		ref.std.Formatter = sampled.next
+	}
+	if maxPerSecond > 0 {
+		ref.std.Formatter = newSamplingFormatter(ref.std.Formatter, maxPerSecond)
+	}
+}
+
+// samplingFormatter wraps another formatter and drops the output of
+// repeated identical messages over the per-second limit.
+type samplingFormatter struct {
+	next  lg.Formatter
+	limit int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+	suppressed  map[string]int
+}
+
+// newSamplingFormatter wraps the formatter with the per-second limit.
+func newSamplingFormatter(next lg.Formatter, limit int) *samplingFormatter {
+	return &samplingFormatter{
+		next:       next,
+		limit:      limit,
+		counts:     map[string]int{},
+		suppressed: map[string]int{},
+	}
+}
+
+// Format implements the logrus Formatter interface. Entries over the
+// per-second limit are formatted to no output; the first entry of
+// a previously suppressed message is preceded by a summary line.
+func (formatter *samplingFormatter) Format(entry *lg.Entry) ([]byte, error) {
+	formatter.mu.Lock()
+	if entry.Time.Sub(formatter.windowStart) >= time.Second {
+		formatter.windowStart = entry.Time
+		formatter.counts = map[string]int{}
+	}
+	key := entry.Level.String() + "|" + entry.Message
+	formatter.counts[key]++
+	if formatter.counts[key] > formatter.limit {
+		formatter.suppressed[key]++
+		formatter.mu.Unlock()
+		return nil, nil
+	}
+	repeats := formatter.suppressed[key]
+	delete(formatter.suppressed, key)
+	formatter.mu.Unlock()
+
+	output, err := formatter.next.Format(entry)
+	if err != nil || repeats == 0 {
+		return output, err
+	}
+	summary := *entry
+	summary.Message = fmt.Sprintf("message %q was repeated %d more times", entry.Message, repeats)
+	summaryOutput, err := formatter.next.Format(&summary)
+	if err != nil {
+		return output, nil
+	}
+	return append(summaryOutput, output...), nil
+}
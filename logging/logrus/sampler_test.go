@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	lg "github.com/Sirupsen/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestSamplingSuppressesRepeatedMessages(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	logger := New()
+	var buffer bytes.Buffer
+	logger.SetOutput(&buffer)
+	logger.SetFormatter(&LogfmtFormatter{LoggerName: logger.GetName()})
+	logger.SetSampling(2)
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("reconnecting")
+	}
+	logger.Info("connected")
+
+	output := buffer.String()
+	gomega.Expect(strings.Count(output, "msg=reconnecting")).To(gomega.Equal(2))
+	gomega.Expect(output).To(gomega.ContainSubstring("msg=connected"))
+
+	// sampling can be switched off again
+	buffer.Reset()
+	logger.SetSampling(0)
+	for i := 0; i < 5; i++ {
+		logger.Warn("reconnecting")
+	}
+	gomega.Expect(strings.Count(buffer.String(), "msg=reconnecting")).To(gomega.Equal(5))
+}
+
+func TestSamplingSummarizesSuppressedMessages(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	formatter := newSamplingFormatter(&LogfmtFormatter{}, 1)
+	start := time.Now()
+	entry := func(offset time.Duration) *lg.Entry {
+		return &lg.Entry{
+			Time:    start.Add(offset),
+			Level:   lg.WarnLevel,
+			Message: "reconnecting",
+		}
+	}
+
+	output, err := formatter.Format(entry(0))
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(string(output)).To(gomega.ContainSubstring("msg=reconnecting"))
+
+	// over the limit within the same second
+	output, err = formatter.Format(entry(100 * time.Millisecond))
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(output).To(gomega.BeEmpty())
+
+	// the next second emits the summary first
+	output, err = formatter.Format(entry(time.Second))
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(string(output)).To(gomega.ContainSubstring("repeated 1 more time"))
+	gomega.Expect(string(output)).To(gomega.ContainSubstring("msg=reconnecting"))
+}
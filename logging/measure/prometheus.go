@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CollectorRegistrar is the subset of the prometheus plugin API the
+// exporter needs. It is satisfied by rpc/prometheus.Plugin as well as
+// by a plain *prometheus.Registry.
+type CollectorRegistrar interface {
+	// RegisterCollector registers the collector on the metrics registry.
+	RegisterCollector(collector prometheus.Collector) error
+}
+
+// registryAdapter lets a plain *prometheus.Registry be used as
+// a CollectorRegistrar.
+type registryAdapter struct {
+	registry *prometheus.Registry
+}
+
+func (adapter registryAdapter) RegisterCollector(collector prometheus.Collector) error {
+	return adapter.registry.Register(collector)
+}
+
+// Registrar adapts a plain *prometheus.Registry to the
+// CollectorRegistrar interface.
+func Registrar(registry *prometheus.Registry) CollectorRegistrar {
+	return registryAdapter{registry: registry}
+}
+
+// ExportToPrometheus registers a histogram vector named
+// <tracer name>_duration_seconds (labelled by entry name) on the given
+// registry and observes every entry recorded by the tracer from this
+// point on. Ad hoc instrumentation added with the tracer thus becomes
+// permanent observability without extra code.
+func ExportToPrometheus(tracer *Tracer, registrar CollectorRegistrar) error {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: metricName(tracer.Name()) + "_duration_seconds",
+		Help: "Durations of the operations recorded by the " + tracer.Name() + " tracer.",
+	}, []string{"name"})
+	if err := registrar.RegisterCollector(histogram); err != nil {
+		return err
+	}
+
+	tracer.mu.Lock()
+	tracer.observer = func(entry Entry) {
+		histogram.WithLabelValues(entry.Name).Observe(entry.Duration.Seconds())
+	}
+	tracer.mu.Unlock()
+	return nil
+}
+
+// metricName converts the tracer name into a valid Prometheus metric
+// name.
+func metricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
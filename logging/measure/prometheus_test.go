@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestExportToPrometheus(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	registry := prometheus.NewRegistry()
+	tracer := NewTracer("etcd-broker", nil)
+	gomega.Expect(ExportToPrometheus(tracer, Registrar(registry))).To(gomega.BeNil())
+
+	tracer.LogDuration("put", time.Now(), 5*time.Millisecond)
+	tracer.LogDuration("put", time.Now(), 10*time.Millisecond)
+	tracer.LogDuration("get", time.Now(), time.Millisecond)
+
+	families, err := registry.Gather()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(families).To(gomega.HaveLen(1))
+	gomega.Expect(families[0].GetName()).To(gomega.Equal("etcd_broker_duration_seconds"))
+
+	metrics := families[0].GetMetric()
+	gomega.Expect(metrics).To(gomega.HaveLen(2))
+	total := uint64(0)
+	for _, metric := range metrics {
+		total += metric.GetHistogram().GetSampleCount()
+	}
+	gomega.Expect(total).To(gomega.BeEquivalentTo(3))
+
+	// a second export of the same tracer fails on the duplicate metric
+	gomega.Expect(ExportToPrometheus(tracer, Registrar(registry))).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// DefaultCapacity is the number of most recent entries a tracer keeps
+// when no capacity is specified.
+const DefaultCapacity = 1000
+
+// Entry is one recorded duration.
+type Entry struct {
+	// Name of the measured operation.
+	Name string `json:"name"`
+	// Started is the start time of the operation.
+	Started time.Time `json:"started"`
+	// Duration of the operation in nanoseconds.
+	Duration time.Duration `json:"duration"`
+}
+
+// Tracer records the durations of named operations in a bounded
+// in-memory log. It is safe for concurrent use. The typical usage is:
+//
+//	defer tracer.LogTime("transaction", time.Now())
+type Tracer struct {
+	name string
+	log  logging.Logger
+
+	mu       sync.Mutex
+	capacity int
+	entries  []Entry
+	next     int
+	wrapped  bool
+	observer func(Entry)
+}
+
+// NewTracer creates a tracer that keeps DefaultCapacity most recent
+// entries.
+func NewTracer(name string, log logging.Logger) *Tracer {
+	return NewTracerWithCapacity(name, log, DefaultCapacity)
+}
+
+// NewTracerWithCapacity creates a tracer that keeps the given number of
+// most recent entries; older entries are overwritten.
+func NewTracerWithCapacity(name string, log logging.Logger, capacity int) *Tracer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Tracer{
+		name:     name,
+		log:      log,
+		capacity: capacity,
+		entries:  make([]Entry, capacity),
+	}
+}
+
+// Name returns the name assigned to the tracer.
+func (tracer *Tracer) Name() string {
+	return tracer.name
+}
+
+// LogTime records the duration elapsed since the given start time under
+// the given entry name.
+func (tracer *Tracer) LogTime(name string, start time.Time) {
+	tracer.record(Entry{Name: name, Started: start, Duration: time.Since(start)})
+}
+
+// LogDuration records an already measured duration under the given
+// entry name.
+func (tracer *Tracer) LogDuration(name string, start time.Time, duration time.Duration) {
+	tracer.record(Entry{Name: name, Started: start, Duration: duration})
+}
+
+// Get returns the recorded entries, oldest first.
+func (tracer *Tracer) Get() []Entry {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	if !tracer.wrapped {
+		entries := make([]Entry, tracer.next)
+		copy(entries, tracer.entries[:tracer.next])
+		return entries
+	}
+	entries := make([]Entry, 0, tracer.capacity)
+	entries = append(entries, tracer.entries[tracer.next:]...)
+	entries = append(entries, tracer.entries[:tracer.next]...)
+	return entries
+}
+
+// Clear removes all recorded entries.
+func (tracer *Tracer) Clear() {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	tracer.next = 0
+	tracer.wrapped = false
+}
+
+// record appends the entry to the bounded log.
+func (tracer *Tracer) record(entry Entry) {
+	tracer.mu.Lock()
+	tracer.entries[tracer.next] = entry
+	tracer.next++
+	if tracer.next == tracer.capacity {
+		tracer.next = 0
+		tracer.wrapped = true
+	}
+	observer := tracer.observer
+	tracer.mu.Unlock()
+
+	if observer != nil {
+		observer(entry)
+	}
+
+	if tracer.log != nil {
+		tracer.log.WithFields(map[string]interface{}{
+			"tracer":   tracer.name,
+			"duration": entry.Duration,
+		}).Debug(entry.Name)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestLogTime(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	tracer := NewTracer("test", logrus.StandardLogger())
+	gomega.Expect(tracer.Name()).To(gomega.Equal("test"))
+
+	start := time.Now().Add(-time.Millisecond)
+	tracer.LogTime("txn", start)
+	tracer.LogDuration("put", start, 2*time.Millisecond)
+
+	entries := tracer.Get()
+	gomega.Expect(entries).To(gomega.HaveLen(2))
+	gomega.Expect(entries[0].Name).To(gomega.Equal("txn"))
+	gomega.Expect(entries[0].Duration).To(gomega.BeNumerically(">=", time.Millisecond))
+	gomega.Expect(entries[1].Duration).To(gomega.Equal(2 * time.Millisecond))
+
+	tracer.Clear()
+	gomega.Expect(tracer.Get()).To(gomega.BeEmpty())
+}
+
+func TestBoundedCapacity(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	tracer := NewTracerWithCapacity("test", nil, 3)
+	for i := 0; i < 5; i++ {
+		tracer.LogDuration("op"+strconv.Itoa(i), time.Now(), time.Duration(i))
+	}
+
+	entries := tracer.Get()
+	gomega.Expect(entries).To(gomega.HaveLen(3))
+	// the oldest entries were overwritten
+	gomega.Expect(entries[0].Name).To(gomega.Equal("op2"))
+	gomega.Expect(entries[2].Name).To(gomega.Equal("op4"))
+}
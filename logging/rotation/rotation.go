@@ -0,0 +1,222 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of the rotation configuration.
+const (
+	defaultMaxSize = 100 // megabytes
+
+	// backupTimeFormat is the timestamp appended to the rotated files;
+	// lexical order of the names matches their age.
+	backupTimeFormat = "20060102T150405.000"
+
+	compressedSuffix = ".gz"
+)
+
+// Config holds the settings of the rotating file writer.
+type Config struct {
+	// FileName is the file to write the log to.
+	FileName string `json:"file-name"`
+	// MaxSize is the size in megabytes at which the file is rotated
+	// (default 100).
+	MaxSize int `json:"max-size"`
+	// MaxAge is the number of days after which rotated files are
+	// deleted; zero keeps them forever.
+	MaxAge int `json:"max-age"`
+	// MaxBackups is the number of rotated files to keep; zero keeps
+	// all of them.
+	MaxBackups int `json:"max-backups"`
+	// Compress enables gzip compression of the rotated files.
+	Compress bool `json:"compress"`
+}
+
+// Writer is an io.WriteCloser writing to the configured file and rotating
+// it once it reaches the maximum size. Old rotated files are pruned by
+// count and age and optionally compressed.
+type Writer struct {
+	config Config
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewWriter opens the log file (creating it when needed) and returns the
+// rotating writer, usable as the output of a logger.
+func NewWriter(config Config) (*Writer, error) {
+	if config.MaxSize == 0 {
+		config.MaxSize = defaultMaxSize
+	}
+	writer := &Writer{config: config}
+	if err := writer.open(); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// Write implements the io.Writer interface; it rotates the file before
+// the write would exceed the maximum size.
+func (writer *Writer) Write(p []byte) (int, error) {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if writer.size+int64(len(p)) > int64(writer.config.MaxSize)*1024*1024 {
+		if err := writer.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := writer.file.Write(p)
+	writer.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (writer *Writer) Close() error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if writer.file == nil {
+		return nil
+	}
+	err := writer.file.Close()
+	writer.file = nil
+	return err
+}
+
+// open opens the log file for appending. The caller must hold the mutex.
+func (writer *Writer) open() error {
+	file, err := os.OpenFile(writer.config.FileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	writer.file = file
+	writer.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup, reopens a fresh
+// file and prunes (and optionally compresses) the old backups. The caller
+// must hold the mutex.
+func (writer *Writer) rotate() error {
+	if err := writer.file.Close(); err != nil {
+		return err
+	}
+	backup := writer.config.FileName + "." + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(writer.config.FileName, backup); err != nil {
+		return err
+	}
+	if err := writer.open(); err != nil {
+		return err
+	}
+	if writer.config.Compress {
+		if err := compressFile(backup); err == nil {
+			backup += compressedSuffix
+		}
+	}
+	writer.prune()
+	return nil
+}
+
+// prune deletes the rotated files over the backup count or age limits.
+func (writer *Writer) prune() {
+	backups := writer.backups()
+	// oldest first; the timestamped names sort chronologically
+	sort.Strings(backups)
+
+	remove := map[string]bool{}
+	if max := writer.config.MaxBackups; max > 0 && len(backups) > max {
+		for _, backup := range backups[:len(backups)-max] {
+			remove[backup] = true
+		}
+	}
+	if writer.config.MaxAge > 0 {
+		cutoff := time.Now().AddDate(0, 0, -writer.config.MaxAge)
+		for _, backup := range backups {
+			if ts, err := backupTime(writer.config.FileName, backup); err == nil && ts.Before(cutoff) {
+				remove[backup] = true
+			}
+		}
+	}
+	for backup := range remove {
+		os.Remove(backup)
+	}
+}
+
+// backups lists the rotated files of the configured log file.
+func (writer *Writer) backups() []string {
+	matches, err := filepath.Glob(writer.config.FileName + ".*")
+	if err != nil {
+		return nil
+	}
+	backups := matches[:0]
+	for _, match := range matches {
+		if _, err := backupTime(writer.config.FileName, match); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	return backups
+}
+
+// backupTime parses the rotation timestamp out of the backup file name.
+func backupTime(fileName, backup string) (time.Time, error) {
+	ts := strings.TrimPrefix(backup, fileName+".")
+	ts = strings.TrimSuffix(ts, compressedSuffix)
+	return time.ParseInLocation(backupTimeFormat, ts, time.Local)
+}
+
+// compressFile gzips the file and removes the original.
+func compressFile(fileName string) error {
+	src, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(fileName + compressedSuffix)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(fileName + compressedSuffix)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(fileName + compressedSuffix)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(fileName + compressedSuffix)
+		return err
+	}
+	return os.Remove(fileName)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotation
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestRotationBySize(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "rotation")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "agent.log")
+	writer, err := NewWriter(Config{FileName: fileName, MaxSize: 1, MaxBackups: 2})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer writer.Close()
+
+	record := append(bytes.Repeat([]byte("x"), 512*1024), '\n')
+	for i := 0; i < 8; i++ {
+		_, err = writer.Write(record)
+		gomega.Expect(err).To(gomega.BeNil())
+	}
+
+	// the current file stays below the limit
+	info, err := os.Stat(fileName)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(info.Size()).To(gomega.BeNumerically("<=", 1024*1024))
+
+	// at most MaxBackups rotated files are kept
+	gomega.Expect(len(writer.backups())).To(gomega.BeNumerically("<=", 2))
+	gomega.Expect(len(writer.backups())).To(gomega.BeNumerically(">", 0))
+}
+
+func TestRotationCompressesBackups(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "rotation")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "agent.log")
+	writer, err := NewWriter(Config{FileName: fileName, MaxSize: 1, Compress: true})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer writer.Close()
+
+	record := append(bytes.Repeat([]byte("x"), 512*1024), '\n')
+	for i := 0; i < 4; i++ {
+		_, err = writer.Write(record)
+		gomega.Expect(err).To(gomega.BeNil())
+	}
+
+	backups := writer.backups()
+	gomega.Expect(len(backups)).To(gomega.BeNumerically(">", 0))
+	for _, backup := range backups {
+		gomega.Expect(backup).To(gomega.HaveSuffix(".gz"))
+	}
+}
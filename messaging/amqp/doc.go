@@ -0,0 +1,19 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package amqp implements a messaging plugin backed by an AMQP 0-9-1 broker
+// (typically RabbitMQ). The underlying connection is abstracted by
+// the Connection interface and an implementation backed by an AMQP client
+// library is injected by the embedding application.
+package amqp
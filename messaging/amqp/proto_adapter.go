@@ -0,0 +1,119 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/messaging"
+)
+
+// NewProtoPublisher creates a transport-agnostic publisher that publishes
+// proto-serialized messages to the configured exchange under the given
+// routing key.
+func (plugin *Plugin) NewProtoPublisher(routingKey string) messaging.ProtoPublisher {
+	return &protoPublisher{publisher: plugin.NewPublisher(routingKey), serializer: &keyval.SerializerProto{}}
+}
+
+// protoPublisher implements messaging.ProtoPublisher. AMQP routing keys
+// already determine the queue, thus the partition key is ignored.
+type protoPublisher struct {
+	publisher  *Publisher
+	serializer keyval.Serializer
+}
+
+// Put serializes the message and publishes it under the routing key.
+func (pub *protoPublisher) Put(key string, msg proto.Message) error {
+	data, err := pub.serializer.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return pub.publisher.Put(data)
+}
+
+// NewProtoWatcher creates an adapter that exposes the messaging.ProtoWatcher
+// API on top of the plugin. A topic is mapped to a queue bound
+// to the configured exchange with the topic as the binding key.
+func (plugin *Plugin) NewProtoWatcher() messaging.ProtoWatcher {
+	return &protoWatcher{plugin: plugin, subscriptions: map[string]*protoSubscription{}}
+}
+
+// protoWatcher implements messaging.ProtoWatcher using per-queue subscribers.
+type protoWatcher struct {
+	plugin        *Plugin
+	mu            sync.Mutex
+	subscriptions map[string]*protoSubscription
+}
+
+// protoSubscription is the subscriber of one queue together with its
+// conversion goroutine.
+type protoSubscription struct {
+	subscriber *Subscriber
+	stopChan   chan struct{}
+}
+
+// Watch starts delivery of the messages of the topics to msgChan.
+func (watcher *protoWatcher) Watch(msgChan chan messaging.ProtoMessage, topics ...string) error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	for _, topic := range topics {
+		if _, found := watcher.subscriptions[topic]; found {
+			return fmt.Errorf("topic %q is already watched", topic)
+		}
+		rawChan := make(chan *Message)
+		subscriber, err := watcher.plugin.NewSubscriber(topic, topic, rawChan)
+		if err != nil {
+			return err
+		}
+		subscription := &protoSubscription{subscriber: subscriber, stopChan: make(chan struct{})}
+		watcher.subscriptions[topic] = subscription
+		go forwardMessages(rawChan, msgChan, subscription.stopChan)
+	}
+	return nil
+}
+
+// StopWatch cancels the delivery of the messages of the topic.
+func (watcher *protoWatcher) StopWatch(topic string) error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	subscription, found := watcher.subscriptions[topic]
+	if !found {
+		return fmt.Errorf("topic %q is not watched", topic)
+	}
+	delete(watcher.subscriptions, topic)
+	close(subscription.stopChan)
+	return subscription.subscriber.Close()
+}
+
+// forwardMessages converts the received messages into ProtoMessages.
+func forwardMessages(rawChan chan *Message, msgChan chan messaging.ProtoMessage, stopChan chan struct{}) {
+	for {
+		select {
+		case <-stopChan:
+			return
+		case msg := <-rawChan:
+			select {
+			case msgChan <- messaging.NewProtoMessage(msg.RoutingKey, "", msg.Body):
+			case <-stopChan:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messaging
+
+import (
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// ProcessFunc handles one received message on behalf of a plugin.
+// A non-nil error triggers a retry of the message.
+type ProcessFunc func(msg ProtoMessage) error
+
+// DeadLetterPublisher publishes the raw payload of the messages that
+// repeatedly failed plugin processing, typically to a dead-letter topic.
+type DeadLetterPublisher interface {
+	// PutDeadLetter publishes the raw payload of the failed message.
+	PutDeadLetter(topic string, key string, data []byte) error
+}
+
+// ConsumerConfig holds the retry and backpressure settings of a consumer.
+type ConsumerConfig struct {
+	// MaxAttempts is the number of processing attempts per message before
+	// it is handed over to the dead-letter publisher.
+	MaxAttempts int
+	// RetryBackoff is the delay before the second attempt, doubled
+	// with each following attempt.
+	RetryBackoff time.Duration
+	// MaxInFlight bounds the number of buffered unprocessed messages.
+	// A slow plugin thus applies backpressure to the transport instead
+	// of buffering unboundedly.
+	MaxInFlight int
+}
+
+const (
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = 1 * time.Second
+	defaultMaxInFlight  = 16
+)
+
+// Consumer processes the messages of watched topics with retry, backpressure
+// and dead-letter handling. Messages are processed sequentially, therefore
+// their ordering is preserved.
+type Consumer struct {
+	log        logging.Logger
+	watcher    ProtoWatcher
+	process    ProcessFunc
+	deadLetter DeadLetterPublisher
+	config     *ConsumerConfig
+	topics     []string
+	msgChan    chan ProtoMessage
+	closeChan  chan struct{}
+	doneChan   chan struct{}
+}
+
+// RunConsumer subscribes for the messages of the topics and starts their
+// processing by the process function. The deadLetter publisher may be nil,
+// in which case repeatedly failing messages are dropped (with an error log).
+// Nil config applies the defaults.
+func RunConsumer(log logging.Logger, watcher ProtoWatcher, process ProcessFunc,
+	deadLetter DeadLetterPublisher, config *ConsumerConfig, topics ...string) (*Consumer, error) {
+
+	if config == nil {
+		config = &ConsumerConfig{}
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = defaultRetryBackoff
+	}
+	if config.MaxInFlight == 0 {
+		config.MaxInFlight = defaultMaxInFlight
+	}
+	consumer := &Consumer{
+		log:        log,
+		watcher:    watcher,
+		process:    process,
+		deadLetter: deadLetter,
+		config:     config,
+		topics:     topics,
+		msgChan:    make(chan ProtoMessage, config.MaxInFlight),
+		closeChan:  make(chan struct{}),
+		doneChan:   make(chan struct{}),
+	}
+	err := watcher.Watch(consumer.msgChan, topics...)
+	if err != nil {
+		return nil, err
+	}
+	go consumer.processMessages()
+	return consumer, nil
+}
+
+// Close cancels the subscriptions and stops the processing.
+func (consumer *Consumer) Close() error {
+	close(consumer.closeChan)
+	var wasErr error
+	for _, topic := range consumer.topics {
+		err := consumer.watcher.StopWatch(topic)
+		if err != nil {
+			wasErr = err
+		}
+	}
+	<-consumer.doneChan
+	return wasErr
+}
+
+// processMessages sequentially processes the received messages.
+func (consumer *Consumer) processMessages() {
+	defer close(consumer.doneChan)
+	for {
+		select {
+		case <-consumer.closeChan:
+			return
+		case msg := <-consumer.msgChan:
+			consumer.processWithRetry(msg)
+		}
+	}
+}
+
+// processWithRetry attempts to process the message, retrying with
+// an exponential backoff, and hands it over to the dead-letter publisher
+// when all attempts have failed.
+func (consumer *Consumer) processWithRetry(msg ProtoMessage) {
+	backoff := consumer.config.RetryBackoff
+	var err error
+	for attempt := 1; attempt <= consumer.config.MaxAttempts; attempt++ {
+		err = consumer.process(msg)
+		if err == nil {
+			return
+		}
+		consumer.log.WithFields(logging.Fields{"topic": msg.GetTopic(), "attempt": attempt}).
+			Warn("Message processing failed: ", err)
+		if attempt == consumer.config.MaxAttempts {
+			break
+		}
+		select {
+		case <-consumer.closeChan:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	consumer.publishDeadLetter(msg, err)
+}
+
+// publishDeadLetter hands the failed message over to the dead-letter publisher.
+func (consumer *Consumer) publishDeadLetter(msg ProtoMessage, procErr error) {
+	if consumer.deadLetter == nil {
+		consumer.log.WithField("topic", msg.GetTopic()).
+			Error("Dropping the message, all processing attempts failed: ", procErr)
+		return
+	}
+	var data []byte
+	if raw, ok := msg.(interface {
+		GetBytes() []byte
+	}); ok {
+		data = raw.GetBytes()
+	}
+	err := consumer.deadLetter.PutDeadLetter(msg.GetTopic(), msg.GetKey(), data)
+	if err != nil {
+		consumer.log.WithField("topic", msg.GetTopic()).
+			Error("Unable to publish the dead letter: ", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messaging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeWatcher delivers the messages pushed by the test to the consumer.
+type fakeWatcher struct {
+	msgChan chan ProtoMessage
+	watched []string
+}
+
+func (w *fakeWatcher) Watch(msgChan chan ProtoMessage, topics ...string) error {
+	w.msgChan = msgChan
+	w.watched = append(w.watched, topics...)
+	return nil
+}
+
+func (w *fakeWatcher) StopWatch(topic string) error {
+	return nil
+}
+
+// fakeDeadLetter records the handed-over payloads.
+type fakeDeadLetter struct {
+	received chan string
+}
+
+func (d *fakeDeadLetter) PutDeadLetter(topic string, key string, data []byte) error {
+	d.received <- string(data)
+	return nil
+}
+
+func TestConsumerRetryAndDeadLetter(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	watcher := &fakeWatcher{}
+	deadLetter := &fakeDeadLetter{received: make(chan string, 1)}
+	attempts := 0
+	process := func(msg ProtoMessage) error {
+		attempts++
+		return errors.New("processing failed")
+	}
+
+	consumer, err := RunConsumer(logrus.StandardLogger(), watcher, process, deadLetter,
+		&ConsumerConfig{MaxAttempts: 2, RetryBackoff: time.Millisecond}, "topic1")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer consumer.Close()
+	gomega.Expect(watcher.watched).To(gomega.ConsistOf("topic1"))
+
+	// the failing message is retried and then handed over to the dead-letter publisher
+	watcher.msgChan <- NewProtoMessage("topic1", "key", []byte("payload"))
+	gomega.Eventually(deadLetter.received).Should(gomega.Receive(gomega.Equal("payload")))
+	gomega.Expect(attempts).To(gomega.Equal(2))
+}
+
+func TestConsumerSuccess(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	watcher := &fakeWatcher{}
+	processed := make(chan string, 1)
+	process := func(msg ProtoMessage) error {
+		processed <- msg.GetTopic()
+		return nil
+	}
+
+	consumer, err := RunConsumer(logrus.StandardLogger(), watcher, process, nil, nil, "topic1")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer consumer.Close()
+
+	watcher.msgChan <- NewProtoMessage("topic1", "key", []byte("payload"))
+	gomega.Eventually(processed).Should(gomega.Receive(gomega.Equal("topic1")))
+}
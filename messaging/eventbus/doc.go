@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventbus implements a lightweight in-process publish/subscribe
+// bus for notifications between the plugins of one agent. Events are
+// published to named topics and delivered to buffered subscriptions with
+// a per-subscription slow-consumer policy, replacing ad hoc shared
+// channels threaded through plugin constructors.
+package eventbus
@@ -0,0 +1,201 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// SlowConsumerPolicy decides what a publisher does when the buffer of a
+// subscription is full.
+type SlowConsumerPolicy int
+
+const (
+	// DropNewest discards the published event for the slow subscription
+	// and counts it as dropped. This is the default.
+	DropNewest SlowConsumerPolicy = iota
+	// DropOldest evicts the oldest buffered event of the subscription to
+	// make room for the published one.
+	DropOldest
+	// Block makes the publisher wait until the subscription has room.
+	// Use with care, a stuck consumer then stalls the publishers of the
+	// topic.
+	Block
+)
+
+// defaultBufferSize is used when a subscription does not specify one.
+const defaultBufferSize = 16
+
+// Event is one notification delivered to the subscriptions of a topic.
+type Event struct {
+	// Topic the event was published to.
+	Topic string
+	// Payload is the value the publisher passed; the subscribers assert
+	// it to the type agreed on for the topic.
+	Payload interface{}
+	// Time the event was published.
+	Time time.Time
+}
+
+// Plugin implements the in-process event bus. It implements the
+// core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log logging.Logger
+
+	mu     sync.RWMutex
+	topics map[string][]*Subscription
+	closed bool
+}
+
+// NewPlugin creates a new instance of the event bus.
+func NewPlugin(log logging.Logger) *Plugin {
+	return &Plugin{log: log, topics: map[string][]*Subscription{}}
+}
+
+// Init is a NOOP, the bus needs no external resources.
+func (plugin *Plugin) Init() error {
+	return nil
+}
+
+// Close cancels all subscriptions and closes their channels.
+func (plugin *Plugin) Close() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.closed {
+		return nil
+	}
+	plugin.closed = true
+	for _, subs := range plugin.topics {
+		for _, sub := range subs {
+			close(sub.events)
+		}
+	}
+	plugin.topics = map[string][]*Subscription{}
+	return nil
+}
+
+// Subscribe registers a subscription for the events of the topic.
+// bufferSize <= 0 selects the default buffer.
+func (plugin *Plugin) Subscribe(topic string, bufferSize int, policy SlowConsumerPolicy) (*Subscription, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.closed {
+		return nil, errors.New("the event bus is closed")
+	}
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	sub := &Subscription{
+		plugin: plugin,
+		topic:  topic,
+		policy: policy,
+		events: make(chan Event, bufferSize),
+	}
+	plugin.topics[topic] = append(plugin.topics[topic], sub)
+	return sub, nil
+}
+
+// Publish delivers the payload to all subscriptions of the topic.
+func (plugin *Plugin) Publish(topic string, payload interface{}) error {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+
+	if plugin.closed {
+		return errors.New("the event bus is closed")
+	}
+	event := Event{Topic: topic, Payload: payload, Time: time.Now()}
+	for _, sub := range plugin.topics[topic] {
+		sub.deliver(event)
+	}
+	return nil
+}
+
+// Subscription receives the events of one topic.
+type Subscription struct {
+	plugin *Plugin
+	topic  string
+	policy SlowConsumerPolicy
+	events chan Event
+
+	droppedMu sync.Mutex
+	dropped   uint64
+}
+
+// Events returns the channel the events of the topic arrive on. The
+// channel is closed when the subscription or the bus is closed.
+func (sub *Subscription) Events() <-chan Event {
+	return sub.events
+}
+
+// Dropped returns how many events were discarded on this subscription
+// because its buffer was full.
+func (sub *Subscription) Dropped() uint64 {
+	sub.droppedMu.Lock()
+	defer sub.droppedMu.Unlock()
+	return sub.dropped
+}
+
+// Close cancels the subscription and closes its channel.
+func (sub *Subscription) Close() error {
+	sub.plugin.mu.Lock()
+	defer sub.plugin.mu.Unlock()
+
+	subs := sub.plugin.topics[sub.topic]
+	for i, candidate := range subs {
+		if candidate == sub {
+			sub.plugin.topics[sub.topic] = append(subs[:i], subs[i+1:]...)
+			close(sub.events)
+			return nil
+		}
+	}
+	return nil
+}
+
+// deliver enqueues the event, applying the slow-consumer policy of the
+// subscription when the buffer is full.
+func (sub *Subscription) deliver(event Event) {
+	if sub.policy == Block {
+		sub.events <- event
+		return
+	}
+	select {
+	case sub.events <- event:
+		return
+	default:
+	}
+	if sub.policy == DropOldest {
+		select {
+		case <-sub.events:
+		default:
+		}
+		select {
+		case sub.events <- event:
+			return
+		default:
+		}
+	}
+	sub.droppedMu.Lock()
+	sub.dropped++
+	dropped := sub.dropped
+	sub.droppedMu.Unlock()
+	sub.plugin.log.WithFields(logging.Fields{"topic": sub.topic, "dropped": dropped}).
+		Warn("Slow consumer, event dropped")
+}
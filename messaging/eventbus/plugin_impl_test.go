@@ -0,0 +1,124 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// ifDown is a sample typed payload of the test topic.
+type ifDown struct {
+	Name string
+}
+
+// TestPublishSubscribe checks the delivery of the events of a topic to
+// its subscriptions and nowhere else.
+func TestPublishSubscribe(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	ifEvents, err := plugin.Subscribe("interfaces", 0, DropNewest)
+	gomega.Expect(err).To(gomega.BeNil())
+	otherEvents, err := plugin.Subscribe("routes", 0, DropNewest)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	gomega.Expect(plugin.Publish("interfaces", &ifDown{Name: "memif0"})).To(gomega.BeNil())
+
+	event := <-ifEvents.Events()
+	gomega.Expect(event.Topic).To(gomega.BeEquivalentTo("interfaces"))
+	gomega.Expect(event.Payload).To(gomega.Equal(&ifDown{Name: "memif0"}))
+	gomega.Expect(event.Time.IsZero()).To(gomega.BeFalse())
+	gomega.Expect(otherEvents.Events()).To(gomega.BeEmpty())
+}
+
+// TestDropNewest checks that a full buffer discards the published event
+// and counts it.
+func TestDropNewest(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	defer plugin.Close()
+
+	sub, err := plugin.Subscribe("topic", 2, DropNewest)
+	gomega.Expect(err).To(gomega.BeNil())
+	for i := 0; i < 5; i++ {
+		gomega.Expect(plugin.Publish("topic", i)).To(gomega.BeNil())
+	}
+
+	gomega.Expect(sub.Dropped()).To(gomega.BeEquivalentTo(3))
+	gomega.Expect((<-sub.Events()).Payload).To(gomega.Equal(0))
+	gomega.Expect((<-sub.Events()).Payload).To(gomega.Equal(1))
+}
+
+// TestDropOldest checks that a full buffer evicts the oldest event in
+// favor of the published one.
+func TestDropOldest(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	defer plugin.Close()
+
+	sub, err := plugin.Subscribe("topic", 2, DropOldest)
+	gomega.Expect(err).To(gomega.BeNil())
+	for i := 0; i < 5; i++ {
+		gomega.Expect(plugin.Publish("topic", i)).To(gomega.BeNil())
+	}
+
+	gomega.Expect(sub.Dropped()).To(gomega.BeEquivalentTo(0))
+	gomega.Expect((<-sub.Events()).Payload).To(gomega.Equal(3))
+	gomega.Expect((<-sub.Events()).Payload).To(gomega.Equal(4))
+}
+
+// TestSubscriptionClose checks that a closed subscription no longer
+// receives events and its channel is closed.
+func TestSubscriptionClose(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	defer plugin.Close()
+
+	sub, err := plugin.Subscribe("topic", 1, DropNewest)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(sub.Close()).To(gomega.BeNil())
+
+	gomega.Expect(plugin.Publish("topic", "late")).To(gomega.BeNil())
+	_, open := <-sub.Events()
+	gomega.Expect(open).To(gomega.BeFalse())
+}
+
+// TestBusClose checks that the closed bus rejects operations and closes
+// the channels of the subscriptions.
+func TestBusClose(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	sub, err := plugin.Subscribe("topic", 1, DropNewest)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+
+	_, open := <-sub.Events()
+	gomega.Expect(open).To(gomega.BeFalse())
+	gomega.Expect(plugin.Publish("topic", "late")).NotTo(gomega.BeNil())
+	_, err = plugin.Subscribe("topic", 1, DropNewest)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/discovery/dnssrv"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/utils/tlsreload"
+)
+
+// Config holds the settings of the Kafka connection shared by the multiplexer.
+type Config struct {
+	// Addrs is the list of broker addresses.
+	Addrs []string `json:"addrs"`
+	// SRV resolves the broker addresses from a DNS SRV name when no
+	// static addresses are configured.
+	SRV *dnssrv.Config `json:"srv"`
+	// GroupID identifies the consumer group of the agent.
+	GroupID string `json:"group-id"`
+	// TLS configures the encryption of the broker connections.
+	TLS TLSConfig `json:"tls"`
+	// SASL configures the authentication against the brokers.
+	SASL SASLConfig `json:"sasl"`
+}
+
+// TLSConfig configures the encryption of the broker connections.
+type TLSConfig struct {
+	Enabled               bool   `json:"enabled"`
+	Certfile              string `json:"cert-file"`
+	Keyfile               string `json:"key-file"`
+	CAfile                string `json:"ca-file"`
+	InsecureSkipTLSVerify bool   `json:"insecure-skip-tls-verify"`
+}
+
+// NewTLSConfig builds the tls.Config of the broker connections. The
+// client certificate is transparently reloaded when the files are
+// rotated. Nil is returned when the encryption is disabled. The
+// connection factories backed by a concrete Kafka client library are
+// supposed to use this helper instead of loading the files themselves.
+func (config *TLSConfig) NewTLSConfig(log logging.Logger) (*tls.Config, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	tlscfg := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipTLSVerify,
+	}
+	if config.CAfile != "" {
+		pem, err := ioutil.ReadFile(config.CAfile)
+		if err != nil {
+			return nil, err
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to add CA certificate from '%s'", config.CAfile)
+		}
+		tlscfg.RootCAs = caPool
+	}
+	if config.Certfile != "" && config.Keyfile != "" {
+		keyPair, err := tlsreload.NewKeyPair(log, config.Certfile, config.Keyfile)
+		if err != nil {
+			return nil, err
+		}
+		tlscfg.GetClientCertificate = keyPair.GetClientCertificate
+	}
+	return tlscfg, nil
+}
+
+// SASLConfig configures the authentication against the brokers.
+type SASLConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// defaultAddr is used when no broker address is configured.
+const defaultAddr = "127.0.0.1:9092"
+
+// ConfigFromFile loads the Kafka configuration from the specified file.
+// If the file is valid and contains valid configuration, the parsed
+// configuration is returned; otherwise, an error is returned.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Addrs) == 0 && config.SRV != nil && config.SRV.Name != "" {
+		config.Addrs, err = dnssrv.Resolve(config.SRV.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(config.Addrs) == 0 {
+		config.Addrs = []string{defaultAddr}
+	}
+	return config, nil
+}
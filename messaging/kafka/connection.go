@@ -0,0 +1,94 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+// ConsumerMessage is a message received from a Kafka topic.
+type ConsumerMessage struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// ProducerMessage is a message successfully published to a Kafka topic.
+type ProducerMessage struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// ProducerError is a message that could not be published, together
+// with the cause.
+type ProducerError struct {
+	*ProducerMessage
+	Err error
+}
+
+// Error makes ProducerError implement the error interface.
+func (perr *ProducerError) Error() string {
+	return perr.Err.Error()
+}
+
+// Connection represents the shared connection to the Kafka cluster.
+// It is the extension point between the multiplexer and a concrete
+// Kafka client library.
+type Connection interface {
+	// SendSync publishes the message to the topic and blocks until
+	// the broker acknowledges it. Messages with the same key are published
+	// to the same partition, therefore their ordering is preserved.
+	SendSync(topic string, key []byte, value []byte) (partition int32, offset int64, err error)
+	// SendAsync publishes the message to the topic without blocking.
+	// The broker acknowledgement is delivered to succChan, a failure
+	// to errChan.
+	SendAsync(topic string, key []byte, value []byte, succChan chan *ProducerMessage, errChan chan *ProducerError)
+	// StartConsuming begins delivery of the messages of the topic to msgChan.
+	StartConsuming(topic string, msgChan chan *ConsumerMessage) error
+	// StopConsuming cancels the delivery of the messages of the topic.
+	StopConsuming(topic string) error
+	// StartPartitionConsuming begins delivery of the messages of the given
+	// partition to msgChan, starting at the given offset.
+	StartPartitionConsuming(topic string, partition int32, startOffset int64, msgChan chan *ConsumerMessage) error
+	// StopPartitionConsuming cancels the delivery of the messages of the partition.
+	StopPartitionConsuming(topic string, partition int32) error
+	// CommitOffset marks the offset of the partition as processed on behalf
+	// of the consumer group.
+	CommitOffset(groupID string, topic string, partition int32, offset int64) error
+	// StartGroupConsuming joins the consumer group and begins delivery
+	// of the messages of the assigned partitions to msgChan. The optional
+	// handler is notified about the partition rebalancing.
+	StartGroupConsuming(groupID string, topics []string, msgChan chan *ConsumerMessage, handler GroupHandler) error
+	// StopGroupConsuming leaves the consumer group.
+	StopGroupConsuming(groupID string) error
+	// Close releases the resources of the connection.
+	Close() error
+}
+
+// EndpointUpdater is optionally implemented by connections that can
+// switch to a new set of broker addresses at runtime. The multiplexer
+// uses it to follow SRV-discovered cluster membership changes.
+type EndpointUpdater interface {
+	// UpdateEndpoints replaces the broker addresses used for the new
+	// connections; the established ones are kept.
+	UpdateEndpoints(addrs []string)
+}
+
+// ConnectionFactory creates the connection to the Kafka cluster described
+// by the configuration. The embedding application is supposed to assign
+// a factory backed by a concrete Kafka client library before the multiplexer
+// is initialized from a config file.
+var ConnectionFactory func(config *Config) (Connection, error)
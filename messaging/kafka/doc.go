@@ -0,0 +1,19 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kafka implements a plugin that multiplexes one shared Kafka connection
+// among multiple per-topic publishers and consumers. The underlying connection
+// is abstracted by the Connection interface; an implementation backed
+// by a Kafka client library is injected by the embedding application.
+package kafka
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PartitionAssignment identifies one partition of a topic assigned
+// to a member of a consumer group.
+type PartitionAssignment struct {
+	Topic     string
+	Partition int32
+}
+
+// GroupHandler receives notifications about the partition rebalancing
+// of a consumer group.
+type GroupHandler interface {
+	// OnPartitionsAssigned is called after partitions have been assigned
+	// to this group member, before their consumption starts.
+	OnPartitionsAssigned(assigned []PartitionAssignment)
+	// OnPartitionsRevoked is called before assigned partitions are taken
+	// away by a rebalance. The plugin is supposed to flush its in-progress
+	// work before returning from the callback.
+	OnPartitionsRevoked(revoked []PartitionAssignment)
+}
+
+// NewGroupConsumer joins the consumer group and subscribes for the messages
+// of the given topics. The partitions of the topics are shared with the other
+// members of the group. Empty groupID defaults to the group configured
+// for the multiplexer. The optional handler is notified about the partition
+// rebalancing.
+func (mux *Mux) NewGroupConsumer(groupID string, topics []string, msgChan chan *ConsumerMessage,
+	handler GroupHandler) (*GroupConsumer, error) {
+
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	if groupID == "" {
+		if mux.config == nil || mux.config.GroupID == "" {
+			return nil, errors.New("no consumer group ID was given nor configured")
+		}
+		groupID = mux.config.GroupID
+	}
+	if _, found := mux.groupConsumers[groupID]; found {
+		return nil, fmt.Errorf("consumer group %q is already joined", groupID)
+	}
+	err := mux.conn.StartGroupConsuming(groupID, topics, msgChan, handler)
+	if err != nil {
+		return nil, err
+	}
+	consumer := &GroupConsumer{mux: mux, groupID: groupID}
+	mux.groupConsumers[groupID] = consumer
+	return consumer, nil
+}
+
+// GroupConsumer is a membership of the multiplexer in one consumer group.
+type GroupConsumer struct {
+	mux     *Mux
+	groupID string
+}
+
+// Close leaves the consumer group.
+func (cons *GroupConsumer) Close() error {
+	cons.mux.mu.Lock()
+	defer cons.mux.mu.Unlock()
+
+	if _, found := cons.mux.groupConsumers[cons.groupID]; !found {
+		return nil
+	}
+	delete(cons.mux.groupConsumers, cons.groupID)
+	return cons.mux.conn.StopGroupConsuming(cons.groupID)
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/ligato/cn-infra/messaging/kafka"
+)
+
+// topicPartition identifies one partition of a topic.
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// group is a simulated consumer group membership.
+type group struct {
+	topics  map[string]struct{}
+	msgChan chan *kafka.ConsumerMessage
+	handler kafka.GroupHandler
+}
+
+// Connection is an in-memory implementation of kafka.Connection. The
+// published messages are kept in per-partition buffers, the partition
+// of a message is derived from its key like with a real broker, and
+// per-topic errors can be injected to exercise failure handling.
+//
+// The messages are delivered synchronously from the Send calls; the
+// consuming tests should use buffered channels or concurrent readers.
+type Connection struct {
+	mu            sync.Mutex
+	numPartitions int32
+	buffers       map[topicPartition][]*kafka.ConsumerMessage
+	errors        map[string]error
+	consumers     map[string][]chan *kafka.ConsumerMessage
+	partConsumers map[topicPartition][]chan *kafka.ConsumerMessage
+	groups        map[string]*group
+	committed     map[string]map[topicPartition]int64
+	closed        bool
+}
+
+// NewConnection creates a mock connection simulating the given number
+// of partitions per topic.
+func NewConnection(numPartitions int32) *Connection {
+	if numPartitions < 1 {
+		numPartitions = 1
+	}
+	return &Connection{
+		numPartitions: numPartitions,
+		buffers:       map[topicPartition][]*kafka.ConsumerMessage{},
+		errors:        map[string]error{},
+		consumers:     map[string][]chan *kafka.ConsumerMessage{},
+		partConsumers: map[topicPartition][]chan *kafka.ConsumerMessage{},
+		groups:        map[string]*group{},
+		committed:     map[string]map[topicPartition]int64{},
+	}
+}
+
+// SetError injects an error returned by the subsequent publishings to
+// the topic; nil removes the injection.
+func (conn *Connection) SetError(topic string, err error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if err == nil {
+		delete(conn.errors, topic)
+	} else {
+		conn.errors[topic] = err
+	}
+}
+
+// Messages returns the messages published to the given partition of the
+// topic.
+func (conn *Connection) Messages(topic string, partition int32) []*kafka.ConsumerMessage {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	buffer := conn.buffers[topicPartition{topic: topic, partition: partition}]
+	messages := make([]*kafka.ConsumerMessage, len(buffer))
+	copy(messages, buffer)
+	return messages
+}
+
+// CommittedOffset returns the offset committed on behalf of the group,
+// or -1 when nothing was committed yet.
+func (conn *Connection) CommittedOffset(groupID string, topic string, partition int32) int64 {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	offsets, found := conn.committed[groupID]
+	if !found {
+		return -1
+	}
+	offset, found := offsets[topicPartition{topic: topic, partition: partition}]
+	if !found {
+		return -1
+	}
+	return offset
+}
+
+// SendSync publishes the message and delivers it to the subscribed
+// consumers before returning.
+func (conn *Connection) SendSync(topic string, key []byte, value []byte) (int32, int64, error) {
+	conn.mu.Lock()
+
+	if err := conn.errors[topic]; err != nil {
+		conn.mu.Unlock()
+		return 0, 0, err
+	}
+	partition := conn.partitionFor(key)
+	tp := topicPartition{topic: topic, partition: partition}
+	message := &kafka.ConsumerMessage{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    int64(len(conn.buffers[tp])),
+		Key:       key,
+		Value:     value,
+	}
+	conn.buffers[tp] = append(conn.buffers[tp], message)
+
+	var receivers []chan *kafka.ConsumerMessage
+	receivers = append(receivers, conn.consumers[topic]...)
+	receivers = append(receivers, conn.partConsumers[tp]...)
+	for _, grp := range conn.groups {
+		if _, subscribed := grp.topics[topic]; subscribed {
+			receivers = append(receivers, grp.msgChan)
+		}
+	}
+	conn.mu.Unlock()
+
+	for _, msgChan := range receivers {
+		msgChan <- message
+	}
+	return partition, message.Offset, nil
+}
+
+// SendAsync publishes the message and reports the result to succChan or
+// errChan.
+func (conn *Connection) SendAsync(topic string, key []byte, value []byte,
+	succChan chan *kafka.ProducerMessage, errChan chan *kafka.ProducerError) {
+
+	partition, offset, err := conn.SendSync(topic, key, value)
+	produced := &kafka.ProducerMessage{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       key,
+		Value:     value,
+	}
+	if err != nil {
+		errChan <- &kafka.ProducerError{ProducerMessage: produced, Err: err}
+		return
+	}
+	succChan <- produced
+}
+
+// StartConsuming begins delivery of the subsequently published messages
+// of the topic to msgChan.
+func (conn *Connection) StartConsuming(topic string, msgChan chan *kafka.ConsumerMessage) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.consumers[topic] = append(conn.consumers[topic], msgChan)
+	return nil
+}
+
+// StopConsuming cancels the delivery of the messages of the topic.
+func (conn *Connection) StopConsuming(topic string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if _, found := conn.consumers[topic]; !found {
+		return fmt.Errorf("topic %q is not consumed", topic)
+	}
+	delete(conn.consumers, topic)
+	return nil
+}
+
+// StartPartitionConsuming replays the stored messages of the partition
+// from the given offset and begins delivery of the subsequent ones.
+func (conn *Connection) StartPartitionConsuming(topic string, partition int32, startOffset int64,
+	msgChan chan *kafka.ConsumerMessage) error {
+
+	conn.mu.Lock()
+	tp := topicPartition{topic: topic, partition: partition}
+	var replay []*kafka.ConsumerMessage
+	for _, message := range conn.buffers[tp] {
+		if message.Offset >= startOffset {
+			replay = append(replay, message)
+		}
+	}
+	conn.partConsumers[tp] = append(conn.partConsumers[tp], msgChan)
+	conn.mu.Unlock()
+
+	for _, message := range replay {
+		msgChan <- message
+	}
+	return nil
+}
+
+// StopPartitionConsuming cancels the delivery of the messages of the
+// partition.
+func (conn *Connection) StopPartitionConsuming(topic string, partition int32) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	tp := topicPartition{topic: topic, partition: partition}
+	if _, found := conn.partConsumers[tp]; !found {
+		return fmt.Errorf("partition %d of topic %q is not consumed", partition, topic)
+	}
+	delete(conn.partConsumers, tp)
+	return nil
+}
+
+// CommitOffset records the offset committed on behalf of the group.
+func (conn *Connection) CommitOffset(groupID string, topic string, partition int32, offset int64) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	offsets, found := conn.committed[groupID]
+	if !found {
+		offsets = map[topicPartition]int64{}
+		conn.committed[groupID] = offsets
+	}
+	offsets[topicPartition{topic: topic, partition: partition}] = offset
+	return nil
+}
+
+// StartGroupConsuming joins the consumer group. The mock assigns all
+// partitions of the topics to the joining member and notifies the
+// handler about the assignment.
+func (conn *Connection) StartGroupConsuming(groupID string, topics []string,
+	msgChan chan *kafka.ConsumerMessage, handler kafka.GroupHandler) error {
+
+	conn.mu.Lock()
+	if _, found := conn.groups[groupID]; found {
+		conn.mu.Unlock()
+		return fmt.Errorf("consumer group %q is already joined", groupID)
+	}
+	grp := &group{topics: map[string]struct{}{}, msgChan: msgChan, handler: handler}
+	for _, topic := range topics {
+		grp.topics[topic] = struct{}{}
+	}
+	conn.groups[groupID] = grp
+	conn.mu.Unlock()
+
+	if handler != nil {
+		handler.OnPartitionsAssigned(conn.assignments(topics))
+	}
+	return nil
+}
+
+// StopGroupConsuming leaves the consumer group, notifying the handler
+// about the revoked partitions.
+func (conn *Connection) StopGroupConsuming(groupID string) error {
+	conn.mu.Lock()
+	grp, found := conn.groups[groupID]
+	if !found {
+		conn.mu.Unlock()
+		return fmt.Errorf("consumer group %q is not joined", groupID)
+	}
+	delete(conn.groups, groupID)
+	conn.mu.Unlock()
+
+	if grp.handler != nil {
+		topics := make([]string, 0, len(grp.topics))
+		for topic := range grp.topics {
+			topics = append(topics, topic)
+		}
+		grp.handler.OnPartitionsRevoked(conn.assignments(topics))
+	}
+	return nil
+}
+
+// Close marks the connection closed.
+func (conn *Connection) Close() error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.closed = true
+	return nil
+}
+
+// IsClosed returns true after Close was called.
+func (conn *Connection) IsClosed() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.closed
+}
+
+// partitionFor derives the partition from the message key like a real
+// broker. The caller must hold the mutex.
+func (conn *Connection) partitionFor(key []byte) int32 {
+	if len(key) == 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write(key)
+	return int32(hash.Sum32() % uint32(conn.numPartitions))
+}
+
+// assignments enumerates all partitions of the topics.
+func (conn *Connection) assignments(topics []string) []kafka.PartitionAssignment {
+	var assigned []kafka.PartitionAssignment
+	for _, topic := range topics {
+		for partition := int32(0); partition < conn.numPartitions; partition++ {
+			assigned = append(assigned, kafka.PartitionAssignment{Topic: topic, Partition: partition})
+		}
+	}
+	return assigned
+}
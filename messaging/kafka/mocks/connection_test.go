@@ -0,0 +1,130 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/messaging/kafka"
+	"github.com/onsi/gomega"
+)
+
+func TestPublishAndConsumeThroughMux(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := NewConnection(3)
+	mux := kafka.NewMuxUsingConnection(logrus.StandardLogger(), conn)
+	gomega.Expect(mux.Init()).To(gomega.BeNil())
+	defer mux.Close()
+
+	msgChan := make(chan *kafka.ConsumerMessage, 10)
+	consumer, err := mux.NewConsumer("status", msgChan)
+	gomega.Expect(err).To(gomega.BeNil())
+	defer consumer.Close()
+
+	publisher := mux.NewSyncPublisher("status")
+	gomega.Expect(publisher.Put([]byte("if0"), []byte("up"))).To(gomega.BeNil())
+
+	message := <-msgChan
+	gomega.Expect(message.Topic).To(gomega.Equal("status"))
+	gomega.Expect(message.Value).To(gomega.Equal([]byte("up")))
+
+	// messages with the same key land in the same partition
+	gomega.Expect(publisher.Put([]byte("if0"), []byte("down"))).To(gomega.BeNil())
+	second := <-msgChan
+	gomega.Expect(second.Partition).To(gomega.Equal(message.Partition))
+	gomega.Expect(second.Offset).To(gomega.Equal(message.Offset + 1))
+
+	stored := conn.Messages("status", message.Partition)
+	gomega.Expect(stored).To(gomega.HaveLen(2))
+}
+
+func TestInjectedError(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := NewConnection(1)
+	conn.SetError("status", errors.New("broker is down"))
+
+	_, _, err := conn.SendSync("status", []byte("key"), []byte("value"))
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	succChan := make(chan *kafka.ProducerMessage, 1)
+	errChan := make(chan *kafka.ProducerError, 1)
+	conn.SendAsync("status", []byte("key"), []byte("value"), succChan, errChan)
+	producerErr := <-errChan
+	gomega.Expect(producerErr.Err.Error()).To(gomega.Equal("broker is down"))
+
+	conn.SetError("status", nil)
+	_, _, err = conn.SendSync("status", []byte("key"), []byte("value"))
+	gomega.Expect(err).To(gomega.BeNil())
+}
+
+func TestPartitionConsumingReplay(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := NewConnection(1)
+	conn.SendSync("events", nil, []byte("first"))
+	conn.SendSync("events", nil, []byte("second"))
+	conn.SendSync("events", nil, []byte("third"))
+
+	msgChan := make(chan *kafka.ConsumerMessage, 10)
+	err := conn.StartPartitionConsuming("events", 0, 1, msgChan)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// the messages from the requested offset were replayed
+	gomega.Expect((<-msgChan).Value).To(gomega.Equal([]byte("second")))
+	gomega.Expect((<-msgChan).Value).To(gomega.Equal([]byte("third")))
+
+	gomega.Expect(conn.StopPartitionConsuming("events", 0)).To(gomega.BeNil())
+}
+
+// recordingHandler records the partition rebalancing notifications.
+type recordingHandler struct {
+	assigned []kafka.PartitionAssignment
+	revoked  []kafka.PartitionAssignment
+}
+
+func (handler *recordingHandler) OnPartitionsAssigned(assigned []kafka.PartitionAssignment) {
+	handler.assigned = assigned
+}
+
+func (handler *recordingHandler) OnPartitionsRevoked(revoked []kafka.PartitionAssignment) {
+	handler.revoked = revoked
+}
+
+func TestGroupConsumingAndOffsets(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := NewConnection(2)
+	handler := &recordingHandler{}
+	msgChan := make(chan *kafka.ConsumerMessage, 10)
+
+	err := conn.StartGroupConsuming("workers", []string{"jobs"}, msgChan, handler)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(handler.assigned).To(gomega.HaveLen(2))
+
+	conn.SendSync("jobs", []byte("job1"), []byte("payload"))
+	message := <-msgChan
+	gomega.Expect(message.Topic).To(gomega.Equal("jobs"))
+
+	gomega.Expect(conn.CommitOffset("workers", "jobs", message.Partition, message.Offset)).To(gomega.BeNil())
+	gomega.Expect(conn.CommittedOffset("workers", "jobs", message.Partition)).To(gomega.Equal(message.Offset))
+	gomega.Expect(conn.CommittedOffset("workers", "jobs", message.Partition+1)).To(gomega.BeEquivalentTo(-1))
+
+	gomega.Expect(conn.StopGroupConsuming("workers")).To(gomega.BeNil())
+	gomega.Expect(handler.revoked).To(gomega.HaveLen(2))
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ligato/cn-infra/discovery/dnssrv"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/utils/safeclose"
+)
+
+// Mux is a plugin that shares one Kafka connection among multiple per-topic
+// publishers and consumers. It implements the core.Plugin interface, therefore
+// it can be loaded with other plugins.
+type Mux struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	mu             sync.Mutex
+	conn           Connection
+	consumers      map[string]*Consumer
+	groupConsumers map[string]*GroupConsumer
+	partConsumers  map[string]*PartitionConsumer
+	closeCh        chan struct{}
+	srvDone        <-chan struct{}
+}
+
+// NewMux creates a new instance of the multiplexer. The configuration
+// of the Kafka connection is loaded from the file during Init. The connection
+// itself is created by the ConnectionFactory.
+func NewMux(log logging.Logger, configFile string) *Mux {
+	return &Mux{log: log, configFile: configFile,
+		consumers: map[string]*Consumer{}, groupConsumers: map[string]*GroupConsumer{},
+		partConsumers: map[string]*PartitionConsumer{}}
+}
+
+// NewMuxUsingConnection creates a new instance of the multiplexer
+// on top of an already established connection.
+func NewMuxUsingConnection(log logging.Logger, conn Connection) *Mux {
+	return &Mux{log: log, conn: conn,
+		consumers: map[string]*Consumer{}, groupConsumers: map[string]*GroupConsumer{},
+		partConsumers: map[string]*PartitionConsumer{}}
+}
+
+// Init establishes the connection to the Kafka cluster unless an existing
+// connection was injected by the constructor.
+func (mux *Mux) Init() error {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	if mux.conn != nil {
+		return nil
+	}
+	if ConnectionFactory == nil {
+		return errors.New("no Kafka connection was injected and no ConnectionFactory is registered")
+	}
+	config, err := ConfigFromFile(mux.configFile)
+	if err != nil {
+		return err
+	}
+	mux.config = config
+	mux.conn, err = ConnectionFactory(config)
+	if err != nil {
+		mux.log.Errorf("Failed to connect to Kafka broker(s) %v, Error: '%s'", config.Addrs, err)
+		return err
+	}
+	mux.log.Debug("Kafka multiplexer connected to ", config.Addrs)
+
+	if updater, ok := mux.conn.(EndpointUpdater); ok &&
+		config.SRV != nil && config.SRV.Name != "" && config.SRV.PollPeriod > 0 {
+		// follow the cluster membership changes published in DNS
+		mux.closeCh = make(chan struct{})
+		mux.srvDone = dnssrv.Poll(mux.log, config.SRV, mux.closeCh, updater.UpdateEndpoints)
+	}
+	return nil
+}
+
+// Close stops all consumers and releases the connection.
+func (mux *Mux) Close() error {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	if mux.closeCh != nil {
+		close(mux.closeCh)
+		mux.closeCh = nil
+		// wait for the SRV poller before tearing down the connection
+		<-mux.srvDone
+	}
+	for topic := range mux.consumers {
+		err := mux.conn.StopConsuming(topic)
+		if err != nil {
+			mux.log.Warn("err stopping consumer of topic ", topic, " ", err)
+		}
+	}
+	mux.consumers = map[string]*Consumer{}
+	for groupID := range mux.groupConsumers {
+		err := mux.conn.StopGroupConsuming(groupID)
+		if err != nil {
+			mux.log.Warn("err stopping consumer group ", groupID, " ", err)
+		}
+	}
+	mux.groupConsumers = map[string]*GroupConsumer{}
+	for _, consumer := range mux.partConsumers {
+		err := mux.conn.StopPartitionConsuming(consumer.topic, consumer.partition)
+		if err != nil {
+			mux.log.Warn("err stopping partition consumer ", partitionName(consumer.topic, consumer.partition), " ", err)
+		}
+	}
+	mux.partConsumers = map[string]*PartitionConsumer{}
+	return safeclose.Close(mux.conn)
+}
+
+// NewSyncPublisher creates a publisher that publishes messages
+// to the given topic and waits for the broker acknowledgement.
+func (mux *Mux) NewSyncPublisher(topic string) *SyncPublisher {
+	return &SyncPublisher{mux: mux, topic: topic}
+}
+
+// NewConsumer subscribes for the messages of the given topic. The messages
+// are delivered to msgChan until the consumer is closed.
+func (mux *Mux) NewConsumer(topic string, msgChan chan *ConsumerMessage) (*Consumer, error) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	if _, found := mux.consumers[topic]; found {
+		return nil, fmt.Errorf("topic %q is already consumed", topic)
+	}
+	err := mux.conn.StartConsuming(topic, msgChan)
+	if err != nil {
+		return nil, err
+	}
+	consumer := &Consumer{mux: mux, topic: topic}
+	mux.consumers[topic] = consumer
+	return consumer, nil
+}
+
+// SyncPublisher publishes messages to one topic and waits for the broker
+// acknowledgement of each message.
+type SyncPublisher struct {
+	mux   *Mux
+	topic string
+}
+
+// Put publishes the key-value message and blocks until the broker acknowledges it.
+func (pub *SyncPublisher) Put(key []byte, value []byte) error {
+	_, _, err := pub.mux.conn.SendSync(pub.topic, key, value)
+	return err
+}
+
+// Consumer is a subscription for the messages of one topic.
+type Consumer struct {
+	mux   *Mux
+	topic string
+}
+
+// Close cancels the subscription.
+func (cons *Consumer) Close() error {
+	cons.mux.mu.Lock()
+	defer cons.mux.mu.Unlock()
+
+	if _, found := cons.mux.consumers[cons.topic]; !found {
+		return nil
+	}
+	delete(cons.mux.consumers, cons.topic)
+	return cons.mux.conn.StopConsuming(cons.topic)
+}
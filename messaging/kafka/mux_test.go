@@ -0,0 +1,211 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// fakeConnection records published messages and consumed topics.
+type fakeConnection struct {
+	published  map[string][]string
+	consumed   map[string]chan *ConsumerMessage
+	groups     map[string][]string
+	partitions map[string]int64
+	committed  map[string]int64
+	closed     bool
+}
+
+func newFakeConnection() *fakeConnection {
+	return &fakeConnection{
+		published:  map[string][]string{},
+		consumed:   map[string]chan *ConsumerMessage{},
+		groups:     map[string][]string{},
+		partitions: map[string]int64{},
+		committed:  map[string]int64{},
+	}
+}
+
+func (f *fakeConnection) SendSync(topic string, key []byte, value []byte) (int32, int64, error) {
+	f.published[topic] = append(f.published[topic], string(value))
+	return 0, int64(len(f.published[topic]) - 1), nil
+}
+
+func (f *fakeConnection) SendAsync(topic string, key []byte, value []byte,
+	succChan chan *ProducerMessage, errChan chan *ProducerError) {
+	f.published[topic] = append(f.published[topic], string(value))
+	succChan <- &ProducerMessage{Topic: topic, Key: key, Value: value,
+		Offset: int64(len(f.published[topic]) - 1)}
+}
+
+func (f *fakeConnection) StartConsuming(topic string, msgChan chan *ConsumerMessage) error {
+	f.consumed[topic] = msgChan
+	return nil
+}
+
+func (f *fakeConnection) StopConsuming(topic string) error {
+	delete(f.consumed, topic)
+	return nil
+}
+
+func (f *fakeConnection) StartPartitionConsuming(topic string, partition int32,
+	startOffset int64, msgChan chan *ConsumerMessage) error {
+	f.partitions[partitionName(topic, partition)] = startOffset
+	return nil
+}
+
+func (f *fakeConnection) StopPartitionConsuming(topic string, partition int32) error {
+	delete(f.partitions, partitionName(topic, partition))
+	return nil
+}
+
+func (f *fakeConnection) CommitOffset(groupID string, topic string, partition int32, offset int64) error {
+	f.committed[partitionName(topic, partition)] = offset
+	return nil
+}
+
+func (f *fakeConnection) StartGroupConsuming(groupID string, topics []string,
+	msgChan chan *ConsumerMessage, handler GroupHandler) error {
+	f.groups[groupID] = topics
+	if handler != nil {
+		handler.OnPartitionsAssigned([]PartitionAssignment{{Topic: topics[0], Partition: 0}})
+	}
+	return nil
+}
+
+func (f *fakeConnection) StopGroupConsuming(groupID string) error {
+	delete(f.groups, groupID)
+	return nil
+}
+
+func (f *fakeConnection) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestMux(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	mux := NewMuxUsingConnection(logrus.StandardLogger(), conn)
+	gomega.Expect(mux.Init()).To(gomega.BeNil())
+
+	// publishing goes through the shared connection
+	pub := mux.NewSyncPublisher("topic1")
+	gomega.Expect(pub.Put([]byte("key"), []byte("value"))).To(gomega.BeNil())
+	gomega.Expect(conn.published["topic1"]).To(gomega.ConsistOf("value"))
+
+	// consuming the same topic twice is reported
+	msgChan := make(chan *ConsumerMessage, 1)
+	consumer, err := mux.NewConsumer("topic2", msgChan)
+	gomega.Expect(err).To(gomega.BeNil())
+	_, err = mux.NewConsumer("topic2", msgChan)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// closing the consumer stops the subscription
+	gomega.Expect(consumer.Close()).To(gomega.BeNil())
+	gomega.Expect(conn.consumed).To(gomega.BeEmpty())
+
+	// closing the plugin releases the connection
+	gomega.Expect(mux.Close()).To(gomega.BeNil())
+	gomega.Expect(conn.closed).To(gomega.BeTrue())
+}
+
+func TestAsyncPublisher(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	mux := NewMuxUsingConnection(logrus.StandardLogger(), conn)
+	gomega.Expect(mux.Init()).To(gomega.BeNil())
+	defer mux.Close()
+
+	succChan := make(chan *ProducerMessage, 1)
+	errChan := make(chan *ProducerError, 1)
+	pub := mux.NewAsyncPublisher("topic1", succChan, errChan)
+	pub.Put([]byte("key"), []byte("value"))
+
+	var msg *ProducerMessage
+	gomega.Eventually(succChan).Should(gomega.Receive(&msg))
+	gomega.Expect(msg.Topic).To(gomega.BeEquivalentTo("topic1"))
+	gomega.Expect(conn.published["topic1"]).To(gomega.ConsistOf("value"))
+}
+
+func TestPartitionConsumer(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	mux := NewMuxUsingConnection(logrus.StandardLogger(), conn)
+	gomega.Expect(mux.Init()).To(gomega.BeNil())
+	defer mux.Close()
+
+	msgChan := make(chan *ConsumerMessage, 1)
+	consumer, err := mux.NewPartitionConsumer("topic1", 2, OffsetOldest, msgChan)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(conn.partitions["topic1/2"]).To(gomega.BeEquivalentTo(OffsetOldest))
+
+	// the same partition cannot be consumed twice
+	_, err = mux.NewPartitionConsumer("topic1", 2, OffsetNewest, msgChan)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// offsets are committed manually
+	gomega.Expect(consumer.CommitOffset("group1", 42)).To(gomega.BeNil())
+	gomega.Expect(conn.committed["topic1/2"]).To(gomega.BeEquivalentTo(42))
+
+	gomega.Expect(consumer.Close()).To(gomega.BeNil())
+	gomega.Expect(conn.partitions).To(gomega.BeEmpty())
+}
+
+// fakeGroupHandler records the rebalance notifications.
+type fakeGroupHandler struct {
+	assigned []PartitionAssignment
+}
+
+func (h *fakeGroupHandler) OnPartitionsAssigned(assigned []PartitionAssignment) {
+	h.assigned = append(h.assigned, assigned...)
+}
+
+func (h *fakeGroupHandler) OnPartitionsRevoked(revoked []PartitionAssignment) {}
+
+func TestGroupConsumer(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	conn := newFakeConnection()
+	mux := NewMuxUsingConnection(logrus.StandardLogger(), conn)
+	gomega.Expect(mux.Init()).To(gomega.BeNil())
+	defer mux.Close()
+
+	// group ID must be given when none is configured
+	msgChan := make(chan *ConsumerMessage, 1)
+	_, err := mux.NewGroupConsumer("", []string{"topic1"}, msgChan, nil)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// the handler is notified about the partition assignment
+	handler := &fakeGroupHandler{}
+	consumer, err := mux.NewGroupConsumer("group1", []string{"topic1"}, msgChan, handler)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(conn.groups).To(gomega.HaveKey("group1"))
+	gomega.Expect(handler.assigned).To(gomega.HaveLen(1))
+
+	// joining the same group twice is reported
+	_, err = mux.NewGroupConsumer("group1", []string{"topic2"}, msgChan, nil)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// closing the consumer leaves the group
+	gomega.Expect(consumer.Close()).To(gomega.BeNil())
+	gomega.Expect(conn.groups).To(gomega.BeEmpty())
+}
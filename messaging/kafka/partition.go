@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import "fmt"
+
+const (
+	// OffsetNewest instructs the partition consumer to start at the message
+	// produced next after the subscription.
+	OffsetNewest int64 = -1
+	// OffsetOldest instructs the partition consumer to start at the oldest
+	// message available on the broker.
+	OffsetOldest int64 = -2
+)
+
+// NewPartitionConsumer subscribes for the messages of one explicit partition
+// of the topic, starting at the given offset (possibly OffsetNewest
+// or OffsetOldest). Unlike the group consumer, the offsets are managed solely
+// by the caller - typically a stateful plugin keeping its own offset
+// checkpoints in the datastore.
+func (mux *Mux) NewPartitionConsumer(topic string, partition int32, startOffset int64,
+	msgChan chan *ConsumerMessage) (*PartitionConsumer, error) {
+
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+
+	name := partitionName(topic, partition)
+	if _, found := mux.partConsumers[name]; found {
+		return nil, fmt.Errorf("partition %s is already consumed", name)
+	}
+	err := mux.conn.StartPartitionConsuming(topic, partition, startOffset, msgChan)
+	if err != nil {
+		return nil, err
+	}
+	consumer := &PartitionConsumer{mux: mux, topic: topic, partition: partition}
+	mux.partConsumers[name] = consumer
+	return consumer, nil
+}
+
+// PartitionConsumer is a subscription for the messages of one explicit
+// partition of a topic.
+type PartitionConsumer struct {
+	mux       *Mux
+	topic     string
+	partition int32
+}
+
+// CommitOffset marks the offset as processed on behalf of the given consumer
+// group, so that a restarted consumer without its own checkpoint can resume
+// after it.
+func (cons *PartitionConsumer) CommitOffset(groupID string, offset int64) error {
+	return cons.mux.conn.CommitOffset(groupID, cons.topic, cons.partition, offset)
+}
+
+// Close cancels the subscription.
+func (cons *PartitionConsumer) Close() error {
+	cons.mux.mu.Lock()
+	defer cons.mux.mu.Unlock()
+
+	name := partitionName(cons.topic, cons.partition)
+	if _, found := cons.mux.partConsumers[name]; !found {
+		return nil
+	}
+	delete(cons.mux.partConsumers, name)
+	return cons.mux.conn.StopPartitionConsuming(cons.topic, cons.partition)
+}
+
+// partitionName identifies one partition of a topic in the consumer map.
+func partitionName(topic string, partition int32) string {
+	return fmt.Sprintf("%s/%d", topic, partition)
+}
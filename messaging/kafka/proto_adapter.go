@@ -0,0 +1,97 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ligato/cn-infra/messaging"
+)
+
+// ProtoSyncPublisher satisfies the transport-agnostic publisher interface.
+var _ messaging.ProtoPublisher = &ProtoSyncPublisher{}
+
+// NewProtoWatcher creates an adapter that exposes the messaging.ProtoWatcher
+// API on top of the multiplexer.
+func (mux *Mux) NewProtoWatcher() messaging.ProtoWatcher {
+	return &protoWatcher{mux: mux, subscriptions: map[string]*protoSubscription{}}
+}
+
+// protoWatcher implements messaging.ProtoWatcher using per-topic consumers
+// of the multiplexer.
+type protoWatcher struct {
+	mux           *Mux
+	mu            sync.Mutex
+	subscriptions map[string]*protoSubscription
+}
+
+// protoSubscription is the consumer of one topic together with its
+// conversion goroutine.
+type protoSubscription struct {
+	consumer *Consumer
+	stopChan chan struct{}
+}
+
+// Watch starts delivery of the messages of the topics to msgChan.
+func (watcher *protoWatcher) Watch(msgChan chan messaging.ProtoMessage, topics ...string) error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	for _, topic := range topics {
+		if _, found := watcher.subscriptions[topic]; found {
+			return fmt.Errorf("topic %q is already watched", topic)
+		}
+		rawChan := make(chan *ConsumerMessage)
+		consumer, err := watcher.mux.NewConsumer(topic, rawChan)
+		if err != nil {
+			return err
+		}
+		subscription := &protoSubscription{consumer: consumer, stopChan: make(chan struct{})}
+		watcher.subscriptions[topic] = subscription
+		go forwardMessages(rawChan, msgChan, subscription.stopChan)
+	}
+	return nil
+}
+
+// StopWatch cancels the delivery of the messages of the topic.
+func (watcher *protoWatcher) StopWatch(topic string) error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	subscription, found := watcher.subscriptions[topic]
+	if !found {
+		return fmt.Errorf("topic %q is not watched", topic)
+	}
+	delete(watcher.subscriptions, topic)
+	close(subscription.stopChan)
+	return subscription.consumer.Close()
+}
+
+// forwardMessages converts the consumed messages into ProtoMessages.
+func forwardMessages(rawChan chan *ConsumerMessage, msgChan chan messaging.ProtoMessage, stopChan chan struct{}) {
+	for {
+		select {
+		case <-stopChan:
+			return
+		case msg := <-rawChan:
+			select {
+			case msgChan <- messaging.NewProtoMessage(msg.Topic, string(msg.Key), msg.Value):
+			case <-stopChan:
+				return
+			}
+		}
+	}
+}
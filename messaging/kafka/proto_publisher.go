@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// NewAsyncPublisher creates a publisher that publishes messages to the given
+// topic without blocking. Broker acknowledgements are delivered to succChan,
+// failures to errChan.
+func (mux *Mux) NewAsyncPublisher(topic string, succChan chan *ProducerMessage, errChan chan *ProducerError) *AsyncPublisher {
+	return &AsyncPublisher{mux: mux, topic: topic, succChan: succChan, errChan: errChan}
+}
+
+// NewProtoSyncPublisher creates a publisher that publishes proto-serialized
+// messages to the given topic and waits for the broker acknowledgement
+// of each message. Messages with the same key are published to the same
+// partition, therefore their ordering is preserved.
+func (mux *Mux) NewProtoSyncPublisher(topic string) *ProtoSyncPublisher {
+	return &ProtoSyncPublisher{mux: mux, topic: topic, serializer: &keyval.SerializerProto{}}
+}
+
+// NewProtoAsyncPublisher creates a publisher that publishes proto-serialized
+// messages to the given topic without blocking. Broker acknowledgements are
+// delivered to succChan, failures to errChan.
+func (mux *Mux) NewProtoAsyncPublisher(topic string, succChan chan *ProducerMessage, errChan chan *ProducerError) *ProtoAsyncPublisher {
+	return &ProtoAsyncPublisher{mux: mux, topic: topic,
+		serializer: &keyval.SerializerProto{}, succChan: succChan, errChan: errChan}
+}
+
+// AsyncPublisher publishes messages to one topic without waiting
+// for the broker acknowledgement.
+type AsyncPublisher struct {
+	mux      *Mux
+	topic    string
+	succChan chan *ProducerMessage
+	errChan  chan *ProducerError
+}
+
+// Put publishes the key-value message. The result of the publishing
+// is delivered to the channels of the publisher.
+func (pub *AsyncPublisher) Put(key []byte, value []byte) {
+	pub.mux.conn.SendAsync(pub.topic, key, value, pub.succChan, pub.errChan)
+}
+
+// ProtoSyncPublisher publishes proto-serialized messages to one topic
+// and waits for the broker acknowledgement of each message.
+type ProtoSyncPublisher struct {
+	mux        *Mux
+	topic      string
+	serializer keyval.Serializer
+}
+
+// Put serializes the message, publishes it under the given partition key
+// and blocks until the broker acknowledges it.
+func (pub *ProtoSyncPublisher) Put(key string, msg proto.Message) error {
+	data, err := pub.serializer.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, _, err = pub.mux.conn.SendSync(pub.topic, []byte(key), data)
+	return err
+}
+
+// ProtoAsyncPublisher publishes proto-serialized messages to one topic
+// without waiting for the broker acknowledgement.
+type ProtoAsyncPublisher struct {
+	mux        *Mux
+	topic      string
+	serializer keyval.Serializer
+	succChan   chan *ProducerMessage
+	errChan    chan *ProducerError
+}
+
+// Put serializes the message and publishes it under the given partition key.
+// The result of the publishing is delivered to the channels of the publisher.
+func (pub *ProtoAsyncPublisher) Put(key string, msg proto.Message) error {
+	data, err := pub.serializer.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	pub.mux.conn.SendAsync(pub.topic, []byte(key), data, pub.succChan, pub.errChan)
+	return nil
+}
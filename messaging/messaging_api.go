@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messaging
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// ProtoPublisher publishes proto-serialized messages to one topic.
+type ProtoPublisher interface {
+	// Put publishes the message under the given partition key. Messages
+	// with the same key preserve their ordering where the transport
+	// supports partitioning.
+	Put(key string, msg proto.Message) error
+}
+
+// ProtoWatcher allows to subscribe for proto-modelled messages of topics.
+type ProtoWatcher interface {
+	// Watch starts delivery of the messages of the topics to msgChan.
+	Watch(msgChan chan ProtoMessage, topics ...string) error
+	// StopWatch cancels the delivery of the messages of the topic.
+	StopWatch(topic string) error
+}
+
+// ProtoMessage is a message with a proto-modelled payload received from a topic.
+type ProtoMessage interface {
+	// GetTopic returns the topic the message was received from.
+	GetTopic() string
+	// GetKey returns the partition key of the message.
+	GetKey() string
+	// GetValue unmarshals the payload of the message into the msg argument.
+	GetValue(msg proto.Message) error
+}
+
+// NewProtoMessage wraps raw message data into a ProtoMessage.
+func NewProtoMessage(topic string, key string, data []byte) ProtoMessage {
+	return &protoMessage{topic: topic, key: key, data: data, serializer: &keyval.SerializerProto{}}
+}
+
+// protoMessage implements ProtoMessage on top of raw message data.
+type protoMessage struct {
+	topic      string
+	key        string
+	data       []byte
+	serializer keyval.Serializer
+}
+
+// GetTopic returns the topic the message was received from.
+func (m *protoMessage) GetTopic() string {
+	return m.topic
+}
+
+// GetKey returns the partition key of the message.
+func (m *protoMessage) GetKey() string {
+	return m.key
+}
+
+// GetValue unmarshals the payload of the message into the msg argument.
+func (m *protoMessage) GetValue(msg proto.Message) error {
+	return m.serializer.Unmarshal(m.data, msg)
+}
+
+// GetBytes returns the raw payload of the message.
+func (m *protoMessage) GetBytes() []byte {
+	return m.data
+}
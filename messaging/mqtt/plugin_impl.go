@@ -0,0 +1,239 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/utils/safeclose"
+)
+
+// QoS is the MQTT delivery guarantee of a publishing or a subscription.
+type QoS byte
+
+const (
+	// AtMostOnce delivery (QoS 0).
+	AtMostOnce QoS = 0
+	// AtLeastOnce delivery (QoS 1).
+	AtLeastOnce QoS = 1
+	// ExactlyOnce delivery (QoS 2).
+	ExactlyOnce QoS = 2
+)
+
+// Config holds the settings of the MQTT connection.
+type Config struct {
+	// BrokerAddress of the MQTT broker, e.g. "tcp://127.0.0.1:1883".
+	BrokerAddress string `json:"broker-address"`
+	// ClientID identifies the agent on the broker.
+	ClientID string `json:"client-id"`
+	// QoS is the default delivery guarantee of publishers and subscriptions.
+	QoS QoS `json:"qos"`
+	// Username and Password authenticate the agent against the broker.
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TLS configures the encryption of the broker connection.
+	TLS TLSConfig `json:"tls"`
+}
+
+// TLSConfig configures the encryption of the broker connection.
+type TLSConfig struct {
+	Enabled               bool   `json:"enabled"`
+	Certfile              string `json:"cert-file"`
+	Keyfile               string `json:"key-file"`
+	CAfile                string `json:"ca-file"`
+	InsecureSkipTLSVerify bool   `json:"insecure-skip-tls-verify"`
+}
+
+// defaultBrokerAddress is used when no broker address is configured.
+const defaultBrokerAddress = "tcp://127.0.0.1:1883"
+
+// ConfigFromFile loads the MQTT configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	if config.BrokerAddress == "" {
+		config.BrokerAddress = defaultBrokerAddress
+	}
+	return config, nil
+}
+
+// Message is a message received from an MQTT topic.
+type Message struct {
+	Topic    string
+	Payload  []byte
+	Retained bool
+}
+
+// Connection represents the connection to the MQTT broker. It is
+// the extension point between the plugin and a concrete MQTT client library.
+type Connection interface {
+	// Publish publishes the payload to the topic with the given QoS.
+	// Retained messages are stored by the broker and delivered to future
+	// subscribers of the topic.
+	Publish(topic string, payload []byte, qos QoS, retained bool) error
+	// Subscribe begins delivery of the messages of the topic to msgChan.
+	Subscribe(topic string, qos QoS, msgChan chan *Message) error
+	// Unsubscribe cancels the delivery of the messages of the topic.
+	Unsubscribe(topic string) error
+	// Close releases the resources of the connection.
+	Close() error
+}
+
+// ConnectionFactory creates the connection to the MQTT broker described
+// by the configuration. The embedding application is supposed to assign
+// a factory backed by a concrete MQTT client library before the plugin
+// is initialized from a config file.
+var ConnectionFactory func(config *Config) (Connection, error)
+
+// Plugin provides access to a shared MQTT connection. It implements
+// the core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	mu          sync.Mutex
+	conn        Connection
+	subscribers map[string]*Subscriber
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration
+// of the MQTT connection is loaded from the file during Init. The connection
+// itself is created by the ConnectionFactory.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile, subscribers: map[string]*Subscriber{}}
+}
+
+// NewPluginUsingConnection creates a new instance of the plugin on top
+// of an already established connection.
+func NewPluginUsingConnection(log logging.Logger, conn Connection) *Plugin {
+	return &Plugin{log: log, conn: conn, subscribers: map[string]*Subscriber{}}
+}
+
+// Init establishes the connection to the MQTT broker unless an existing
+// connection was injected by the constructor.
+func (plugin *Plugin) Init() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.conn != nil {
+		return nil
+	}
+	if ConnectionFactory == nil {
+		return errors.New("no MQTT connection was injected and no ConnectionFactory is registered")
+	}
+	config, err := ConfigFromFile(plugin.configFile)
+	if err != nil {
+		return err
+	}
+	plugin.config = config
+	plugin.conn, err = ConnectionFactory(config)
+	if err != nil {
+		plugin.log.Errorf("Failed to connect to MQTT broker %s, Error: '%s'", config.BrokerAddress, err)
+		return err
+	}
+	plugin.log.Debug("MQTT plugin connected to ", config.BrokerAddress)
+	return nil
+}
+
+// Close stops all subscribers and releases the connection.
+func (plugin *Plugin) Close() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	for topic := range plugin.subscribers {
+		err := plugin.conn.Unsubscribe(topic)
+		if err != nil {
+			plugin.log.Warn("err unsubscribing topic ", topic, " ", err)
+		}
+	}
+	plugin.subscribers = map[string]*Subscriber{}
+	return safeclose.Close(plugin.conn)
+}
+
+// qos returns the configured default QoS.
+func (plugin *Plugin) qos() QoS {
+	if plugin.config != nil {
+		return plugin.config.QoS
+	}
+	return AtMostOnce
+}
+
+// NewPublisher creates a publisher that publishes messages to the given topic
+// with the configured QoS.
+func (plugin *Plugin) NewPublisher(topic string, retained bool) *Publisher {
+	return &Publisher{plugin: plugin, topic: topic, qos: plugin.qos(), retained: retained}
+}
+
+// NewSubscriber subscribes for the messages of the given topic
+// with the configured QoS.
+func (plugin *Plugin) NewSubscriber(topic string, msgChan chan *Message) (*Subscriber, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if _, found := plugin.subscribers[topic]; found {
+		return nil, fmt.Errorf("topic %q is already subscribed", topic)
+	}
+	err := plugin.conn.Subscribe(topic, plugin.qos(), msgChan)
+	if err != nil {
+		return nil, err
+	}
+	subscriber := &Subscriber{plugin: plugin, topic: topic}
+	plugin.subscribers[topic] = subscriber
+	return subscriber, nil
+}
+
+// Publisher publishes messages to one topic.
+type Publisher struct {
+	plugin   *Plugin
+	topic    string
+	qos      QoS
+	retained bool
+}
+
+// Put publishes the payload with the QoS and retention of the publisher.
+func (pub *Publisher) Put(payload []byte) error {
+	return pub.plugin.conn.Publish(pub.topic, payload, pub.qos, pub.retained)
+}
+
+// Subscriber is a subscription for the messages of one topic.
+type Subscriber struct {
+	plugin *Plugin
+	topic  string
+}
+
+// Close cancels the subscription.
+func (sub *Subscriber) Close() error {
+	sub.plugin.mu.Lock()
+	defer sub.plugin.mu.Unlock()
+
+	if _, found := sub.plugin.subscribers[sub.topic]; !found {
+		return nil
+	}
+	delete(sub.plugin.subscribers, sub.topic)
+	return sub.plugin.conn.Unsubscribe(sub.topic)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/utils/safeclose"
+)
+
+// Config holds the settings of the NATS connection.
+type Config struct {
+	// Servers is the list of NATS server addresses.
+	Servers []string `json:"servers"`
+	// QueueGroup is the default queue group of the subscriptions, so that
+	// multiple agent replicas share the subjects. Empty disables queueing.
+	QueueGroup string `json:"queue-group"`
+	// JetStream enables at-least-once delivery through a JetStream stream.
+	JetStream bool `json:"jetstream"`
+	// DurableName identifies the JetStream consumer of the agent so that
+	// a restarted agent resumes at its last acknowledged message.
+	DurableName string `json:"durable-name"`
+	// CredentialsFile is the path to the NATS credentials file.
+	CredentialsFile string `json:"credentials-file"`
+}
+
+// defaultServer is used when no server address is configured.
+const defaultServer = "nats://127.0.0.1:4222"
+
+// ConfigFromFile loads the NATS configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Servers) == 0 {
+		config.Servers = []string{defaultServer}
+	}
+	return config, nil
+}
+
+// Message is a message received from a NATS subject.
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// Connection represents the shared connection to the NATS server.
+// It is the extension point between the plugin and a concrete NATS
+// client library.
+type Connection interface {
+	// Publish publishes the message to the subject and blocks until
+	// the server (or JetStream) acknowledges it.
+	Publish(subject string, data []byte) error
+	// Subscribe begins delivery of the messages of the subject to msgChan.
+	// Non-empty queueGroup makes the subscription load-balanced among
+	// the members of the group.
+	Subscribe(subject string, queueGroup string, msgChan chan *Message) error
+	// Unsubscribe cancels the delivery of the messages of the subject.
+	Unsubscribe(subject string) error
+	// Close releases the resources of the connection.
+	Close() error
+}
+
+// ConnectionFactory creates the connection to the NATS server described
+// by the configuration. The embedding application is supposed to assign
+// a factory backed by a concrete NATS client library before the plugin
+// is initialized from a config file.
+var ConnectionFactory func(config *Config) (Connection, error)
+
+// Plugin provides access to a shared NATS connection. It implements
+// the core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	mu          sync.Mutex
+	conn        Connection
+	subscribers map[string]*Subscriber
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration
+// of the NATS connection is loaded from the file during Init. The connection
+// itself is created by the ConnectionFactory.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile, subscribers: map[string]*Subscriber{}}
+}
+
+// NewPluginUsingConnection creates a new instance of the plugin on top
+// of an already established connection.
+func NewPluginUsingConnection(log logging.Logger, conn Connection) *Plugin {
+	return &Plugin{log: log, conn: conn, subscribers: map[string]*Subscriber{}}
+}
+
+// Init establishes the connection to the NATS server unless an existing
+// connection was injected by the constructor.
+func (plugin *Plugin) Init() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.conn != nil {
+		return nil
+	}
+	if ConnectionFactory == nil {
+		return errors.New("no NATS connection was injected and no ConnectionFactory is registered")
+	}
+	config, err := ConfigFromFile(plugin.configFile)
+	if err != nil {
+		return err
+	}
+	plugin.config = config
+	plugin.conn, err = ConnectionFactory(config)
+	if err != nil {
+		plugin.log.Errorf("Failed to connect to NATS server(s) %v, Error: '%s'", config.Servers, err)
+		return err
+	}
+	plugin.log.Debug("NATS plugin connected to ", config.Servers)
+	return nil
+}
+
+// Close stops all subscribers and releases the connection.
+func (plugin *Plugin) Close() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	for subject := range plugin.subscribers {
+		err := plugin.conn.Unsubscribe(subject)
+		if err != nil {
+			plugin.log.Warn("err unsubscribing subject ", subject, " ", err)
+		}
+	}
+	plugin.subscribers = map[string]*Subscriber{}
+	return safeclose.Close(plugin.conn)
+}
+
+// NewPublisher creates a publisher that publishes messages to the given subject.
+func (plugin *Plugin) NewPublisher(subject string) *Publisher {
+	return &Publisher{plugin: plugin, subject: subject}
+}
+
+// NewSubscriber subscribes for the messages of the given subject. The queue
+// group configured for the plugin is applied to the subscription.
+func (plugin *Plugin) NewSubscriber(subject string, msgChan chan *Message) (*Subscriber, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if _, found := plugin.subscribers[subject]; found {
+		return nil, fmt.Errorf("subject %q is already subscribed", subject)
+	}
+	queueGroup := ""
+	if plugin.config != nil {
+		queueGroup = plugin.config.QueueGroup
+	}
+	err := plugin.conn.Subscribe(subject, queueGroup, msgChan)
+	if err != nil {
+		return nil, err
+	}
+	subscriber := &Subscriber{plugin: plugin, subject: subject}
+	plugin.subscribers[subject] = subscriber
+	return subscriber, nil
+}
+
+// Publisher publishes messages to one subject.
+type Publisher struct {
+	plugin  *Plugin
+	subject string
+}
+
+// Put publishes the message and blocks until the server acknowledges it.
+func (pub *Publisher) Put(data []byte) error {
+	return pub.plugin.conn.Publish(pub.subject, data)
+}
+
+// Subscriber is a subscription for the messages of one subject.
+type Subscriber struct {
+	plugin  *Plugin
+	subject string
+}
+
+// Close cancels the subscription.
+func (sub *Subscriber) Close() error {
+	sub.plugin.mu.Lock()
+	defer sub.plugin.mu.Unlock()
+
+	if _, found := sub.plugin.subscribers[sub.subject]; !found {
+		return nil
+	}
+	delete(sub.plugin.subscribers, sub.subject)
+	return sub.plugin.conn.Unsubscribe(sub.subject)
+}
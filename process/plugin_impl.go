@@ -0,0 +1,136 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// API is the process management interface the plugin exposes to other
+// plugins.
+type API interface {
+	// NewProcess registers a process defined by the command and its
+	// arguments. The process is not started.
+	NewProcess(name string, command string, args ...string) (*ManagedProcess, error)
+	// AttachProcess registers an already running process by its PID.
+	AttachProcess(name string, pid int) (*ManagedProcess, error)
+	// GetProcess returns the process registered under the given name.
+	GetProcess(name string) (*ManagedProcess, bool)
+	// ListProcesses returns all registered processes.
+	ListProcesses() []*ManagedProcess
+}
+
+// Plugin manages external OS processes. It implements the core.Plugin
+// interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log logging.Logger
+
+	mu        sync.Mutex
+	processes map[string]*ManagedProcess
+}
+
+// NewPlugin creates a new instance of the plugin.
+func NewPlugin(log logging.Logger) *Plugin {
+	return &Plugin{log: log, processes: map[string]*ManagedProcess{}}
+}
+
+// Init does nothing; processes are registered on demand by other
+// plugins.
+func (plugin *Plugin) Init() error {
+	return nil
+}
+
+// Close stops all processes that were started (not attached to) by this
+// plugin and are still running.
+func (plugin *Plugin) Close() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	for _, proc := range plugin.processes {
+		if proc.attached || !proc.IsAlive() {
+			continue
+		}
+		if err := proc.Stop(); err != nil {
+			plugin.log.Warn("Failed to stop process ", proc.Name(), ": ", err)
+		}
+	}
+	return nil
+}
+
+// NewProcess registers a process defined by the command and its
+// arguments. The process is not started; call Start on the returned
+// instance.
+func (plugin *Plugin) NewProcess(name string, command string, args ...string) (*ManagedProcess, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if _, found := plugin.processes[name]; found {
+		return nil, fmt.Errorf("process %q is already registered", name)
+	}
+	proc := &ManagedProcess{
+		log:     plugin.log,
+		name:    name,
+		command: command,
+		args:    args,
+	}
+	plugin.processes[name] = proc
+	return proc, nil
+}
+
+// AttachProcess registers an already running process by its PID. The
+// output of an attached process is not captured and only a subset of
+// operations (signals, liveness, resource usage) is available.
+func (plugin *Plugin) AttachProcess(name string, pid int) (*ManagedProcess, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if _, found := plugin.processes[name]; found {
+		return nil, fmt.Errorf("process %q is already registered", name)
+	}
+	if err := syscall.Kill(pid, syscall.Signal(0)); err != nil {
+		return nil, fmt.Errorf("no running process with PID %d: %v", pid, err)
+	}
+	proc := &ManagedProcess{
+		log:      plugin.log,
+		name:     name,
+		pid:      pid,
+		attached: true,
+	}
+	plugin.processes[name] = proc
+	return proc, nil
+}
+
+// GetProcess returns the process registered under the given name.
+func (plugin *Plugin) GetProcess(name string) (*ManagedProcess, bool) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	proc, found := plugin.processes[name]
+	return proc, found
+}
+
+// ListProcesses returns all registered processes.
+func (plugin *Plugin) ListProcesses() []*ManagedProcess {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	processes := make([]*ManagedProcess, 0, len(plugin.processes))
+	for _, proc := range plugin.processes {
+		processes = append(processes, proc)
+	}
+	return processes
+}
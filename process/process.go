@@ -0,0 +1,258 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// clockTicksPerSecond is the kernel USER_HZ the CPU times in
+// /proc/<pid>/stat are expressed in.
+const clockTicksPerSecond = 100
+
+// Usage is a snapshot of the resource consumption of a process.
+type Usage struct {
+	// CPUTime is the total time the process spent on the CPU
+	// (user + system).
+	CPUTime time.Duration
+	// MemoryRSS is the resident set size in bytes.
+	MemoryRSS int64
+}
+
+// ManagedProcess represents one external process under the control of
+// the plugin. Instances are created by Plugin.NewProcess or
+// Plugin.AttachProcess.
+type ManagedProcess struct {
+	log     logging.Logger
+	name    string
+	command string
+	args    []string
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	pid      int
+	attached bool
+	done     chan struct{}
+	exitErr  error
+}
+
+// Name returns the name the process was registered under.
+func (proc *ManagedProcess) Name() string {
+	return proc.name
+}
+
+// GetPid returns the PID of the process, or zero when it was not
+// started yet.
+func (proc *ManagedProcess) GetPid() int {
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	return proc.pid
+}
+
+// Start launches the process and begins capturing its output. A process
+// that terminated can be started again.
+func (proc *ManagedProcess) Start() error {
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+
+	if proc.attached {
+		return errors.New("cannot start an attached process")
+	}
+	if proc.isAlive() {
+		return fmt.Errorf("process %s is already running with PID %d", proc.name, proc.pid)
+	}
+
+	cmd := exec.Command(proc.command, proc.args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	proc.cmd = cmd
+	proc.pid = cmd.Process.Pid
+	proc.done = make(chan struct{})
+	proc.exitErr = nil
+
+	go proc.captureOutput(stdout, "stdout")
+	go proc.captureOutput(stderr, "stderr")
+	go proc.monitor(cmd, proc.done)
+
+	proc.log.WithField("process", proc.name).Debug("Started with PID ", proc.pid)
+	return nil
+}
+
+// Stop sends SIGTERM to the process.
+func (proc *ManagedProcess) Stop() error {
+	return proc.signal(syscall.SIGTERM)
+}
+
+// Kill sends SIGKILL to the process.
+func (proc *ManagedProcess) Kill() error {
+	return proc.signal(syscall.SIGKILL)
+}
+
+// Wait blocks until the process terminates and returns the error
+// reported by its exit (nil for a zero exit status). For an attached
+// process the liveness is polled.
+func (proc *ManagedProcess) Wait() error {
+	proc.mu.Lock()
+	done := proc.done
+	attached := proc.attached
+	proc.mu.Unlock()
+
+	if attached {
+		for proc.IsAlive() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return nil
+	}
+	if done == nil {
+		return fmt.Errorf("process %s was not started", proc.name)
+	}
+	<-done
+
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	return proc.exitErr
+}
+
+// IsAlive returns true while the process is running.
+func (proc *ManagedProcess) IsAlive() bool {
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	return proc.isAlive()
+}
+
+// MeasureUsage reads the current resource usage of the process from
+// procfs.
+func (proc *ManagedProcess) MeasureUsage() (*Usage, error) {
+	pid := proc.GetPid()
+	if pid == 0 {
+		return nil, fmt.Errorf("process %s was not started", proc.name)
+	}
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	// the command in field 2 may contain spaces; parse after the
+	// closing parenthesis
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	if len(fields) < 13 {
+		return nil, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	usage := &Usage{
+		CPUTime: time.Duration(utime+stime) * time.Second / clockTicksPerSecond,
+	}
+
+	status, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		rssFields := strings.Fields(line)
+		if len(rssFields) >= 2 {
+			rssKb, err := strconv.ParseInt(rssFields[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			usage.MemoryRSS = rssKb * 1024
+		}
+		break
+	}
+	return usage, nil
+}
+
+// signal delivers the given signal to the process.
+func (proc *ManagedProcess) signal(sig syscall.Signal) error {
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	if proc.pid == 0 {
+		return fmt.Errorf("process %s was not started", proc.name)
+	}
+	return syscall.Kill(proc.pid, sig)
+}
+
+// isAlive checks the liveness of the process. The caller must hold the
+// mutex.
+func (proc *ManagedProcess) isAlive() bool {
+	if proc.attached {
+		return proc.pid != 0 && syscall.Kill(proc.pid, syscall.Signal(0)) == nil
+	}
+	if proc.done == nil {
+		return false
+	}
+	select {
+	case <-proc.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// captureOutput forwards the lines of the given stream into the logging
+// subsystem.
+func (proc *ManagedProcess) captureOutput(stream io.Reader, streamName string) {
+	log := proc.log.WithFields(map[string]interface{}{"process": proc.name, "stream": streamName})
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		log.Info(scanner.Text())
+	}
+}
+
+// monitor waits for the termination of the process and records its exit
+// status.
+func (proc *ManagedProcess) monitor(cmd *exec.Cmd, done chan struct{}) {
+	err := cmd.Wait()
+
+	proc.mu.Lock()
+	proc.exitErr = err
+	proc.mu.Unlock()
+	close(done)
+
+	if err != nil {
+		proc.log.WithField("process", proc.name).Warn("Terminated: ", err)
+	} else {
+		proc.log.WithField("process", proc.name).Debug("Terminated successfully")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestStartWaitExitStatus(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	proc, err := plugin.NewProcess("true", "sh", "-c", "exit 0")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(proc.Start()).To(gomega.BeNil())
+	gomega.Expect(proc.Wait()).To(gomega.BeNil())
+	gomega.Expect(proc.IsAlive()).To(gomega.BeFalse())
+
+	failing, err := plugin.NewProcess("false", "sh", "-c", "exit 3")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(failing.Start()).To(gomega.BeNil())
+	gomega.Expect(failing.Wait()).NotTo(gomega.BeNil())
+}
+
+func TestStopAndRestart(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	defer plugin.Close()
+
+	proc, err := plugin.NewProcess("sleeper", "sleep", "60")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(proc.Start()).To(gomega.BeNil())
+	gomega.Expect(proc.IsAlive()).To(gomega.BeTrue())
+	gomega.Expect(proc.GetPid()).NotTo(gomega.BeZero())
+
+	// a second registration under the same name is refused
+	_, err = plugin.NewProcess("sleeper", "sleep", "60")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	gomega.Expect(proc.Stop()).To(gomega.BeNil())
+	proc.Wait()
+	gomega.Expect(proc.IsAlive()).To(gomega.BeFalse())
+
+	// a terminated process can be started again
+	gomega.Expect(proc.Start()).To(gomega.BeNil())
+	gomega.Expect(proc.IsAlive()).To(gomega.BeTrue())
+	gomega.Expect(proc.Stop()).To(gomega.BeNil())
+	proc.Wait()
+}
+
+func TestAttachAndUsage(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger())
+	defer plugin.Close()
+
+	proc, err := plugin.AttachProcess("self", os.Getpid())
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(proc.IsAlive()).To(gomega.BeTrue())
+
+	usage, err := proc.MeasureUsage()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(usage.MemoryRSS).To(gomega.BeNumerically(">", 0))
+
+	_, err = plugin.AttachProcess("missing", 1<<30)
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	registered, found := plugin.GetProcess("self")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(registered).To(gomega.Equal(proc))
+	gomega.Expect(plugin.ListProcesses()).To(gomega.HaveLen(1))
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supervisor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/process"
+)
+
+// Restart policies of a supervised program.
+const (
+	// RestartNever leaves a terminated program down.
+	RestartNever = "never"
+	// RestartAlways restarts the program after any termination.
+	RestartAlways = "always"
+	// RestartOnFailure restarts the program only after a non-zero exit.
+	RestartOnFailure = "on-failure"
+)
+
+// defaultBackoff is the delay before the first restart of a program;
+// it doubles with every consecutive restart up to maxBackoff.
+const (
+	defaultBackoff = 1 * time.Second
+	maxBackoff     = 1 * time.Minute
+)
+
+// Config lists the programs the supervisor runs. The programs are
+// started in the order they are listed in.
+type Config struct {
+	Programs []ProgramConfig `json:"programs"`
+}
+
+// ProgramConfig describes one supervised program.
+type ProgramConfig struct {
+	// Name identifies the program in logs and events.
+	Name string `json:"name"`
+	// Command and Args define the executable to run.
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	// RestartPolicy is one of never, always, on-failure (the default).
+	RestartPolicy string `json:"restart-policy"`
+	// MaxRestarts bounds the number of consecutive restarts
+	// (zero means unlimited).
+	MaxRestarts int `json:"max-restarts"`
+	// Backoff is the delay before the first restart; it doubles with
+	// every consecutive restart.
+	Backoff time.Duration `json:"backoff"`
+}
+
+// ConfigFromFile loads the supervisor configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// EventType distinguishes the events delivered to the hooks.
+type EventType int
+
+const (
+	// ProgramStarted is fired after a program (re)start.
+	ProgramStarted EventType = iota
+	// ProgramCrashed is fired after a program terminates with an error.
+	ProgramCrashed
+)
+
+// Event describes a state change of a supervised program.
+type Event struct {
+	// Type of the event.
+	Type EventType
+	// Program is the name of the program.
+	Program string
+	// Pid of the program (zero for ProgramCrashed).
+	Pid int
+	// Error reported by the termination (nil for ProgramStarted).
+	Error error
+}
+
+// Hook is a function executed on every program event. Hooks can for
+// example mark the agent unhealthy in the status check when a critical
+// program crashes.
+type Hook func(event Event)
+
+// Plugin supervises the programs listed in its configuration. It
+// implements the core.Plugin interface, therefore it can be loaded with
+// other plugins.
+type Plugin struct {
+	log        logging.Logger
+	processes  process.API
+	config     *Config
+	configFile string
+
+	mu      sync.Mutex
+	hooks   []Hook
+	closing chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration is
+// loaded from the file during Init and the programs are run on top of
+// the given process plugin.
+func NewPlugin(log logging.Logger, processes process.API, configFile string) *Plugin {
+	return &Plugin{log: log, processes: processes, configFile: configFile}
+}
+
+// NewPluginUsingConfig creates a new instance of the plugin with an
+// already parsed configuration.
+func NewPluginUsingConfig(log logging.Logger, processes process.API, config *Config) *Plugin {
+	return &Plugin{log: log, processes: processes, config: config}
+}
+
+// AddHook registers a hook executed on every program event. Hooks must
+// be registered before Init.
+func (plugin *Plugin) AddHook(hook Hook) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	plugin.hooks = append(plugin.hooks, hook)
+}
+
+// Init loads the configuration and starts the programs in the order
+// they are listed in.
+func (plugin *Plugin) Init() error {
+	if plugin.config == nil {
+		config, err := ConfigFromFile(plugin.configFile)
+		if err != nil {
+			return err
+		}
+		plugin.config = config
+	}
+	plugin.closing = make(chan struct{})
+
+	for i := range plugin.config.Programs {
+		program := &plugin.config.Programs[i]
+		switch program.RestartPolicy {
+		case "":
+			program.RestartPolicy = RestartOnFailure
+		case RestartNever, RestartAlways, RestartOnFailure:
+		default:
+			return fmt.Errorf("unknown restart policy %q of program %s",
+				program.RestartPolicy, program.Name)
+		}
+		if program.Backoff == 0 {
+			program.Backoff = defaultBackoff
+		}
+		proc, err := plugin.processes.NewProcess(program.Name, program.Command, program.Args...)
+		if err != nil {
+			return err
+		}
+		if err := proc.Start(); err != nil {
+			return fmt.Errorf("failed to start program %s: %v", program.Name, err)
+		}
+		plugin.notify(Event{Type: ProgramStarted, Program: program.Name, Pid: proc.GetPid()})
+
+		plugin.wg.Add(1)
+		go plugin.supervise(program, proc)
+	}
+	return nil
+}
+
+// Close stops all supervised programs and waits until the supervising
+// goroutines finish.
+func (plugin *Plugin) Close() error {
+	close(plugin.closing)
+	for _, program := range plugin.config.Programs {
+		if proc, found := plugin.processes.GetProcess(program.Name); found && proc.IsAlive() {
+			if err := proc.Stop(); err != nil {
+				plugin.log.Warn("Failed to stop program ", program.Name, ": ", err)
+			}
+		}
+	}
+	plugin.wg.Wait()
+	return nil
+}
+
+// supervise watches one program and applies its restart policy.
+func (plugin *Plugin) supervise(program *ProgramConfig, proc *process.ManagedProcess) {
+	defer plugin.wg.Done()
+
+	restarts := 0
+	backoff := program.Backoff
+	for {
+		err := proc.Wait()
+
+		select {
+		case <-plugin.closing:
+			return
+		default:
+		}
+
+		if err != nil {
+			plugin.log.Warn("Program ", program.Name, " crashed: ", err)
+			plugin.notify(Event{Type: ProgramCrashed, Program: program.Name, Error: err})
+		}
+		switch {
+		case program.RestartPolicy == RestartNever:
+			return
+		case program.RestartPolicy == RestartOnFailure && err == nil:
+			return
+		}
+		restarts++
+		if program.MaxRestarts > 0 && restarts > program.MaxRestarts {
+			plugin.log.Error("Program ", program.Name, " exceeded ",
+				program.MaxRestarts, " restarts, giving up")
+			return
+		}
+
+		select {
+		case <-plugin.closing:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+
+		if err := proc.Start(); err != nil {
+			plugin.log.Error("Failed to restart program ", program.Name, ": ", err)
+			return
+		}
+		plugin.log.Info("Restarted program ", program.Name, " (restart #", restarts, ")")
+		plugin.notify(Event{Type: ProgramStarted, Program: program.Name, Pid: proc.GetPid()})
+	}
+}
+
+// notify executes the registered hooks.
+func (plugin *Plugin) notify(event Event) {
+	plugin.mu.Lock()
+	hooks := make([]Hook, len(plugin.hooks))
+	copy(hooks, plugin.hooks)
+	plugin.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+}
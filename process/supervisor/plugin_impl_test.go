@@ -0,0 +1,125 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supervisor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/process"
+	"github.com/onsi/gomega"
+)
+
+// eventRecorder collects the events delivered to a hook.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (rec *eventRecorder) hook(event Event) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.events = append(rec.events, event)
+}
+
+func (rec *eventRecorder) count(eventType EventType) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	count := 0
+	for _, event := range rec.events {
+		if event.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRestartOnFailureWithMaxRestarts(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	processes := process.NewPlugin(logrus.StandardLogger())
+	defer processes.Close()
+
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), processes, &Config{
+		Programs: []ProgramConfig{
+			{
+				Name:          "crasher",
+				Command:       "sh",
+				Args:          []string{"-c", "exit 1"},
+				RestartPolicy: RestartOnFailure,
+				MaxRestarts:   2,
+				Backoff:       10 * time.Millisecond,
+			},
+		},
+	})
+	recorder := &eventRecorder{}
+	plugin.AddHook(recorder.hook)
+
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	gomega.Eventually(func() int {
+		return recorder.count(ProgramCrashed)
+	}, 5*time.Second, 10*time.Millisecond).Should(gomega.Equal(3))
+
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+	// initial start + two restarts
+	gomega.Expect(recorder.count(ProgramStarted)).To(gomega.Equal(3))
+}
+
+func TestRestartNeverOnSuccess(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	processes := process.NewPlugin(logrus.StandardLogger())
+	defer processes.Close()
+
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), processes, &Config{
+		Programs: []ProgramConfig{
+			{Name: "oneshot", Command: "sh", Args: []string{"-c", "exit 0"}},
+			{Name: "daemon", Command: "sleep", Args: []string{"60"}},
+		},
+	})
+	recorder := &eventRecorder{}
+	plugin.AddHook(recorder.hook)
+
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	oneshot, found := processes.GetProcess("oneshot")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Eventually(oneshot.IsAlive, 5*time.Second, 10*time.Millisecond).Should(gomega.BeFalse())
+
+	daemon, found := processes.GetProcess("daemon")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(daemon.IsAlive()).To(gomega.BeTrue())
+
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+	gomega.Expect(daemon.IsAlive()).To(gomega.BeFalse())
+	// the successful exit is not restarted under on-failure policy
+	gomega.Expect(recorder.count(ProgramStarted)).To(gomega.Equal(2))
+}
+
+func TestUnknownRestartPolicy(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	processes := process.NewPlugin(logrus.StandardLogger())
+	defer processes.Close()
+
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), processes, &Config{
+		Programs: []ProgramConfig{
+			{Name: "bad", Command: "sleep", Args: []string{"60"}, RestartPolicy: "sometimes"},
+		},
+	})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Permission names an operation that a handler may require, e.g. "read"
+// or "admin".
+type Permission string
+
+// Config holds the settings of the authenticator.
+type Config struct {
+	// HMACSecret is the shared secret the HMAC-signed tokens are
+	// verified with.
+	HMACSecret string `json:"hmac-secret"`
+	// RolesClaim is the name of the token claim holding the role names.
+	// Defaults to "roles".
+	RolesClaim string `json:"roles-claim"`
+	// RolePermissions maps a role name to the permissions it grants.
+	RolePermissions map[string][]Permission `json:"role-permissions"`
+}
+
+// Identity describes an authenticated caller: the subject of the token
+// and the roles extracted from its claims.
+type Identity struct {
+	Subject string
+	Roles   []string
+
+	permissions map[Permission]bool
+}
+
+// Allowed tells whether any role of the caller grants the permission.
+func (identity *Identity) Allowed(permission Permission) bool {
+	return identity.permissions[permission]
+}
+
+// Authenticator validates bearer tokens and maps their claims to roles
+// and permissions.
+type Authenticator struct {
+	config *Config
+}
+
+// NewAuthenticator creates a new authenticator with the given settings.
+func NewAuthenticator(config *Config) (*Authenticator, error) {
+	if config.HMACSecret == "" {
+		return nil, fmt.Errorf("missing HMAC secret in the auth configuration")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	return &Authenticator{config: config}, nil
+}
+
+// Authenticate validates the bearer token and returns the identity
+// of the caller.
+func (auth *Authenticator) Authenticate(tokenString string) (*Identity, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return []byte(auth.config.HMACSecret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	identity := &Identity{permissions: map[Permission]bool{}}
+	identity.Subject, _ = claims["sub"].(string)
+	if roles, ok := claims[auth.config.RolesClaim].([]interface{}); ok {
+		for _, r := range roles {
+			role, ok := r.(string)
+			if !ok {
+				continue
+			}
+			identity.Roles = append(identity.Roles, role)
+			for _, permission := range auth.config.RolePermissions[role] {
+				identity.permissions[permission] = true
+			}
+		}
+	}
+	return identity, nil
+}
+
+// AuthorizeToken validates the bearer token and checks that it grants
+// all the required permissions.
+func (auth *Authenticator) AuthorizeToken(tokenString string, required ...Permission) (*Identity, error) {
+	identity, err := auth.Authenticate(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range required {
+		if !identity.Allowed(permission) {
+			return nil, fmt.Errorf("missing permission '%s'", permission)
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/onsi/gomega"
+)
+
+const testSecret = "test-secret"
+
+func testConfig() *Config {
+	return &Config{
+		HMACSecret: testSecret,
+		RolePermissions: map[string][]Permission{
+			"operator": {"read"},
+			"admin":    {"read", "write"},
+		},
+	}
+}
+
+func signedToken(roles ...string) string {
+	claims := jwt.MapClaims{"sub": "tester"}
+	roleClaims := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		roleClaims = append(roleClaims, role)
+	}
+	claims["roles"] = roleClaims
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+func TestAuthorizeToken(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	auth, err := NewAuthenticator(testConfig())
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// the operator role grants read but not write
+	identity, err := auth.AuthorizeToken(signedToken("operator"), "read")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(identity.Subject).To(gomega.Equal("tester"))
+	_, err = auth.AuthorizeToken(signedToken("operator"), "write")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// the admin role grants both
+	_, err = auth.AuthorizeToken(signedToken("admin"), "read", "write")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// a token signed with another secret is refused
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{}).
+		SignedString([]byte("other-secret"))
+	gomega.Expect(err).To(gomega.BeNil())
+	_, err = auth.AuthorizeToken(forged, "read")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	auth, err := NewAuthenticator(testConfig())
+	gomega.Expect(err).To(gomega.BeNil())
+
+	handler := auth.HTTPMiddleware("write")(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("done"))
+		}))
+
+	get := func(token string) int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/manage", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	gomega.Expect(get("")).To(gomega.Equal(http.StatusUnauthorized))
+	gomega.Expect(get(signedToken("operator"))).To(gomega.Equal(http.StatusForbidden))
+	gomega.Expect(get(signedToken("admin"))).To(gomega.Equal(http.StatusOK))
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	grpcplugin "github.com/ligato/cn-infra/rpc/grpc"
+	"google.golang.org/grpc"
+)
+
+// HTTPMiddleware protects the wrapped routes of the HTTP server: requests
+// without a bearer token granting all the required permissions are rejected
+// with 401 Unauthorized or 403 Forbidden. The returned function is usable
+// as a rest.Middleware.
+func (auth *Authenticator) HTTPMiddleware(required ...Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header := req.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			identity, err := auth.Authenticate(strings.TrimPrefix(header, "Bearer "))
+			if err != nil {
+				http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			for _, permission := range required {
+				if !identity.Allowed(permission) {
+					http.Error(w, "403 Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// UnaryInterceptor protects the unary calls of the gRPC server: calls
+// without a bearer token granting all the required permissions are
+// rejected with Unauthenticated.
+func (auth *Authenticator) UnaryInterceptor(required ...Permission) grpc.UnaryServerInterceptor {
+	return grpcplugin.UnaryAuthInterceptor(auth.validatorFor(required))
+}
+
+// StreamInterceptor protects the streams of the gRPC server: streams
+// without a bearer token granting all the required permissions are
+// rejected with Unauthenticated.
+func (auth *Authenticator) StreamInterceptor(required ...Permission) grpc.StreamServerInterceptor {
+	return grpcplugin.StreamAuthInterceptor(auth.validatorFor(required))
+}
+
+// validatorFor adapts the authenticator to the token validator expected
+// by the gRPC auth interceptors.
+func (auth *Authenticator) validatorFor(required []Permission) grpcplugin.TokenValidator {
+	return func(token string) error {
+		_, err := auth.AuthorizeToken(token, required...)
+		return err
+	}
+}
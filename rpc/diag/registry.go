@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ligato/cn-infra/idxmap"
+)
+
+// CacheDump produces a JSON-marshallable snapshot of one internal
+// cache. It is called on every dump request, so it must be safe for
+// concurrent use with the owner of the cache.
+type CacheDump func() (interface{}, error)
+
+// Registry holds the named dump callbacks of the caches of one agent.
+type Registry struct {
+	mu      sync.RWMutex
+	dumpers map[string]CacheDump
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{dumpers: map[string]CacheDump{}}
+}
+
+// RegisterCache registers the dump callback under the name.
+func (registry *Registry) RegisterCache(name string, dump CacheDump) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, taken := registry.dumpers[name]; taken {
+		return fmt.Errorf("the cache name %q is already taken", name)
+	}
+	registry.dumpers[name] = dump
+	return nil
+}
+
+// RegisterIdxMap registers a dump of the mapping: entry names with
+// their metadata. The name of the cache defaults to the registry title
+// of the mapping.
+func (registry *Registry) RegisterIdxMap(name string, mapping idxmap.NamedMapping) error {
+	if name == "" {
+		name = mapping.GetRegistryTitle()
+	}
+	return registry.RegisterCache(name, func() (interface{}, error) {
+		dump := map[string]interface{}{}
+		for _, entryName := range mapping.ListAllNames() {
+			metadata, _ := mapping.GetValue(entryName)
+			dump[entryName] = metadata
+		}
+		return dump, nil
+	})
+}
+
+// ListCaches returns the registered cache names, sorted alphabetically.
+func (registry *Registry) ListCaches() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.dumpers))
+	for name := range registry.dumpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dump snapshots the named cache.
+func (registry *Registry) Dump(name string) (interface{}, error) {
+	registry.mu.RLock()
+	dump, found := registry.dumpers[name]
+	registry.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("no cache %q is registered", name)
+	}
+	return dump()
+}
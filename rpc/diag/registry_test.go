@@ -0,0 +1,108 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/idxmap/mem"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// ifMeta is a sample metadata of the dumped idxmap.
+type ifMeta struct {
+	SwIfIndex uint32 `json:"sw_if_index"`
+}
+
+// TestRegistryDump checks the registration and the dumping of the
+// caches.
+func TestRegistryDump(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	registry := NewRegistry()
+	gomega.Expect(registry.RegisterCache("pending-txns", func() (interface{}, error) {
+		return []string{"txn-1"}, nil
+	})).To(gomega.BeNil())
+
+	mapping := mem.NewNamedMapping(logrus.StandardLogger(), "sw_if_indexes", nil)
+	mapping.Put("memif0", &ifMeta{SwIfIndex: 1})
+	gomega.Expect(registry.RegisterIdxMap("", mapping)).To(gomega.BeNil())
+
+	gomega.Expect(registry.ListCaches()).To(gomega.Equal([]string{"pending-txns", "sw_if_indexes"}))
+
+	dump, err := registry.Dump("sw_if_indexes")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(dump).To(gomega.Equal(map[string]interface{}{"memif0": &ifMeta{SwIfIndex: 1}}))
+
+	_, err = registry.Dump("bogus")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+
+	// duplicate names are rejected
+	gomega.Expect(registry.RegisterCache("pending-txns", nil)).NotTo(gomega.BeNil())
+}
+
+// TestDiagHandlers checks the REST endpoints of the registry.
+func TestDiagHandlers(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	registry := NewRegistry()
+	gomega.Expect(registry.RegisterCache("pending-txns", func() (interface{}, error) {
+		return []string{"txn-1"}, nil
+	})).To(gomega.BeNil())
+	gomega.Expect(registry.RegisterCache("broken", func() (interface{}, error) {
+		return nil, errors.New("cache unavailable")
+	})).To(gomega.BeNil())
+
+	router := mux.NewRouter()
+	RegisterDiagHandlers(routerHandlers{router}, registry)
+
+	listResp := httptest.NewRecorder()
+	router.ServeHTTP(listResp, httptest.NewRequest("GET", CachesPath, nil))
+	gomega.Expect(listResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var names []string
+	gomega.Expect(json.Unmarshal(listResp.Body.Bytes(), &names)).To(gomega.BeNil())
+	gomega.Expect(names).To(gomega.Equal([]string{"broken", "pending-txns"}))
+
+	dumpResp := httptest.NewRecorder()
+	router.ServeHTTP(dumpResp, httptest.NewRequest("GET", "/dump/pending-txns", nil))
+	gomega.Expect(dumpResp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+	var txns []string
+	gomega.Expect(json.Unmarshal(dumpResp.Body.Bytes(), &txns)).To(gomega.BeNil())
+	gomega.Expect(txns).To(gomega.Equal([]string{"txn-1"}))
+
+	brokenResp := httptest.NewRecorder()
+	router.ServeHTTP(brokenResp, httptest.NewRequest("GET", "/dump/broken", nil))
+	gomega.Expect(brokenResp.Code).To(gomega.BeEquivalentTo(http.StatusNotFound))
+
+	missingResp := httptest.NewRecorder()
+	router.ServeHTTP(missingResp, httptest.NewRequest("GET", "/dump/bogus", nil))
+	gomega.Expect(missingResp.Code).To(gomega.BeEquivalentTo(http.StatusNotFound))
+}
+
+// routerHandlers adapts a plain mux router to the rest.HTTPHandlers
+// interface for the tests.
+type routerHandlers struct {
+	router *mux.Router
+}
+
+func (handlers routerHandlers) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	return handlers.router.Handle(path, handler).Methods(methods...)
+}
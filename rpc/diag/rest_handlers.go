@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diag
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Paths of the cache dump endpoints.
+const (
+	// CachesPath lists the registered cache names.
+	CachesPath = "/dump"
+	// CacheDumpPath dumps one named cache as JSON.
+	CacheDumpPath = "/dump/{name}"
+)
+
+// RegisterDiagHandlers exposes the registry over REST.
+func RegisterDiagHandlers(handlers rest.HTTPHandlers, registry *Registry) {
+	handlers.RegisterHTTPHandler(CachesPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(registry.ListCaches())
+		}), "GET")
+
+	handlers.RegisterHTTPHandler(CacheDumpPath, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			dump, err := registry.Dump(mux.Vars(req)["name"])
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			if err := json.NewEncoder(w).Encode(dump); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			}
+		}), "GET")
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the gRPC server.
+type Config struct {
+	// Endpoint is the address and port the server listens on.
+	Endpoint string `json:"endpoint"`
+	// Network is the network of the endpoint: "tcp" (the default)
+	// or "unix" with the endpoint holding the socket path.
+	Network string `json:"network"`
+	// SocketPermissions is the octal file mode (e.g. "0600") applied
+	// to the unix socket. Empty keeps the system default.
+	SocketPermissions string `json:"socket-permissions"`
+	// MaxMsgSize is the maximum size of a received message in bytes.
+	// Zero keeps the gRPC default.
+	MaxMsgSize int `json:"max-msg-size"`
+	// MaxConcurrentStreams is the limit of concurrent streams per
+	// HTTP/2 connection. Zero keeps the gRPC default.
+	MaxConcurrentStreams uint32 `json:"max-concurrent-streams"`
+	// HealthCheck enables the standard grpc_health_v1 service,
+	// so that gRPC health probes work out of the box.
+	HealthCheck bool `json:"health-check"`
+	// Reflection enables the gRPC server reflection service,
+	// so that tools like grpcurl can discover the registered services.
+	Reflection bool `json:"reflection"`
+}
+
+// defaultEndpoint is used when no endpoint is configured.
+const defaultEndpoint = "0.0.0.0:9111"
+
+// configFromFile loads the gRPC server configuration from the specified file.
+// Empty fpath falls back to the GRPC_ENDPOINT environment variable
+// and the default endpoint.
+func configFromFile(fpath string) (*Config, error) {
+	config := &Config{}
+	if fpath != "" {
+		b, err := ioutil.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		err = yaml.Unmarshal(b, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.Endpoint == "" {
+		if ep := os.Getenv("GRPC_ENDPOINT"); ep != "" {
+			config.Endpoint = ep
+		} else {
+			config.Endpoint = defaultEndpoint
+		}
+	}
+	return config, nil
+}
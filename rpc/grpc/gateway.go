@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// DefaultGatewayPrefix is the path prefix the REST facade of the gRPC
+// services is mounted on when no other prefix is configured.
+const DefaultGatewayPrefix = "/api/"
+
+// GatewayHandler registers the grpc-gateway handler of one gRPC service
+// on the serve mux, proxying to the given client connection. The generated
+// RegisterXHandler functions of the services have this signature.
+type GatewayHandler func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// Gateway serves a JSON REST facade of the registered gRPC services
+// on the shared HTTP server via grpc-gateway. It implements the
+// core.PostInit interface: the facade is mounted in AfterInit, when
+// the gRPC server is already listening.
+type Gateway struct {
+	log    logging.Logger
+	grpc   *Plugin
+	http   rest.HTTPHandlers
+	prefix string
+
+	handlers []GatewayHandler
+	conn     *grpc.ClientConn
+	cancel   context.CancelFunc
+}
+
+// NewGateway creates a new REST facade of the services registered on the
+// given gRPC plugin, mounted on DefaultGatewayPrefix of the HTTP server.
+func NewGateway(log logging.Logger, grpcPlugin *Plugin, http rest.HTTPHandlers) *Gateway {
+	return &Gateway{log: log, grpc: grpcPlugin, http: http, prefix: DefaultGatewayPrefix}
+}
+
+// RegisterService adds the grpc-gateway handler of one gRPC service
+// (a generated RegisterXHandler function) to the facade. It must be
+// called before AfterInit, i.e. before the facade is mounted.
+func (gw *Gateway) RegisterService(handler GatewayHandler) {
+	gw.handlers = append(gw.handlers, handler)
+}
+
+// Init is a NOOP, the facade is mounted in AfterInit.
+func (gw *Gateway) Init() error {
+	return nil
+}
+
+// AfterInit dials the local gRPC server and mounts the JSON REST facade
+// of the registered services on the HTTP server.
+func (gw *Gateway) AfterInit() error {
+	if len(gw.handlers) == 0 {
+		gw.log.Debug("No gRPC services registered on the gateway")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gw.cancel = cancel
+
+	conn, err := grpc.Dial(gw.grpc.GetAddress(), grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	gw.conn = conn
+
+	serveMux := runtime.NewServeMux()
+	for _, handler := range gw.handlers {
+		if err := handler(ctx, serveMux, conn); err != nil {
+			return err
+		}
+	}
+
+	// the service handlers register absolute paths on the serve mux,
+	// therefore the mount prefix is stripped from the requests
+	gw.http.RegisterHTTPHandler(gw.prefix+"{path:.*}",
+		http.StripPrefix(strings.TrimSuffix(gw.prefix, "/"), serveMux))
+	gw.log.Info("gRPC services exposed over REST on ", gw.prefix)
+	return nil
+}
+
+// Close closes the connection to the gRPC server.
+func (gw *Gateway) Close() error {
+	if gw.cancel != nil {
+		gw.cancel()
+	}
+	if gw.conn != nil {
+		return gw.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// HealthService is the API used to report the agent health through
+// the standard grpc_health_v1 service. It is intended to be driven
+// by the status-check subsystem.
+type HealthService interface {
+	// SetServing marks the overall agent (empty service name) as
+	// SERVING or NOT_SERVING for the gRPC health checks.
+	SetServing(serving bool)
+}
+
+// healthServer implements the grpc_health_v1 service. Unlike the stock
+// implementation, the serving status of the overall agent (empty service
+// name) is settable too, so that Kubernetes gRPC probes reflect the real
+// agent state.
+type healthServer struct {
+	mu sync.Mutex
+	// statusMap stores the serving status per service name; the empty
+	// name holds the overall agent status.
+	statusMap map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+func newHealthServer() *healthServer {
+	return &healthServer{
+		statusMap: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+			"": grpc_health_v1.HealthCheckResponse_SERVING,
+		},
+	}
+}
+
+// Check implements `service Health`.
+func (s *healthServer) Check(ctx context.Context, in *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status, ok := s.statusMap[in.Service]; ok {
+		return &grpc_health_v1.HealthCheckResponse{Status: status}, nil
+	}
+	return nil, grpc.Errorf(codes.NotFound, "unknown service")
+}
+
+// setServingStatus resets the serving status of a service or inserts
+// a new service entry into the statusMap.
+func (s *healthServer) setServingStatus(service string, status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	s.statusMap[service] = status
+	s.mu.Unlock()
+}
+
+// registerStandardServices registers the grpc_health_v1 and the reflection
+// services on the server, as enabled by the configuration.
+func (plugin *Plugin) registerStandardServices() {
+	if plugin.config.HealthCheck {
+		plugin.health = newHealthServer()
+		grpc_health_v1.RegisterHealthServer(plugin.server, plugin.health)
+	}
+	if plugin.config.Reflection {
+		reflection.Register(plugin.server)
+	}
+}
+
+// SetServing marks the overall agent (empty service name) as SERVING
+// or NOT_SERVING for the gRPC health checks. It is a no-op when the
+// health service is disabled in the configuration.
+func (plugin *Plugin) SetServing(serving bool) {
+	if plugin.health == nil {
+		return
+	}
+	status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	plugin.health.setServingStatus("", status)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthCheckService(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	configFile, err := ioutil.TempFile("", "grpc-config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString("endpoint: 127.0.0.1:0\nhealth-check: true\n")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(configFile.Close()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), configFile.Name())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	gomega.Expect(plugin.AfterInit()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	conn, err := grpc.Dial(plugin.GetAddress(), grpc.WithInsecure(), grpc.WithBlock())
+	gomega.Expect(err).To(gomega.BeNil())
+	defer conn.Close()
+
+	client := grpc_health_v1.NewHealthClient(conn)
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(resp.Status).To(gomega.Equal(grpc_health_v1.HealthCheckResponse_SERVING))
+
+	plugin.SetServing(false)
+	resp, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(resp.Status).To(gomega.Equal(grpc_health_v1.HealthCheckResponse_NOT_SERVING))
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/ligato/cn-infra/logging"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// UseUnaryInterceptors appends unary server interceptors applied to all
+// services of the server, in the order of registration. It must be called
+// before Init, i.e. before the server is created.
+func (plugin *Plugin) UseUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) {
+	plugin.unaryInterceptors = append(plugin.unaryInterceptors, interceptors...)
+}
+
+// UseStreamInterceptors appends stream server interceptors applied to all
+// services of the server, in the order of registration. It must be called
+// before Init, i.e. before the server is created.
+func (plugin *Plugin) UseStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) {
+	plugin.streamInterceptors = append(plugin.streamInterceptors, interceptors...)
+}
+
+// ChainUnaryInterceptors combines multiple unary server interceptors
+// into one, invoking them in order.
+func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next := chained
+			interceptor := interceptors[i]
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// ChainStreamInterceptors combines multiple stream server interceptors
+// into one, invoking them in order.
+func ChainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next := chained
+			interceptor := interceptors[i]
+			chained = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return chained(srv, ss)
+	}
+}
+
+// UnaryLoggingInterceptor logs the method, the duration and the result
+// of every unary call with the given logger.
+func UnaryLoggingInterceptor(log logging.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.WithFields(logging.Fields{
+			"method":   info.FullMethod,
+			"duration": time.Since(start),
+			"error":    err,
+		}).Debug("gRPC unary call")
+		return resp, err
+	}
+}
+
+// StreamLoggingInterceptor logs the method, the duration and the result
+// of every stream with the given logger.
+func StreamLoggingInterceptor(log logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		log.WithFields(logging.Fields{
+			"method":   info.FullMethod,
+			"duration": time.Since(start),
+			"error":    err,
+		}).Debug("gRPC stream")
+		return err
+	}
+}
+
+// UnaryPrometheusInterceptor collects Prometheus metrics (counters and
+// optional latency histograms) of the unary calls.
+func UnaryPrometheusInterceptor() grpc.UnaryServerInterceptor {
+	return grpc_prometheus.UnaryServerInterceptor
+}
+
+// StreamPrometheusInterceptor collects Prometheus metrics (counters and
+// optional latency histograms) of the streams.
+func StreamPrometheusInterceptor() grpc.StreamServerInterceptor {
+	return grpc_prometheus.StreamServerInterceptor
+}
+
+// TokenValidator checks the bearer token extracted from the request
+// metadata. A non-nil error rejects the call with Unauthenticated.
+type TokenValidator func(token string) error
+
+// UnaryAuthInterceptor rejects unary calls whose bearer token is missing
+// or refused by the validator.
+func UnaryAuthInterceptor(validator TokenValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validateToken(ctx, validator); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor rejects streams whose bearer token is missing
+// or refused by the validator.
+func StreamAuthInterceptor(validator TokenValidator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := validateToken(ss.Context(), validator); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// validateToken extracts the bearer token from the "authorization"
+// metadata of the call and passes it to the validator.
+func validateToken(ctx context.Context, validator TokenValidator) error {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return grpc.Errorf(codes.Unauthenticated, "missing metadata")
+	}
+	auth := md["authorization"]
+	if len(auth) == 0 {
+		return grpc.Errorf(codes.Unauthenticated, "missing authorization token")
+	}
+	token := strings.TrimPrefix(auth[0], "Bearer ")
+	if err := validator(token); err != nil {
+		return grpc.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	return nil
+}
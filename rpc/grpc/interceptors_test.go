@@ -0,0 +1,45 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var order []string
+	named := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			order = append(order, name)
+			return handler(ctx, req)
+		}
+	}
+	chained := ChainUnaryInterceptors(named("first"), named("second"), named("third"))
+
+	resp, err := chained(context.Background(), "request", &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			order = append(order, "handler")
+			return "response", nil
+		})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(resp).To(gomega.Equal("response"))
+	gomega.Expect(order).To(gomega.Equal([]string{"first", "second", "third", "handler"}))
+}
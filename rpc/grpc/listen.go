@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listen creates the listener of the server. For unix sockets, a stale
+// socket file is removed first and the configured permissions are applied
+// to the new one.
+func listen(config *Config) (net.Listener, error) {
+	if config.Network != "unix" {
+		return net.Listen("tcp", config.Endpoint)
+	}
+	if err := os.Remove(config.Endpoint); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if config.SocketPermissions != "" {
+		mode, err := strconv.ParseUint(config.SocketPermissions, 8, 32)
+		if err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("invalid socket permissions '%s': %v", config.SocketPermissions, err)
+		}
+		if err := os.Chmod(config.Endpoint, os.FileMode(mode)); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+	return listener, nil
+}
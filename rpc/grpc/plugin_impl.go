@@ -0,0 +1,122 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"net"
+
+	"github.com/ligato/cn-infra/logging"
+	"google.golang.org/grpc"
+)
+
+// Server is the API of the plugin used by other plugins to register
+// their gRPC services on the shared server.
+type Server interface {
+	// GetServer returns the underlying gRPC server, to be used
+	// for service registration during Init.
+	GetServer() *grpc.Server
+}
+
+// Plugin implements the shared gRPC server of the agent. It implements
+// the core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+
+	server   *grpc.Server
+	health   *healthServer
+	listener net.Listener
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration
+// of the gRPC server is loaded from the file during Init.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile}
+}
+
+// Init loads the configuration and creates the gRPC server. Other plugins
+// can register their services from this point on.
+func (plugin *Plugin) Init() error {
+	config, err := configFromFile(plugin.configFile)
+	if err != nil {
+		return err
+	}
+	plugin.config = config
+
+	var opts []grpc.ServerOption
+	if config.MaxMsgSize > 0 {
+		opts = append(opts, grpc.MaxMsgSize(config.MaxMsgSize))
+	}
+	if config.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(config.MaxConcurrentStreams))
+	}
+	if len(plugin.unaryInterceptors) > 0 {
+		opts = append(opts, grpc.UnaryInterceptor(ChainUnaryInterceptors(plugin.unaryInterceptors...)))
+	}
+	if len(plugin.streamInterceptors) > 0 {
+		opts = append(opts, grpc.StreamInterceptor(ChainStreamInterceptors(plugin.streamInterceptors...)))
+	}
+	plugin.server = grpc.NewServer(opts...)
+	plugin.registerStandardServices()
+	return nil
+}
+
+// AfterInit starts the gRPC server. It runs after the Init of all plugins,
+// so that all services are already registered.
+func (plugin *Plugin) AfterInit() error {
+	listener, err := listen(plugin.config)
+	if err != nil {
+		plugin.log.Errorf("Failed to listen on %s, Error: '%s'", plugin.config.Endpoint, err)
+		return err
+	}
+	plugin.listener = listener
+
+	go func() {
+		serveErr := plugin.server.Serve(listener)
+		if serveErr != nil {
+			plugin.log.Error("gRPC server error: ", serveErr)
+		}
+	}()
+	plugin.log.Info("gRPC server is listening on ", plugin.GetAddress())
+	return nil
+}
+
+// Close gracefully stops the gRPC server, waiting for the pending RPCs
+// to complete.
+func (plugin *Plugin) Close() error {
+	if plugin.server != nil {
+		plugin.server.GracefulStop()
+	}
+	return nil
+}
+
+// GetServer returns the underlying gRPC server, to be used for service
+// registration during Init.
+func (plugin *Plugin) GetServer() *grpc.Server {
+	return plugin.server
+}
+
+// GetAddress returns the address the server is listening on. It differs
+// from the configured endpoint when port 0 was configured.
+func (plugin *Plugin) GetAddress() string {
+	if plugin.listener == nil {
+		return plugin.config.Endpoint
+	}
+	return plugin.listener.Addr().String()
+}
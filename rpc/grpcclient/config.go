@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcclient
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the named connections of the pool.
+type Config struct {
+	// Connections maps the connection names the other plugins ask for
+	// to their settings.
+	Connections map[string]*ConnectionConfig `json:"connections"`
+}
+
+// ConnectionConfig holds the settings of one downstream connection.
+type ConnectionConfig struct {
+	// Endpoint is the address and port of the downstream service.
+	Endpoint string `json:"endpoint"`
+	// TLS configures the encryption of the connection; plaintext when
+	// nil or disabled.
+	TLS *TLSConfig `json:"tls"`
+	// KeepalivePeriod is the interval of the HTTP/2 keepalive pings.
+	// Zero keeps the gRPC default (no pings).
+	KeepalivePeriod time.Duration `json:"keepalive-period"`
+	// KeepaliveTimeout is how long an unacknowledged ping is waited
+	// for before the connection is torn down and redialed.
+	KeepaliveTimeout time.Duration `json:"keepalive-timeout"`
+	// BackoffMaxDelay caps the delay between the reconnection
+	// attempts. Zero keeps the gRPC default.
+	BackoffMaxDelay time.Duration `json:"backoff-max-delay"`
+}
+
+// TLSConfig configures the encryption of one connection.
+type TLSConfig struct {
+	Enabled               bool   `json:"enabled"`
+	Certfile              string `json:"cert-file"`
+	Keyfile               string `json:"key-file"`
+	CAfile                string `json:"ca-file"`
+	ServerName            string `json:"server-name"`
+	InsecureSkipTLSVerify bool   `json:"insecure-skip-tls-verify"`
+}
+
+// ConfigFromFile loads the connection pool configuration from the
+// specified file. If the file is valid and contains valid
+// configuration, the parsed configuration is returned; otherwise, an
+// error is returned.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
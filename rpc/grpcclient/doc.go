@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcclient implements a pool of named gRPC client connections
+// to downstream services. The connections are described in one config
+// file (address, TLS, keepalive, backoff) and handed out to other
+// plugins by name, so each plugin stops hand-managing dialing and
+// reconnection. The connections are dialed lazily and reused.
+package grpcclient
@@ -0,0 +1,179 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/utils/tlsreload"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ConnectionProvider is the API of the plugin used by other plugins to
+// obtain their downstream connections by name.
+type ConnectionProvider interface {
+	// GetConnection returns the named connection, dialing it on the
+	// first request. The returned connection is shared, do not close it.
+	GetConnection(name string) (*grpc.ClientConn, error)
+}
+
+// Plugin manages the pool of the named gRPC client connections. It
+// implements the core.Plugin interface, therefore it can be loaded with
+// other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration of
+// the connections is loaded from the file during Init.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile, conns: map[string]*grpc.ClientConn{}}
+}
+
+// NewPluginUsingConfig creates a new instance of the plugin with the
+// given configuration.
+func NewPluginUsingConfig(log logging.Logger, config *Config) *Plugin {
+	return &Plugin{log: log, config: config, conns: map[string]*grpc.ClientConn{}}
+}
+
+// Init loads and validates the configuration. The connections are
+// dialed lazily, on the first GetConnection call.
+func (plugin *Plugin) Init() error {
+	if plugin.config == nil {
+		config, err := ConfigFromFile(plugin.configFile)
+		if err != nil {
+			return err
+		}
+		plugin.config = config
+	}
+	for name, connConfig := range plugin.config.Connections {
+		if connConfig == nil || connConfig.Endpoint == "" {
+			return fmt.Errorf("the connection %q has no endpoint configured", name)
+		}
+	}
+	return nil
+}
+
+// Close closes all the dialed connections.
+func (plugin *Plugin) Close() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	var firstErr error
+	for name, conn := range plugin.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(plugin.conns, name)
+	}
+	return firstErr
+}
+
+// GetConnection returns the named connection, dialing it on the first
+// request. The returned connection is shared by all the plugins asking
+// for the name; gRPC itself keeps it alive and redials it in the
+// background with the configured keepalive and backoff.
+func (plugin *Plugin) GetConnection(name string) (*grpc.ClientConn, error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if conn, found := plugin.conns[name]; found {
+		return conn, nil
+	}
+	connConfig, found := plugin.config.Connections[name]
+	if !found {
+		return nil, fmt.Errorf("no connection named %q is configured", name)
+	}
+	opts, err := dialOptions(plugin.log, connConfig)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(connConfig.Endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	plugin.conns[name] = conn
+	plugin.log.WithFields(logging.Fields{
+		"name":     name,
+		"endpoint": connConfig.Endpoint,
+	}).Debug("Dialed gRPC connection")
+	return conn, nil
+}
+
+// dialOptions translates the connection settings into gRPC dial options.
+func dialOptions(log logging.Logger, config *ConnectionConfig) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if config.TLS != nil && config.TLS.Enabled {
+		tlscfg, err := newTLSConfig(log, config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlscfg)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if config.KeepalivePeriod > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                config.KeepalivePeriod,
+			Timeout:             config.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	if config.BackoffMaxDelay > 0 {
+		opts = append(opts, grpc.WithBackoffMaxDelay(config.BackoffMaxDelay))
+	}
+	return opts, nil
+}
+
+// newTLSConfig builds the tls.Config of one connection. The client
+// certificate is transparently reloaded when the files are rotated.
+func newTLSConfig(log logging.Logger, config *TLSConfig) (*tls.Config, error) {
+	tlscfg := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipTLSVerify,
+	}
+	if config.CAfile != "" {
+		pem, err := ioutil.ReadFile(config.CAfile)
+		if err != nil {
+			return nil, err
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to add CA certificate from '%s'", config.CAfile)
+		}
+		tlscfg.RootCAs = caPool
+	}
+	if config.Certfile != "" && config.Keyfile != "" {
+		keyPair, err := tlsreload.NewKeyPair(log, config.Certfile, config.Keyfile)
+		if err != nil {
+			return nil, err
+		}
+		tlscfg.GetClientCertificate = keyPair.GetClientCertificate
+	}
+	return tlscfg, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// newTestPlugin creates an initialized plugin with one configured
+// connection.
+func newTestPlugin(t *testing.T) *Plugin {
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), &Config{
+		Connections: map[string]*ConnectionConfig{
+			"telemetry": {
+				Endpoint:         "127.0.0.1:9111",
+				KeepalivePeriod:  10 * time.Second,
+				KeepaliveTimeout: 5 * time.Second,
+				BackoffMaxDelay:  time.Minute,
+			},
+		},
+	})
+	if err := plugin.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return plugin
+}
+
+// TestGetConnection checks that the connection is dialed lazily and
+// shared between the callers.
+func TestGetConnection(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	plugin := newTestPlugin(t)
+	defer plugin.Close()
+
+	conn, err := plugin.GetConnection("telemetry")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(conn).NotTo(gomega.BeNil())
+
+	again, err := plugin.GetConnection("telemetry")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(again).To(gomega.BeIdenticalTo(conn))
+}
+
+// TestGetConnectionUnknown checks that asking for a connection that is
+// not configured fails.
+func TestGetConnectionUnknown(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	plugin := newTestPlugin(t)
+	defer plugin.Close()
+
+	_, err := plugin.GetConnection("unknown")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+// TestInitValidation checks that a connection without an endpoint is
+// rejected during Init.
+func TestInitValidation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), &Config{
+		Connections: map[string]*ConnectionConfig{"broken": {}},
+	})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
+
+// TestTLSConfigFailure checks that a broken TLS configuration surfaces
+// when the connection is requested.
+func TestTLSConfigFailure(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPluginUsingConfig(logrus.StandardLogger(), &Config{
+		Connections: map[string]*ConnectionConfig{
+			"secured": {
+				Endpoint: "127.0.0.1:9111",
+				TLS:      &TLSConfig{Enabled: true, CAfile: "/does/not/exist.pem"},
+			},
+		},
+	})
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	_, err := plugin.GetConnection("secured")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pprof
+
+import (
+	"io/ioutil"
+	"net/http"
+	netpprof "net/http/pprof"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// DefaultPath is the path prefix the profiling handlers are mounted on
+// when no other path is configured.
+const DefaultPath = "/debug/pprof"
+
+// Config holds the settings of the profiling endpoints.
+type Config struct {
+	// Path is the path prefix the profiling handlers are mounted on.
+	Path string `json:"path"`
+	// BasicAuth is the list of permitted "user:password" pairs. When
+	// non-empty, profiling requests without valid basic-auth credentials
+	// are rejected with 401 Unauthorized.
+	BasicAuth []string `json:"basic-auth"`
+}
+
+// Plugin mounts the net/http/pprof handlers on the shared HTTP server.
+// It implements the core.Plugin interface, therefore it can be loaded
+// with other plugins.
+type Plugin struct {
+	log        logging.Logger
+	http       rest.HTTPHandlers
+	config     *Config
+	configFile string
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration of
+// the profiling endpoints is loaded from the file during Init; an empty
+// file name keeps the defaults.
+func NewPlugin(log logging.Logger, http rest.HTTPHandlers, configFile string) *Plugin {
+	return &Plugin{log: log, http: http, configFile: configFile}
+}
+
+// Init loads the configuration and mounts the profiling handlers.
+func (plugin *Plugin) Init() error {
+	config := &Config{}
+	if plugin.configFile != "" {
+		b, err := ioutil.ReadFile(plugin.configFile)
+		if err != nil {
+			return err
+		}
+		err = yaml.Unmarshal(b, config)
+		if err != nil {
+			return err
+		}
+	}
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	plugin.config = config
+
+	var middleware []rest.Middleware
+	if len(config.BasicAuth) > 0 {
+		middleware = append(middleware, rest.BasicAuthMiddleware(config.BasicAuth))
+	}
+	register := func(path string, handler http.HandlerFunc) {
+		plugin.http.RegisterHTTPHandler(config.Path+path,
+			rest.WrapMiddleware(handler, middleware...), "GET", "POST")
+	}
+	register("/", netpprof.Index)
+	register("/cmdline", netpprof.Cmdline)
+	register("/profile", netpprof.Profile)
+	register("/symbol", netpprof.Symbol)
+	register("/trace", netpprof.Trace)
+	register("/{profile}", plugin.namedProfile)
+
+	plugin.log.Debug("Profiling handlers mounted on ", config.Path)
+	return nil
+}
+
+// Close is a NOOP, the handlers do not hold any resources to be released.
+func (plugin *Plugin) Close() error {
+	return nil
+}
+
+// namedProfile serves the named profiles (heap, goroutine, block, ...).
+// The standard index handler derives the profile name from the URL path,
+// expecting the default prefix, therefore the path is rewritten before
+// delegating to it.
+func (plugin *Plugin) namedProfile(w http.ResponseWriter, req *http.Request) {
+	if plugin.config.Path != DefaultPath {
+		name := req.URL.Path[len(plugin.config.Path):]
+		req.URL.Path = DefaultPath + name
+	}
+	netpprof.Index(w, req)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/rpc/rest"
+)
+
+// Default paths the probes are served on.
+const (
+	DefaultLivenessPath  = "/liveness"
+	DefaultReadinessPath = "/readiness"
+	DefaultStartupPath   = "/startup"
+)
+
+// StatusProvider reports the overall health of the agent. It is intended
+// to be implemented by the status-check subsystem: a non-nil error means
+// that at least one plugin is not OK.
+type StatusProvider interface {
+	// Status returns nil when all plugins of the agent are OK.
+	Status() error
+}
+
+// Config holds the settings of the probes.
+type Config struct {
+	// GracePeriod is the duration for which readiness failures are
+	// tolerated once the agent has been ready, to ride out transient
+	// plugin errors without being restarted. Zero reports failures
+	// immediately.
+	GracePeriod time.Duration `json:"grace-period"`
+}
+
+// probeResponse is the JSON body of the probe responses.
+type probeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Plugin exposes the liveness, readiness and startup probes on the shared
+// HTTP server. It implements the core.Plugin interface, therefore it can
+// be loaded with other plugins.
+type Plugin struct {
+	log      logging.Logger
+	http     rest.HTTPHandlers
+	provider StatusProvider
+	config   *Config
+
+	mu        sync.Mutex
+	everReady bool
+	lastReady time.Time
+}
+
+// NewPlugin creates a new instance of the plugin computing the probes
+// from the given status provider. A nil config keeps the defaults.
+func NewPlugin(log logging.Logger, http rest.HTTPHandlers, provider StatusProvider, config *Config) *Plugin {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Plugin{log: log, http: http, provider: provider, config: config}
+}
+
+// Init registers the probe endpoints on the HTTP server.
+func (plugin *Plugin) Init() error {
+	plugin.http.RegisterHTTPHandler(DefaultLivenessPath,
+		http.HandlerFunc(plugin.livenessHandler), "GET")
+	plugin.http.RegisterHTTPHandler(DefaultReadinessPath,
+		http.HandlerFunc(plugin.readinessHandler), "GET")
+	plugin.http.RegisterHTTPHandler(DefaultStartupPath,
+		http.HandlerFunc(plugin.startupHandler), "GET")
+	return nil
+}
+
+// Close is a NOOP, the probes do not hold any resources to be released.
+func (plugin *Plugin) Close() error {
+	return nil
+}
+
+// livenessHandler reports whether the agent process is able to serve
+// requests at all; it succeeds as long as the HTTP server is up.
+func (plugin *Plugin) livenessHandler(w http.ResponseWriter, req *http.Request) {
+	writeProbeResponse(w, nil)
+}
+
+// readinessHandler reports whether all plugins of the agent are OK.
+// Failures within the grace period after the last OK status are tolerated.
+func (plugin *Plugin) readinessHandler(w http.ResponseWriter, req *http.Request) {
+	err := plugin.provider.Status()
+
+	plugin.mu.Lock()
+	now := time.Now()
+	if err == nil {
+		plugin.everReady = true
+		plugin.lastReady = now
+	} else if plugin.everReady && now.Sub(plugin.lastReady) < plugin.config.GracePeriod {
+		plugin.log.Debug("Tolerating readiness failure within the grace period: ", err)
+		err = nil
+	}
+	plugin.mu.Unlock()
+
+	writeProbeResponse(w, err)
+}
+
+// startupHandler reports whether the agent has become ready at least once
+// since it was started.
+func (plugin *Plugin) startupHandler(w http.ResponseWriter, req *http.Request) {
+	err := plugin.provider.Status()
+
+	plugin.mu.Lock()
+	if err == nil {
+		plugin.everReady = true
+		plugin.lastReady = time.Now()
+	} else if plugin.everReady {
+		err = nil
+	}
+	plugin.mu.Unlock()
+
+	writeProbeResponse(w, err)
+}
+
+// writeProbeResponse writes the probe result as JSON, with 200 OK for
+// success and 503 Service Unavailable for failure.
+func writeProbeResponse(w http.ResponseWriter, err error) {
+	resp := probeResponse{Status: "OK"}
+	code := http.StatusOK
+	if err != nil {
+		resp = probeResponse{Status: "NOT_READY", Error: err.Error()}
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
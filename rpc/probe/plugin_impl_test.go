@@ -0,0 +1,75 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"github.com/onsi/gomega"
+)
+
+// fakeProvider implements StatusProvider for the tests.
+type fakeProvider struct {
+	err error
+}
+
+func (provider *fakeProvider) Status() error {
+	return provider.err
+}
+
+func TestProbes(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	httpPlugin := rest.NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(httpPlugin.Init()).To(gomega.BeNil())
+
+	provider := &fakeProvider{err: errors.New("plugin not ready")}
+	plugin := NewPlugin(logrus.StandardLogger(), httpPlugin, provider, nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Expect(httpPlugin.AfterInit()).To(gomega.BeNil())
+	defer httpPlugin.Close()
+
+	get := func(path string) int {
+		resp, err := http.Get("http://" + httpPlugin.GetAddress() + path)
+		gomega.Expect(err).To(gomega.BeNil())
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the process is alive but not ready yet
+	gomega.Expect(get(DefaultLivenessPath)).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(get(DefaultReadinessPath)).To(gomega.Equal(http.StatusServiceUnavailable))
+	gomega.Expect(get(DefaultStartupPath)).To(gomega.Equal(http.StatusServiceUnavailable))
+
+	// all plugins became OK
+	provider.err = nil
+	gomega.Expect(get(DefaultReadinessPath)).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(get(DefaultStartupPath)).To(gomega.Equal(http.StatusOK))
+
+	// the startup probe keeps succeeding after a later failure
+	provider.err = errors.New("transient failure")
+	gomega.Expect(get(DefaultStartupPath)).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(get(DefaultReadinessPath)).To(gomega.Equal(http.StatusServiceUnavailable))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the metrics plugin.
+type Config struct {
+	// Path of the metrics endpoint. It defaults to DefaultMetricsPath.
+	Path string `json:"path"`
+	// StatsD enables pushing of the registered metrics to a StatsD
+	// endpoint, for environments without a Prometheus server. The
+	// pull endpoint stays available.
+	StatsD *StatsDConfig `json:"statsd"`
+}
+
+// StatsDConfig holds the settings of the StatsD push sink.
+type StatsDConfig struct {
+	// Address is the UDP address of the StatsD endpoint.
+	Address string `json:"address"`
+	// PushPeriod is the interval between the pushes. It defaults to
+	// ten seconds.
+	PushPeriod time.Duration `json:"push-period"`
+	// Prefix is prepended to every pushed metric name.
+	Prefix string `json:"prefix"`
+	// DogStatsD emits the metric labels as DogStatsD tags instead of
+	// flattening them into the metric name.
+	DogStatsD bool `json:"dogstatsd"`
+}
+
+// ConfigFromFile loads the metrics plugin configuration from the
+// specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"os"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultMetricsPath is the endpoint the metrics are served on when
+// no other path is configured.
+const DefaultMetricsPath = "/metrics"
+
+// API is the interface of the plugin used by other plugins to register
+// their collectors and create metrics on the shared registry.
+type API interface {
+	// RegisterCollector registers a custom collector (e.g. a metrics
+	// vector or a plugin-specific collector) on the shared registry.
+	RegisterCollector(collector prometheus.Collector) error
+	// NewGauge creates and registers a gauge with the given name.
+	NewGauge(name string, help string) (prometheus.Gauge, error)
+	// NewCounter creates and registers a counter with the given name.
+	NewCounter(name string, help string) (prometheus.Counter, error)
+}
+
+// Plugin owns the Prometheus metrics registry of the agent and serves it
+// on the /metrics endpoint of the shared HTTP server. It implements the
+// core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log    logging.Logger
+	http   rest.HTTPHandlers
+	path   string
+	config *Config
+
+	registry *prometheus.Registry
+	statsd   *statsdPusher
+}
+
+// NewPlugin creates a new instance of the plugin serving the metrics
+// on DefaultMetricsPath of the given HTTP server.
+func NewPlugin(log logging.Logger, http rest.HTTPHandlers) *Plugin {
+	return &Plugin{log: log, http: http, path: DefaultMetricsPath}
+}
+
+// NewPluginUsingConfig creates a new instance of the plugin with an
+// already loaded configuration, which may select a different endpoint
+// path and enable the StatsD push sink.
+func NewPluginUsingConfig(log logging.Logger, http rest.HTTPHandlers, config *Config) *Plugin {
+	path := DefaultMetricsPath
+	if config != nil && config.Path != "" {
+		path = config.Path
+	}
+	return &Plugin{log: log, http: http, path: path, config: config}
+}
+
+// Init creates the registry, registers the standard process and Go runtime
+// collectors and exposes the metrics endpoint. Other plugins can register
+// their collectors from this point on.
+func (plugin *Plugin) Init() error {
+	plugin.registry = prometheus.NewRegistry()
+	if err := plugin.registry.Register(prometheus.NewProcessCollector(os.Getpid(), "")); err != nil {
+		return err
+	}
+	if err := plugin.registry.Register(prometheus.NewGoCollector()); err != nil {
+		return err
+	}
+
+	plugin.http.RegisterHTTPHandler(plugin.path, promhttp.HandlerFor(
+		plugin.registry, promhttp.HandlerOpts{}), "GET")
+
+	if plugin.config != nil && plugin.config.StatsD != nil {
+		pusher, err := newStatsDPusher(plugin.log, plugin.registry, plugin.config.StatsD)
+		if err != nil {
+			return err
+		}
+		plugin.statsd = pusher
+		pusher.start()
+		plugin.log.WithField("address", plugin.config.StatsD.Address).
+			Info("StatsD metrics sink enabled")
+	}
+	return nil
+}
+
+// Close stops the StatsD sink if it is enabled. The registry itself does
+// not hold any resources to be released.
+func (plugin *Plugin) Close() error {
+	if plugin.statsd != nil {
+		return plugin.statsd.close()
+	}
+	return nil
+}
+
+// RegisterCollector registers a custom collector (e.g. a metrics vector
+// or a plugin-specific collector) on the shared registry.
+func (plugin *Plugin) RegisterCollector(collector prometheus.Collector) error {
+	return plugin.registry.Register(collector)
+}
+
+// NewGauge creates and registers a gauge with the given name.
+func (plugin *Plugin) NewGauge(name string, help string) (prometheus.Gauge, error) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+	if err := plugin.registry.Register(gauge); err != nil {
+		return nil, err
+	}
+	return gauge, nil
+}
+
+// NewCounter creates and registers a counter with the given name.
+func (plugin *Plugin) NewCounter(name string, help string) (prometheus.Counter, error) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: help})
+	if err := plugin.registry.Register(counter); err != nil {
+		return nil, err
+	}
+	return counter, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/cn-infra/rpc/rest"
+	"github.com/onsi/gomega"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	httpPlugin := rest.NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(httpPlugin.Init()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), httpPlugin)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	counter, err := plugin.NewCounter("test_counter", "a counter for the test")
+	gomega.Expect(err).To(gomega.BeNil())
+	counter.Add(3)
+
+	gomega.Expect(httpPlugin.AfterInit()).To(gomega.BeNil())
+	defer httpPlugin.Close()
+
+	resp, err := http.Get("http://" + httpPlugin.GetAddress() + DefaultMetricsPath)
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+	body, err := ioutil.ReadAll(resp.Body)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(strings.Contains(string(body), "test_counter 3")).To(gomega.BeTrue())
+}
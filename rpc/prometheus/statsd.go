@@ -0,0 +1,206 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// defaultPushPeriod is used when no push period is configured.
+const defaultPushPeriod = 10 * time.Second
+
+// statsdPusher periodically gathers the shared registry and pushes the
+// metrics as StatsD lines over UDP. Prometheus counters are cumulative
+// while StatsD counters are increments, therefore the pusher remembers
+// the last pushed values and sends the deltas.
+type statsdPusher struct {
+	log      logging.Logger
+	gatherer prometheus.Gatherer
+	config   *StatsDConfig
+	conn     net.Conn
+
+	mu         sync.Mutex
+	lastValues map[string]float64
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newStatsDPusher connects to the configured StatsD endpoint.
+func newStatsDPusher(log logging.Logger, gatherer prometheus.Gatherer, config *StatsDConfig) (*statsdPusher, error) {
+	conn, err := net.Dial("udp", config.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &statsdPusher{
+		log:        log,
+		gatherer:   gatherer,
+		config:     config,
+		conn:       conn,
+		lastValues: map[string]float64{},
+		closeChan:  make(chan struct{}),
+	}, nil
+}
+
+// start begins the periodic pushing.
+func (pusher *statsdPusher) start() {
+	pusher.wg.Add(1)
+	go pusher.periodicPush()
+}
+
+// close stops the pushing and releases the connection.
+func (pusher *statsdPusher) close() error {
+	close(pusher.closeChan)
+	pusher.wg.Wait()
+	return pusher.conn.Close()
+}
+
+// periodicPush pushes the metrics each period.
+func (pusher *statsdPusher) periodicPush() {
+	defer pusher.wg.Done()
+
+	period := pusher.config.PushPeriod
+	if period <= 0 {
+		period = defaultPushPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pusher.closeChan:
+			return
+		case <-ticker.C:
+			if err := pusher.push(); err != nil {
+				pusher.log.Warn("Failed to push the metrics to StatsD: ", err)
+			}
+		}
+	}
+}
+
+// push gathers the registry and sends one StatsD line per metric.
+func (pusher *statsdPusher) push() error {
+	families, err := pusher.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	pusher.mu.Lock()
+	defer pusher.mu.Unlock()
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, line := range pusher.renderMetric(family, metric) {
+				if _, err := pusher.conn.Write([]byte(line)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// renderMetric builds the StatsD lines of one metric. The caller must
+// hold the mutex.
+func (pusher *statsdPusher) renderMetric(family *dto.MetricFamily, metric *dto.Metric) []string {
+	name := sanitizeStatsD(pusher.config.Prefix + family.GetName())
+	suffix, tags := pusher.renderLabels(metric)
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		delta := pusher.counterDelta(name+suffix, metric.GetCounter().GetValue())
+		return []string{pusher.line(name+suffix, delta, "c", tags)}
+	case dto.MetricType_GAUGE:
+		return []string{pusher.line(name+suffix, metric.GetGauge().GetValue(), "g", tags)}
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		countDelta := pusher.counterDelta(name+suffix+".count", float64(histogram.GetSampleCount()))
+		sumDelta := pusher.counterDelta(name+suffix+".sum", histogram.GetSampleSum())
+		return []string{
+			pusher.line(name+suffix+".count", countDelta, "c", tags),
+			pusher.line(name+suffix+".sum", sumDelta, "c", tags),
+		}
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		countDelta := pusher.counterDelta(name+suffix+".count", float64(summary.GetSampleCount()))
+		sumDelta := pusher.counterDelta(name+suffix+".sum", summary.GetSampleSum())
+		return []string{
+			pusher.line(name+suffix+".count", countDelta, "c", tags),
+			pusher.line(name+suffix+".sum", sumDelta, "c", tags),
+		}
+	default:
+		return nil
+	}
+}
+
+// renderLabels renders the metric labels either as a DogStatsD tag set
+// or as a suffix flattened into the metric name.
+func (pusher *statsdPusher) renderLabels(metric *dto.Metric) (suffix string, tags string) {
+	labels := metric.GetLabel()
+	if len(labels) == 0 {
+		return "", ""
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].GetName() < labels[j].GetName()
+	})
+	if pusher.config.DogStatsD {
+		pairs := make([]string, 0, len(labels))
+		for _, label := range labels {
+			pairs = append(pairs, sanitizeStatsD(label.GetName())+":"+sanitizeStatsD(label.GetValue()))
+		}
+		return "", "|#" + strings.Join(pairs, ",")
+	}
+	var parts []string
+	for _, label := range labels {
+		parts = append(parts, sanitizeStatsD(label.GetName()), sanitizeStatsD(label.GetValue()))
+	}
+	return "." + strings.Join(parts, "."), ""
+}
+
+// counterDelta returns the increment of a cumulative value since the
+// last push. The caller must hold the mutex.
+func (pusher *statsdPusher) counterDelta(key string, value float64) float64 {
+	delta := value - pusher.lastValues[key]
+	if delta < 0 {
+		// the process restarted and the counter started over
+		delta = value
+	}
+	pusher.lastValues[key] = value
+	return delta
+}
+
+// line formats one StatsD line.
+func (pusher *statsdPusher) line(name string, value float64, kind string, tags string) string {
+	return fmt.Sprintf("%s:%g|%s%s", name, value, kind, tags)
+}
+
+// sanitizeStatsD replaces the characters reserved by the StatsD line
+// protocol.
+func sanitizeStatsD(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// receiveLines reads the StatsD lines delivered to the UDP listener
+// until no more arrive.
+func receiveLines(conn net.PacketConn) []string {
+	var lines []string
+	buffer := make([]byte, 1024)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return lines
+		}
+		lines = append(lines, string(buffer[:n]))
+	}
+}
+
+func TestStatsDPush(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer listener.Close()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "requests_total", Help: "h"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_depth", Help: "h"})
+	registry.MustRegister(counter, gauge)
+	counter.Add(5)
+	gauge.Set(3)
+
+	pusher, err := newStatsDPusher(logrus.StandardLogger(), registry,
+		&StatsDConfig{Address: listener.LocalAddr().String(), Prefix: "agent."})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer pusher.conn.Close()
+
+	gomega.Expect(pusher.push()).To(gomega.BeNil())
+	gomega.Expect(receiveLines(listener)).To(gomega.ConsistOf(
+		"agent.requests_total:5|c",
+		"agent.queue_depth:3|g",
+	))
+
+	// only the counter increment since the last push is sent
+	counter.Add(2)
+	gauge.Set(4)
+	gomega.Expect(pusher.push()).To(gomega.BeNil())
+	gomega.Expect(receiveLines(listener)).To(gomega.ConsistOf(
+		"agent.requests_total:2|c",
+		"agent.queue_depth:4|g",
+	))
+}
+
+func TestStatsDLabelRendering(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer listener.Close()
+
+	registry := prometheus.NewRegistry()
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "errors_total", Help: "h"},
+		[]string{"plugin"})
+	registry.MustRegister(vec)
+	vec.WithLabelValues("etcd").Add(1)
+
+	// plain StatsD flattens the labels into the metric name
+	pusher, err := newStatsDPusher(logrus.StandardLogger(), registry,
+		&StatsDConfig{Address: listener.LocalAddr().String()})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer pusher.conn.Close()
+	gomega.Expect(pusher.push()).To(gomega.BeNil())
+	gomega.Expect(receiveLines(listener)).To(gomega.ConsistOf("errors_total.plugin.etcd:1|c"))
+
+	// DogStatsD renders the labels as tags
+	dogPusher, err := newStatsDPusher(logrus.StandardLogger(), registry,
+		&StatsDConfig{Address: listener.LocalAddr().String(), DogStatsD: true})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer dogPusher.conn.Close()
+	gomega.Expect(dogPusher.push()).To(gomega.BeNil())
+	gomega.Expect(receiveLines(listener)).To(gomega.ConsistOf("errors_total:1|c|#plugin:etcd"))
+}
+
+func TestStatsDHistogram(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer listener.Close()
+
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Help: "h"})
+	registry.MustRegister(histogram)
+	histogram.Observe(0.5)
+	histogram.Observe(1.5)
+
+	pusher, err := newStatsDPusher(logrus.StandardLogger(), registry,
+		&StatsDConfig{Address: listener.LocalAddr().String()})
+	gomega.Expect(err).To(gomega.BeNil())
+	defer pusher.conn.Close()
+
+	gomega.Expect(pusher.push()).To(gomega.BeNil())
+	gomega.Expect(receiveLines(listener)).To(gomega.ConsistOf(
+		"latency_seconds.count:2|c",
+		"latency_seconds.sum:2|c",
+	))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/core"
+)
+
+// Paths of the plugin management endpoints.
+const (
+	PluginsPath       = "/plugins"
+	PluginRestartPath = "/plugins/{name}/restart"
+)
+
+// RegisterAgentHandlers exposes the plugin management endpoints of the
+// agent: listing of the loaded plugins and restart of a single plugin.
+func RegisterAgentHandlers(handlers HTTPHandlers, agent *core.Agent) {
+	handlers.RegisterHTTPHandler(PluginsPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			names := []string{}
+			for _, plug := range agent.Plugins() {
+				names = append(names, string(plug.PluginName))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(names)
+		}), "GET")
+
+	handlers.RegisterHTTPHandler(PluginRestartPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			name := core.PluginName(mux.Vars(r)["name"])
+			w.Header().Set("Content-Type", "application/json")
+			if err := agent.RestartPlugin(name); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"status": "restarted"})
+		}), "POST")
+}
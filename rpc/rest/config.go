@@ -0,0 +1,84 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config holds the settings of the HTTP server.
+type Config struct {
+	// Endpoint is the address and port the server listens on.
+	Endpoint string `json:"endpoint"`
+	// Network is the network of the endpoint: "tcp" (the default)
+	// or "unix" with the endpoint holding the socket path.
+	Network string `json:"network"`
+	// SocketPermissions is the octal file mode (e.g. "0600") applied
+	// to the unix socket. Empty keeps the system default.
+	SocketPermissions string `json:"socket-permissions"`
+	// ServerCertfile is the path of the server certificate. When both
+	// the certificate and the key are configured, the server serves HTTPS.
+	ServerCertfile string `json:"server-cert-file"`
+	// ServerKeyfile is the path of the private key of the server certificate.
+	ServerKeyfile string `json:"server-key-file"`
+	// ClientCerts are the paths of the CA certificates used to verify
+	// the client certificates. When non-empty, clients without a valid
+	// certificate are rejected.
+	ClientCerts []string `json:"client-cert-files"`
+	// ClientBasicAuth is the list of permitted "user:password" pairs.
+	// When non-empty, requests without valid basic-auth credentials
+	// are rejected with 401 Unauthorized.
+	ClientBasicAuth []string `json:"client-basic-auth"`
+	// CORS holds the cross-origin resource sharing settings. CORS
+	// headers are emitted only when allowed origins are configured.
+	CORS CORSConfig `json:"cors"`
+}
+
+// UseHTTPS tells whether the server certificate and key are configured,
+// i.e. whether the server serves HTTPS.
+func (config *Config) UseHTTPS() bool {
+	return config.ServerCertfile != "" && config.ServerKeyfile != ""
+}
+
+// defaultEndpoint is used when no endpoint is configured.
+const defaultEndpoint = "0.0.0.0:9191"
+
+// configFromFile loads the HTTP server configuration from the specified file.
+// Empty fpath falls back to the HTTP_ENDPOINT environment variable
+// and the default endpoint.
+func configFromFile(fpath string) (*Config, error) {
+	config := &Config{}
+	if fpath != "" {
+		b, err := ioutil.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		err = yaml.Unmarshal(b, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.Endpoint == "" {
+		if ep := os.Getenv("HTTP_ENDPOINT"); ep != "" {
+			config.Endpoint = ep
+		} else {
+			config.Endpoint = defaultEndpoint
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig holds the cross-origin resource sharing settings of the
+// HTTP server.
+type CORSConfig struct {
+	// AllowedOrigins is the list of origins permitted to make requests;
+	// "*" permits any origin. CORS headers are emitted only when the
+	// list is non-empty.
+	AllowedOrigins []string `json:"allowed-origins"`
+	// AllowedMethods is the list of methods advertised in the preflight
+	// responses.
+	AllowedMethods []string `json:"allowed-methods"`
+	// AllowedHeaders is the list of request headers advertised in the
+	// preflight responses.
+	AllowedHeaders []string `json:"allowed-headers"`
+	// AllowCredentials advertises that requests may carry credentials
+	// (cookies, authorization headers).
+	AllowCredentials bool `json:"allow-credentials"`
+	// MaxAge is the number of seconds the preflight response may be
+	// cached by the browser. Zero keeps the browser default.
+	MaxAge int `json:"max-age"`
+}
+
+// corsHandler emits the CORS headers for permitted origins and answers
+// the preflight OPTIONS requests before passing the other requests
+// to the next handler.
+func corsHandler(config CORSConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin != "" && originAllowed(config.AllowedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if req.Method == "OPTIONS" {
+				if len(config.AllowedMethods) > 0 {
+					w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+				}
+				if len(config.AllowedHeaders) > 0 {
+					w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+				}
+				if config.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+				}
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// originAllowed tells whether the origin is permitted by the configuration.
+func originAllowed(allowed []string, origin string) bool {
+	for _, a := range allowed {
+		if a == "*" || a == origin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCORSHandler(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	config := CORSConfig{
+		AllowedOrigins: []string{"http://dashboard.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+	}
+	handler := corsHandler(config, http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("ok"))
+		}))
+
+	// a request from a permitted origin gets the CORS headers
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/data", nil)
+	req.Header.Set("Origin", "http://dashboard.example.com")
+	handler.ServeHTTP(recorder, req)
+	gomega.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(recorder.Header().Get("Access-Control-Allow-Origin")).
+		To(gomega.Equal("http://dashboard.example.com"))
+
+	// the preflight request is answered without reaching the handler
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("OPTIONS", "/data", nil)
+	req.Header.Set("Origin", "http://dashboard.example.com")
+	handler.ServeHTTP(recorder, req)
+	gomega.Expect(recorder.Code).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(recorder.Header().Get("Access-Control-Allow-Methods")).
+		To(gomega.Equal("GET, POST"))
+	gomega.Expect(recorder.Body.String()).To(gomega.BeEmpty())
+
+	// a request from an unknown origin gets no CORS headers
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/data", nil)
+	req.Header.Set("Origin", "http://evil.example.com")
+	handler.ServeHTTP(recorder, req)
+	gomega.Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(gomega.BeEmpty())
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// Middleware wraps an HTTP handler with a cross-cutting concern, such as
+// request logging, authentication or panic recovery.
+type Middleware func(next http.Handler) http.Handler
+
+// Use appends middleware applied to all routes of the server, in the
+// order of registration. It must be called before AfterInit, i.e. before
+// the server is started.
+func (plugin *Plugin) Use(middleware ...Middleware) {
+	plugin.middleware = append(plugin.middleware, middleware...)
+}
+
+// WrapMiddleware wraps the handler with the given middleware, in order,
+// so that cross-cutting concerns can be applied to selected routes only.
+func WrapMiddleware(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// BasicAuthMiddleware rejects requests without valid basic-auth
+// credentials ("user:password" pairs) with 401 Unauthorized.
+func BasicAuthMiddleware(permitted []string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return authHandler(permitted, next)
+	}
+}
+
+// LoggingMiddleware logs the method, the URI and the remote address
+// of every request with the given logger.
+func LoggingMiddleware(log logging.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			log.Debugf("%s %s from %s", req.Method, req.RequestURI, req.RemoteAddr)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+// RecoveryMiddleware recovers from panics in the downstream handlers,
+// logs the panic with the stack trace and responds with 500 Internal
+// Server Error instead of closing the connection.
+func RecoveryMiddleware(log logging.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Errorf("Recovered from panic in %s: %v\n%s", req.RequestURI, r, debug.Stack())
+					http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/core"
+)
+
+// OpenAPIPath is the path the generated OpenAPI document is served on.
+const OpenAPIPath = "/openapi.json"
+
+// RouteDoc is the OpenAPI metadata of one registered route. Request and
+// Response are example values (typically zero structs) whose types
+// describe the JSON bodies; the schemas are derived from them by
+// reflection over the json tags.
+type RouteDoc struct {
+	// Summary is a one-line description of the operation.
+	Summary string
+	// Description explains the operation in more detail.
+	Description string
+	// Tags group the operations in the generated document.
+	Tags []string
+	// Request describes the JSON request body; nil for none.
+	Request interface{}
+	// Response describes the JSON body of the 200 response; nil for
+	// an unspecified body.
+	Response interface{}
+}
+
+// routeRecord is one registered route with its optional metadata.
+type routeRecord struct {
+	path    string
+	methods []string
+	doc     *RouteDoc
+}
+
+// RegisterDocumentedHandler registers the handler like
+// RegisterHTTPHandler and attaches the OpenAPI metadata of the route,
+// so it appears fully described in the generated document.
+func (plugin *Plugin) RegisterDocumentedHandler(path string, handler http.Handler, doc *RouteDoc, methods ...string) *mux.Route {
+	route := plugin.RegisterHTTPHandler(path, handler, methods...)
+	if len(plugin.routes) > 0 {
+		plugin.routes[len(plugin.routes)-1].doc = doc
+	}
+	return route
+}
+
+// openapiHandler serves the generated OpenAPI v3 document covering all
+// the registered routes.
+func (plugin *Plugin) openapiHandler(w http.ResponseWriter, req *http.Request) {
+	paths := map[string]map[string]interface{}{}
+	for _, record := range plugin.routes {
+		path := openapiPath(record.path)
+		operations, found := paths[path]
+		if !found {
+			operations = map[string]interface{}{}
+			paths[path] = operations
+		}
+		methods := record.methods
+		if len(methods) == 0 {
+			methods = []string{"GET"}
+		}
+		for _, method := range methods {
+			operations[strings.ToLower(method)] = newOperation(record.doc)
+		}
+	}
+
+	document := map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "cn-infra agent API",
+			"version": core.GetVersionInfo().Version,
+		},
+		"paths": paths,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(document)
+}
+
+// newOperation builds the operation object of one route method.
+func newOperation(doc *RouteDoc) map[string]interface{} {
+	operation := map[string]interface{}{}
+	response := map[string]interface{}{"description": "OK"}
+	if doc != nil {
+		if doc.Summary != "" {
+			operation["summary"] = doc.Summary
+		}
+		if doc.Description != "" {
+			operation["description"] = doc.Description
+		}
+		if len(doc.Tags) > 0 {
+			operation["tags"] = doc.Tags
+		}
+		if doc.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"content": jsonContent(doc.Request),
+			}
+		}
+		if doc.Response != nil {
+			response["content"] = jsonContent(doc.Response)
+		}
+	}
+	operation["responses"] = map[string]interface{}{"200": response}
+	return operation
+}
+
+// jsonContent wraps the schema of the value into an OpenAPI content
+// object.
+func jsonContent(value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{
+			"schema": SchemaOf(value),
+		},
+	}
+}
+
+// openapiPath strips the regex parts of the mux path variables, so
+// "/plugins/{name:.*}" becomes the OpenAPI template "/plugins/{name}".
+func openapiPath(path string) string {
+	result := ""
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			break
+		}
+		variable := path[start+1 : start+end]
+		if colon := strings.IndexByte(variable, ':'); colon >= 0 {
+			variable = variable[:colon]
+		}
+		result += path[:start+1] + variable + "}"
+		path = path[start+end+1:]
+	}
+	return result + path
+}
+
+// SchemaOf derives the OpenAPI schema of the value by reflection over
+// its type and json tags.
+func SchemaOf(value interface{}) map[string]interface{} {
+	return schemaOfType(reflect.TypeOf(value))
+}
+
+// timeType is mapped to the date-time string format.
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOfType derives the schema of one type.
+func schemaOfType(t reflect.Type) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte marshals as a base64 string
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaOfType(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return schemaOfStruct(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaOfStruct derives the object schema of a struct from its json
+// tags, descending into the embedded fields.
+func schemaOfStruct(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	collectProperties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// collectProperties adds the properties of the struct fields into the
+// map.
+func collectProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported fields are not marshaled
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectProperties(embedded, properties)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOfType(field.Type)
+	}
+}
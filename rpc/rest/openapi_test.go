@@ -0,0 +1,123 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// ifConfig is a sample request body of the documented test route.
+type ifConfig struct {
+	Name    string            `json:"name"`
+	Enabled bool              `json:"enabled"`
+	MTU     int               `json:"mtu"`
+	Labels  map[string]string `json:"labels"`
+	Created time.Time         `json:"created"`
+	private string
+}
+
+// TestOpenAPIDocument checks that the generated document covers the
+// registered routes with their schemas.
+func TestOpenAPIDocument(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	plugin.RegisterDocumentedHandler("/interfaces/{name}", noop, &RouteDoc{
+		Summary:  "Configure an interface",
+		Tags:     []string{"interfaces"},
+		Request:  ifConfig{},
+		Response: ifConfig{},
+	}, "PUT")
+	plugin.RegisterHTTPHandler("/plugins", noop, "GET")
+
+	req := httptest.NewRequest("GET", OpenAPIPath, nil)
+	resp := httptest.NewRecorder()
+	plugin.router.ServeHTTP(resp, req)
+	gomega.Expect(resp.Code).To(gomega.BeEquivalentTo(http.StatusOK))
+
+	var document struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Summary     string   `json:"summary"`
+			Tags        []string `json:"tags"`
+			RequestBody struct {
+				Content map[string]struct {
+					Schema map[string]interface{} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]interface{} `json:"responses"`
+		} `json:"paths"`
+	}
+	gomega.Expect(json.Unmarshal(resp.Body.Bytes(), &document)).To(gomega.BeNil())
+	gomega.Expect(document.OpenAPI).To(gomega.BeEquivalentTo("3.0.0"))
+
+	put, found := document.Paths["/interfaces/{name}"]["put"]
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(put.Summary).To(gomega.BeEquivalentTo("Configure an interface"))
+	gomega.Expect(put.Tags).To(gomega.Equal([]string{"interfaces"}))
+	schema := put.RequestBody.Content["application/json"].Schema
+	gomega.Expect(schema["type"]).To(gomega.BeEquivalentTo("object"))
+	properties := schema["properties"].(map[string]interface{})
+	gomega.Expect(properties).To(gomega.HaveKey("name"))
+	gomega.Expect(properties).To(gomega.HaveKey("mtu"))
+	gomega.Expect(properties).NotTo(gomega.HaveKey("private"))
+	gomega.Expect(put.Responses).To(gomega.HaveKey("200"))
+
+	// undocumented routes appear with an empty operation
+	_, found = document.Paths["/plugins"]["get"]
+	gomega.Expect(found).To(gomega.BeTrue())
+	// the document endpoint itself is listed
+	_, found = document.Paths[OpenAPIPath]["get"]
+	gomega.Expect(found).To(gomega.BeTrue())
+}
+
+// TestSchemaOf checks the reflection-derived schemas of the common Go
+// types.
+func TestSchemaOf(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	gomega.Expect(SchemaOf("")).To(gomega.Equal(map[string]interface{}{"type": "string"}))
+	gomega.Expect(SchemaOf(0)).To(gomega.Equal(map[string]interface{}{"type": "integer"}))
+	gomega.Expect(SchemaOf(false)).To(gomega.Equal(map[string]interface{}{"type": "boolean"}))
+	gomega.Expect(SchemaOf([]byte(nil))).To(gomega.Equal(
+		map[string]interface{}{"type": "string", "format": "byte"}))
+	gomega.Expect(SchemaOf([]string(nil))).To(gomega.Equal(
+		map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}))
+	gomega.Expect(SchemaOf(time.Time{})).To(gomega.Equal(
+		map[string]interface{}{"type": "string", "format": "date-time"}))
+}
+
+// TestOpenAPIPathTemplates checks the conversion of the mux path
+// variables into OpenAPI templates.
+func TestOpenAPIPathTemplates(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	gomega.Expect(openapiPath("/plugins/{name}/restart")).To(gomega.BeEquivalentTo("/plugins/{name}/restart"))
+	gomega.Expect(openapiPath("/proxy/{path:.*}")).To(gomega.BeEquivalentTo("/proxy/{path}"))
+	gomega.Expect(openapiPath("/plain")).To(gomega.BeEquivalentTo("/plain"))
+}
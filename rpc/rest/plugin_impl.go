@@ -0,0 +1,134 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// HTTPHandlers is the API of the plugin used by other plugins to publish
+// their REST endpoints on the shared HTTP server.
+type HTTPHandlers interface {
+	// RegisterHTTPHandler registers a handler at the given path,
+	// restricted to the given HTTP methods.
+	RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route
+}
+
+// Plugin implements the shared HTTP server of the agent. It implements
+// the core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	router     *mux.Router
+	middleware []Middleware
+	routes     []routeRecord
+	server     *http.Server
+	listener   net.Listener
+}
+
+// NewPlugin creates a new instance of the plugin. The configuration
+// of the HTTP server is loaded from the file during Init.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile}
+}
+
+// Init loads the configuration and prepares the router. Other plugins can
+// register their handlers from this point on.
+func (plugin *Plugin) Init() error {
+	config, err := configFromFile(plugin.configFile)
+	if err != nil {
+		return err
+	}
+	plugin.config = config
+	if err := validateBasicAuth(config.ClientBasicAuth); err != nil {
+		return err
+	}
+	plugin.router = mux.NewRouter()
+	plugin.RegisterDocumentedHandler(OpenAPIPath, http.HandlerFunc(plugin.openapiHandler),
+		&RouteDoc{Summary: "OpenAPI description of the agent API", Tags: []string{"meta"}}, "GET")
+	return nil
+}
+
+// AfterInit starts the HTTP server. It runs after the Init of all plugins,
+// so that all handlers are already registered.
+func (plugin *Plugin) AfterInit() error {
+	listener, err := listen(plugin.config)
+	if err != nil {
+		plugin.log.Errorf("Failed to listen on %s, Error: '%s'", plugin.config.Endpoint, err)
+		return err
+	}
+	if plugin.config.UseHTTPS() {
+		tlsListener, err := plugin.wrapTLS(listener)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		listener = tlsListener
+	}
+	plugin.listener = listener
+
+	handler := WrapMiddleware(plugin.router, plugin.middleware...)
+	if len(plugin.config.ClientBasicAuth) > 0 {
+		handler = authHandler(plugin.config.ClientBasicAuth, handler)
+	}
+	if len(plugin.config.CORS.AllowedOrigins) > 0 {
+		handler = corsHandler(plugin.config.CORS, handler)
+	}
+	plugin.server = &http.Server{Handler: handler}
+
+	go func() {
+		serveErr := plugin.server.Serve(listener)
+		if serveErr != nil && serveErr != http.ErrServerClosed {
+			plugin.log.Error("HTTP server error: ", serveErr)
+		}
+	}()
+	plugin.log.Info("HTTP server is listening on ", plugin.GetAddress())
+	return nil
+}
+
+// Close stops the HTTP server.
+func (plugin *Plugin) Close() error {
+	if plugin.server == nil {
+		return nil
+	}
+	return plugin.server.Close()
+}
+
+// RegisterHTTPHandler registers a handler at the given path, restricted
+// to the given HTTP methods (all methods when none is given).
+func (plugin *Plugin) RegisterHTTPHandler(path string, handler http.Handler, methods ...string) *mux.Route {
+	plugin.log.Debug("Registering HTTP handler at ", path)
+	route := plugin.router.Handle(path, handler)
+	if len(methods) > 0 {
+		route.Methods(methods...)
+	}
+	plugin.routes = append(plugin.routes, routeRecord{path: path, methods: methods})
+	return route
+}
+
+// GetAddress returns the address the server is listening on. It differs
+// from the configured endpoint when port 0 was configured.
+func (plugin *Plugin) GetAddress() string {
+	if plugin.listener == nil {
+		return plugin.config.Endpoint
+	}
+	return plugin.listener.Addr().String()
+}
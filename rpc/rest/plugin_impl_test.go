@@ -0,0 +1,198 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+func TestRegisterHTTPHandler(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	plugin := NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	plugin.RegisterHTTPHandler("/test", http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("hello"))
+		}), "GET")
+
+	gomega.Expect(plugin.AfterInit()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	resp, err := http.Get("http://" + plugin.GetAddress() + "/test")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+	body, err := ioutil.ReadAll(resp.Body)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(string(body)).To(gomega.Equal("hello"))
+
+	// the method restriction is applied
+	resp, err = http.Post("http://"+plugin.GetAddress()+"/test", "text/plain", nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).NotTo(gomega.Equal(http.StatusOK))
+}
+
+func TestMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	os.Setenv("HTTP_ENDPOINT", "127.0.0.1:0")
+	defer os.Unsetenv("HTTP_ENDPOINT")
+
+	plugin := NewPlugin(logrus.StandardLogger(), "")
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	var order []string
+	named := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+	plugin.Use(named("first"), named("second"))
+	plugin.Use(RecoveryMiddleware(logrus.StandardLogger()))
+
+	plugin.RegisterHTTPHandler("/mw", http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("ok"))
+		}), "GET")
+	plugin.RegisterHTTPHandler("/panic", http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			panic("oops")
+		}), "GET")
+
+	gomega.Expect(plugin.AfterInit()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	resp, err := http.Get("http://" + plugin.GetAddress() + "/mw")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(order).To(gomega.Equal([]string{"first", "second"}))
+
+	// the panic is recovered and turned into 500
+	resp, err = http.Get("http://" + plugin.GetAddress() + "/panic")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusInternalServerError))
+}
+
+func TestBasicAuth(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	configFile, err := ioutil.TempFile("", "http-config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString("endpoint: 127.0.0.1:0\nclient-basic-auth:\n  - user:pass\n")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(configFile.Close()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), configFile.Name())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	plugin.RegisterHTTPHandler("/secured", http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("granted"))
+		}), "GET")
+
+	gomega.Expect(plugin.AfterInit()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	// without credentials the request is rejected
+	resp, err := http.Get("http://" + plugin.GetAddress() + "/secured")
+	gomega.Expect(err).To(gomega.BeNil())
+	resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusUnauthorized))
+
+	// with valid credentials the request passes
+	req, err := http.NewRequest("GET", "http://"+plugin.GetAddress()+"/secured", nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	req.SetBasicAuth("user", "pass")
+	resp, err = http.DefaultClient.Do(req)
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+}
+
+func TestUnixSocket(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	socket := filepath.Join(os.TempDir(), "http-test.sock")
+	configFile, err := ioutil.TempFile("", "http-config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString("network: unix\nendpoint: " + socket + "\nsocket-permissions: \"0600\"\n")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(configFile.Close()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), configFile.Name())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	plugin.RegisterHTTPHandler("/test", http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("hello"))
+		}), "GET")
+
+	gomega.Expect(plugin.AfterInit()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	info, err := os.Stat(socket)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(info.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+
+	client := &http.Client{Transport: &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://unix/test")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer resp.Body.Close()
+	gomega.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+}
+
+func TestAfterInitBadTLSConfig(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	configFile, err := ioutil.TempFile("", "http-config")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString("endpoint: 127.0.0.1:0\n" +
+		"server-cert-file: /does/not/exist.crt\nserver-key-file: /does/not/exist.key\n")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(configFile.Close()).To(gomega.BeNil())
+
+	plugin := NewPlugin(logrus.StandardLogger(), configFile.Name())
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+
+	// the missing certificate is reported as an error, not a panic
+	gomega.Expect(plugin.AfterInit()).NotTo(gomega.BeNil())
+	gomega.Expect(plugin.Close()).To(gomega.BeNil())
+}
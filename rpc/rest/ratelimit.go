@@ -0,0 +1,43 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ligato/cn-infra/utils/ratelimit"
+)
+
+// RateLimitMiddleware limits the rate of the requests to the wrapped
+// routes to the given number of requests per second with the given burst.
+// Requests over the limit are rejected with 429 Too Many Requests and
+// a Retry-After header.
+func RateLimitMiddleware(requestsPerSecond float64, burst int) Middleware {
+	bucket := ratelimit.NewTokenBucket(requestsPerSecond, burst)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			allowed, wait := bucket.Allow()
+			if !allowed {
+				retryAfter := int(wait/time.Second) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, "429 Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	handler := RateLimitMiddleware(1, 2)(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("ok"))
+		}))
+
+	get := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/limited", nil))
+		return recorder
+	}
+
+	// the burst of two requests passes
+	gomega.Expect(get().Code).To(gomega.Equal(http.StatusOK))
+	gomega.Expect(get().Code).To(gomega.Equal(http.StatusOK))
+
+	// the third request is over the limit
+	recorder := get()
+	gomega.Expect(recorder.Code).To(gomega.Equal(http.StatusTooManyRequests))
+	gomega.Expect(recorder.Header().Get("Retry-After")).NotTo(gomega.BeEmpty())
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ligato/cn-infra/utils/tlsreload"
+)
+
+// wrapTLS wraps the listener with TLS using the configured server
+// certificate, optionally verifying client certificates against
+// the configured CA certificates. The certificate is transparently
+// reloaded when the files are rotated.
+func (plugin *Plugin) wrapTLS(listener net.Listener) (net.Listener, error) {
+	keyPair, err := tlsreload.NewKeyPair(plugin.log, plugin.config.ServerCertfile, plugin.config.ServerKeyfile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		GetCertificate: keyPair.GetCertificate,
+	}
+	if len(plugin.config.ClientCerts) > 0 {
+		caPool := x509.NewCertPool()
+		for _, c := range plugin.config.ClientCerts {
+			pem, err := ioutil.ReadFile(c)
+			if err != nil {
+				return nil, err
+			}
+			if !caPool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("failed to add CA certificate from '%s'", c)
+			}
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = caPool
+	}
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+// authHandler rejects requests without valid basic-auth credentials
+// with 401 Unauthorized before passing them to the next handler.
+func authHandler(permitted []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, pass, ok := req.BasicAuth()
+		if ok {
+			for _, cred := range permitted {
+				if cred == user+":"+pass {
+					next.ServeHTTP(w, req)
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="cn-infra"`)
+		http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// validateBasicAuth checks that the configured credentials have
+// the "user:password" form.
+func validateBasicAuth(permitted []string) error {
+	for _, cred := range permitted {
+		if !strings.Contains(cred, ":") {
+			return fmt.Errorf("invalid basic-auth entry '%s', expected user:password", cred)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ligato/cn-infra/logging/measure"
+)
+
+// TracerPath is the path of the tracer dump endpoint.
+const TracerPath = "/tracer/{name}"
+
+// RegisterTracerHandler exposes the entries recorded by the given
+// tracers on the REST plugin. GET returns the entries of the tracer
+// selected by name as JSON; DELETE clears them.
+func RegisterTracerHandler(handlers HTTPHandlers, tracers ...*measure.Tracer) {
+	byName := map[string]*measure.Tracer{}
+	for _, tracer := range tracers {
+		byName[tracer.Name()] = tracer
+	}
+	handlers.RegisterHTTPHandler(TracerPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			tracer, found := byName[mux.Vars(r)["name"]]
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Method == "DELETE" {
+				tracer.Clear()
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tracer.Get())
+		}), "GET", "DELETE")
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ligato/cn-infra/core"
+)
+
+// VersionPath is the path of the build information endpoint.
+const VersionPath = "/info/version"
+
+// RegisterVersionHandler exposes the build information of the agent
+// (version, git commit, build date) on the REST plugin.
+func RegisterVersionHandler(handlers HTTPHandlers) {
+	handlers.RegisterHTTPHandler(VersionPath, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(core.GetVersionInfo())
+		}), "GET")
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a job runs next.
+type Schedule interface {
+	// Next returns the first activation time strictly after the given
+	// time.
+	Next(after time.Time) time.Time
+}
+
+// Every returns a schedule activating with the fixed period.
+func Every(period time.Duration) Schedule {
+	return periodicSchedule{period: period}
+}
+
+// periodicSchedule activates with a fixed period.
+type periodicSchedule struct {
+	period time.Duration
+}
+
+// Next returns the activation one period after the given time.
+func (schedule periodicSchedule) Next(after time.Time) time.Time {
+	return after.Add(schedule.period)
+}
+
+// CronSchedule activates at the minutes matching a five-field cron
+// expression.
+type CronSchedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+
+	// anyDay and anyWeekday remember whether the field was "*"; as in
+	// classic cron, when both day fields are restricted a time matching
+	// either of them activates the schedule.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// cronField describes the range of one field of the expression.
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+// cronFields are the five fields of the expression in order.
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// ParseCron parses a classic five-field cron expression
+// "minute hour day-of-month month day-of-week". Every field supports
+// "*", lists ("1,15"), ranges ("1-5") and steps ("*/10", "0-30/5").
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields", spec, len(cronFields))
+	}
+	sets := make([]map[int]bool, len(cronFields))
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	return &CronSchedule{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField expands one field of the expression into the set of
+// matching values.
+func parseCronField(field string, bounds cronField) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if slash := strings.IndexByte(part, '/'); slash >= 0 {
+			parsed, err := strconv.Atoi(part[slash+1:])
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid step in the %s field %q", bounds.name, field)
+			}
+			step = parsed
+			part = part[:slash]
+		}
+		lo, hi := bounds.min, bounds.max
+		switch {
+		case part == "*":
+		case strings.IndexByte(part, '-') > 0:
+			dash := strings.IndexByte(part, '-')
+			var err error
+			lo, err = strconv.Atoi(part[:dash])
+			if err == nil {
+				hi, err = strconv.Atoi(part[dash+1:])
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid range in the %s field %q", bounds.name, field)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in the %s field %q", bounds.name, field)
+			}
+			lo, hi = value, value
+			// a bare value with a step ("5/10") means "from the value up"
+			if step == 1 {
+				hi = value
+			} else {
+				hi = bounds.max
+			}
+		}
+		if lo < bounds.min || hi > bounds.max || lo > hi {
+			return nil, fmt.Errorf("the %s field %q is out of the range %d-%d",
+				bounds.name, field, bounds.min, bounds.max)
+		}
+		for value := lo; value <= hi; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first matching minute strictly after the given time.
+// The zero time is returned when nothing matches within five years
+// (e.g. February 30th).
+func (schedule *CronSchedule) Next(after time.Time) time.Time {
+	candidate := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+	for ; candidate.Before(limit); candidate = candidate.Add(time.Minute) {
+		if !schedule.months[int(candidate.Month())] {
+			continue
+		}
+		if !schedule.matchesDay(candidate) {
+			continue
+		}
+		if schedule.hours[candidate.Hour()] && schedule.minutes[candidate.Minute()] {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay applies the classic cron day semantics: when both the
+// day-of-month and the day-of-week fields are restricted, a day matching
+// either of them counts.
+func (schedule *CronSchedule) matchesDay(t time.Time) bool {
+	day := schedule.days[t.Day()]
+	weekday := schedule.weekdays[int(t.Weekday())]
+	if !schedule.anyDay && !schedule.anyWeekday {
+		return day || weekday
+	}
+	return day && weekday
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+// TestCronNext checks the activation times of typical expressions.
+func TestCronNext(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	// 2017-06-15 was a Thursday
+	after := time.Date(2017, 6, 15, 10, 30, 45, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2017, 6, 15, 10, 31, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2017, 6, 15, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2017, 6, 15, 10, 45, 0, 0, time.UTC)},
+		{"5 4 * * *", time.Date(2017, 6, 16, 4, 5, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2017, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2017, 6, 18, 12, 0, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2017, 6, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 6 * 12 *", time.Date(2017, 12, 1, 6, 30, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		schedule, err := ParseCron(c.spec)
+		gomega.Expect(err).To(gomega.BeNil(), c.spec)
+		gomega.Expect(schedule.Next(after)).To(gomega.BeTemporally("==", c.next), c.spec)
+	}
+}
+
+// TestCronDaySemantics checks that a restricted day-of-month and
+// day-of-week match a day satisfying either of them.
+func TestCronDaySemantics(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	// "at noon on the 1st and on Mondays"
+	schedule, err := ParseCron("0 12 1 * 1")
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// 2017-06-15 is a Thursday; the next match is Monday the 19th
+	after := time.Date(2017, 6, 15, 0, 0, 0, 0, time.UTC)
+	gomega.Expect(schedule.Next(after)).To(gomega.BeTemporally("==",
+		time.Date(2017, 6, 19, 12, 0, 0, 0, time.UTC)))
+
+	// after that Monday the next match is the 1st of July
+	after = time.Date(2017, 6, 26, 13, 0, 0, 0, time.UTC)
+	gomega.Expect(schedule.Next(after)).To(gomega.BeTemporally("==",
+		time.Date(2017, 7, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+// TestCronParseErrors checks the rejection of malformed expressions.
+func TestCronParseErrors(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"a * * * *",
+		"1-b * * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+	} {
+		_, err := ParseCron(spec)
+		gomega.Expect(err).NotTo(gomega.BeNil(), spec)
+	}
+}
+
+// TestCronNeverMatches checks that an impossible expression returns the
+// zero time instead of looping forever.
+func TestCronNeverMatches(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	schedule, err := ParseCron("0 0 30 2 *")
+	gomega.Expect(err).To(gomega.BeNil())
+	next := schedule.Next(time.Date(2017, 6, 15, 0, 0, 0, 0, time.UTC))
+	gomega.Expect(next.IsZero()).To(gomega.BeTrue())
+}
+
+// TestEvery checks the fixed-period schedule.
+func TestEvery(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	after := time.Date(2017, 6, 15, 10, 30, 0, 0, time.UTC)
+	gomega.Expect(Every(time.Hour).Next(after)).To(gomega.BeTemporally("==",
+		after.Add(time.Hour)))
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler implements a plugin the other plugins of an agent
+// register their periodic jobs with, replacing hand-rolled tickers. Jobs
+// run on a fixed period or a cron expression, with optional start
+// jitter, per-job timeouts and overlap prevention; the status of the
+// last runs is reported through the status check plugin, so failing jobs
+// surface on the health endpoints.
+package scheduler
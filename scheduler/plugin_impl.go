@@ -0,0 +1,261 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/health/statuscheck"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// pluginName identifies the scheduler in the status check reports.
+const pluginName = "scheduler"
+
+// JobFunc is the body of a scheduled job. A non-nil error marks the run
+// as failed.
+type JobFunc func() error
+
+// Job describes one registered periodic task.
+type Job struct {
+	// Name identifies the job; it must be unique within the scheduler.
+	Name string
+	// Schedule decides when the job runs; see Every and ParseCron.
+	Schedule Schedule
+	// Run is the body of the job.
+	Run JobFunc
+	// Jitter delays every activation by a random amount up to this
+	// duration, spreading the load of jobs sharing a schedule.
+	Jitter time.Duration
+	// Timeout marks a run that takes longer as failed; zero means no
+	// limit. The run itself is not interrupted, only reported.
+	Timeout time.Duration
+}
+
+// JobStatus is a snapshot of the execution state of one job.
+type JobStatus struct {
+	// Running reports whether a run is in progress.
+	Running bool `json:"running"`
+	// LastStart and LastFinish are the times of the last run; zero
+	// before the first one.
+	LastStart  time.Time `json:"last_start"`
+	LastFinish time.Time `json:"last_finish"`
+	// LastError is the message of the last failed run, empty when the
+	// last run succeeded.
+	LastError string `json:"last_error,omitempty"`
+	// Runs counts the finished runs, Failures the failed ones and
+	// Skipped the activations suppressed because the previous run was
+	// still in progress.
+	Runs     uint64 `json:"runs"`
+	Failures uint64 `json:"failures"`
+	Skipped  uint64 `json:"skipped"`
+}
+
+// Plugin runs the registered jobs on their schedules. It implements the
+// core.Plugin interface, therefore it can be loaded with other plugins.
+type Plugin struct {
+	log    logging.Logger
+	status statuscheck.PluginStatusWriter
+
+	mu   sync.Mutex
+	jobs map[string]*jobEntry
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// jobEntry is one registered job with its execution state.
+type jobEntry struct {
+	job Job
+
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// NewPlugin creates a new instance of the scheduler. The status check
+// plugin may be nil; when it is given, the scheduler reports an error
+// state while the last run of any job has failed.
+func NewPlugin(log logging.Logger, status statuscheck.PluginStatusWriter) *Plugin {
+	return &Plugin{
+		log:     log,
+		status:  status,
+		jobs:    map[string]*jobEntry{},
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Init registers the scheduler with the status check plugin. The jobs
+// added before Init start running now, later additions immediately.
+func (plugin *Plugin) Init() error {
+	if plugin.status != nil {
+		plugin.status.Register(pluginName, plugin.probe)
+	}
+	return nil
+}
+
+// Close stops the scheduling of all jobs and waits for the in-progress
+// runs to finish.
+func (plugin *Plugin) Close() error {
+	close(plugin.closeCh)
+	plugin.wg.Wait()
+	return nil
+}
+
+// AddJob registers the job and starts scheduling it.
+func (plugin *Plugin) AddJob(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("a job must have a name")
+	}
+	if job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("the job %q must have a schedule and a body", job.Name)
+	}
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	if _, taken := plugin.jobs[job.Name]; taken {
+		return fmt.Errorf("the job name %q is already taken", job.Name)
+	}
+	entry := &jobEntry{job: job}
+	plugin.jobs[job.Name] = entry
+
+	plugin.wg.Add(1)
+	go plugin.schedule(entry)
+	return nil
+}
+
+// GetJobStatus returns the status of the named job.
+func (plugin *Plugin) GetJobStatus(name string) (JobStatus, bool) {
+	plugin.mu.Lock()
+	entry, found := plugin.jobs[name]
+	plugin.mu.Unlock()
+	if !found {
+		return JobStatus{}, false
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.status, true
+}
+
+// GetAllJobStatus returns the status of every registered job.
+func (plugin *Plugin) GetAllJobStatus() map[string]JobStatus {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	statuses := map[string]JobStatus{}
+	for name, entry := range plugin.jobs {
+		entry.mu.Lock()
+		statuses[name] = entry.status
+		entry.mu.Unlock()
+	}
+	return statuses
+}
+
+// probe reports the error state while the last run of any job has
+// failed.
+func (plugin *Plugin) probe() (statuscheck.PluginState, error) {
+	for name, status := range plugin.GetAllJobStatus() {
+		if status.LastError != "" {
+			return statuscheck.Error,
+				fmt.Errorf("the job %q failed: %s", name, status.LastError)
+		}
+	}
+	return statuscheck.OK, nil
+}
+
+// schedule activates the job until the scheduler is closed.
+func (plugin *Plugin) schedule(entry *jobEntry) {
+	defer plugin.wg.Done()
+	now := time.Now()
+	for {
+		next := entry.job.Schedule.Next(now)
+		if next.IsZero() {
+			plugin.log.WithFields(logging.Fields{"job": entry.job.Name}).
+				Warn("The schedule has no further activations, job stopped")
+			return
+		}
+		if entry.job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(entry.job.Jitter))))
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-plugin.closeCh:
+			timer.Stop()
+			return
+		case now = <-timer.C:
+		}
+		if entry.beginRun(now) {
+			plugin.wg.Add(1)
+			go plugin.runJob(entry)
+		}
+	}
+}
+
+// beginRun marks the start of a run; false when the previous run is
+// still in progress and the activation is skipped instead.
+func (entry *jobEntry) beginRun(now time.Time) bool {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.status.Running {
+		entry.status.Skipped++
+		return false
+	}
+	entry.status.Running = true
+	entry.status.LastStart = now
+	return true
+}
+
+// runJob executes the body of the job, enforcing its timeout. It runs
+// in its own goroutine, so the scheduling loop keeps ticking and skips
+// the activations overlapping this run.
+func (plugin *Plugin) runJob(entry *jobEntry) {
+	defer plugin.wg.Done()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- entry.job.Run()
+	}()
+
+	var err error
+	if entry.job.Timeout > 0 {
+		timer := time.NewTimer(entry.job.Timeout)
+		select {
+		case err = <-errCh:
+			timer.Stop()
+		case <-timer.C:
+			err = fmt.Errorf("the run exceeded the timeout %v", entry.job.Timeout)
+			// keep the overlap prevention until the body really returns
+			<-errCh
+		}
+	} else {
+		err = <-errCh
+	}
+
+	entry.mu.Lock()
+	entry.status.Running = false
+	entry.status.LastFinish = time.Now()
+	entry.status.Runs++
+	if err != nil {
+		entry.status.Failures++
+		entry.status.LastError = err.Error()
+	} else {
+		entry.status.LastError = ""
+	}
+	entry.mu.Unlock()
+
+	if err != nil {
+		plugin.log.WithFields(logging.Fields{"job": entry.job.Name, "err": err}).
+			Error("Scheduled job failed")
+	}
+}
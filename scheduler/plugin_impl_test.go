@@ -0,0 +1,166 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/health/statuscheck"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// TestPeriodicJob checks that a registered job runs repeatedly and its
+// status is tracked.
+func TestPeriodicJob(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	var mu sync.Mutex
+	runs := 0
+	err := plugin.AddJob(Job{
+		Name:     "counter",
+		Schedule: Every(5 * time.Millisecond),
+		Run: func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			runs++
+			return nil
+		},
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	gomega.Eventually(func() uint64 {
+		status, _ := plugin.GetJobStatus("counter")
+		return status.Runs
+	}, time.Second).Should(gomega.BeNumerically(">=", 3))
+
+	status, found := plugin.GetJobStatus("counter")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(status.LastError).To(gomega.BeEmpty())
+	gomega.Expect(status.Failures).To(gomega.BeEquivalentTo(0))
+	gomega.Expect(status.LastStart.IsZero()).To(gomega.BeFalse())
+}
+
+// TestOverlapPrevention checks that an activation is skipped while the
+// previous run is still in progress.
+func TestOverlapPrevention(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	defer plugin.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	err := plugin.AddJob(Job{
+		Name:     "slow",
+		Schedule: Every(time.Millisecond),
+		Run: func() error {
+			once.Do(func() { close(started) })
+			<-release
+			return nil
+		},
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	<-started
+	// the next activations must be skipped, not run concurrently
+	gomega.Eventually(func() uint64 {
+		status, _ := plugin.GetJobStatus("slow")
+		return status.Skipped
+	}, time.Second).Should(gomega.BeNumerically(">=", 1))
+
+	status, _ := plugin.GetJobStatus("slow")
+	gomega.Expect(status.Running).To(gomega.BeTrue())
+	gomega.Expect(status.Runs).To(gomega.BeEquivalentTo(0))
+	close(release)
+}
+
+// TestJobTimeout checks that a run exceeding its timeout is reported as
+// failed.
+func TestJobTimeout(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	defer plugin.Close()
+
+	err := plugin.AddJob(Job{
+		Name:     "sleepy",
+		Schedule: Every(time.Millisecond),
+		Timeout:  time.Millisecond,
+		Run: func() error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		},
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	gomega.Eventually(func() string {
+		status, _ := plugin.GetJobStatus("sleepy")
+		return status.LastError
+	}, time.Second).Should(gomega.ContainSubstring("exceeded the timeout"))
+}
+
+// TestStatusCheckIntegration checks that a failing job puts the
+// scheduler into the error state of the status check plugin.
+func TestStatusCheckIntegration(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	status := statuscheck.NewPlugin(logrus.StandardLogger())
+	gomega.Expect(status.Init()).To(gomega.BeNil())
+	defer status.Close()
+
+	plugin := NewPlugin(logrus.StandardLogger(), status)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Expect(plugin.AddJob(Job{
+		Name:     "failing",
+		Schedule: Every(time.Millisecond),
+		Run:      func() error { return errors.New("disk full") },
+	})).To(gomega.BeNil())
+
+	gomega.Eventually(func() error {
+		state, err := plugin.probe()
+		if state != statuscheck.Error {
+			return errors.New("not in the error state yet")
+		}
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring("disk full"))
+		return nil
+	}, time.Second).Should(gomega.BeNil())
+}
+
+// TestAddJobValidation checks the rejection of malformed registrations.
+func TestAddJobValidation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil)
+	defer plugin.Close()
+
+	noop := func() error { return nil }
+	gomega.Expect(plugin.AddJob(Job{Schedule: Every(time.Hour), Run: noop})).NotTo(gomega.BeNil())
+	gomega.Expect(plugin.AddJob(Job{Name: "no-schedule", Run: noop})).NotTo(gomega.BeNil())
+	gomega.Expect(plugin.AddJob(Job{Name: "no-body", Schedule: Every(time.Hour)})).NotTo(gomega.BeNil())
+
+	gomega.Expect(plugin.AddJob(Job{Name: "job", Schedule: Every(time.Hour), Run: noop})).To(gomega.BeNil())
+	gomega.Expect(plugin.AddJob(Job{Name: "job", Schedule: Every(time.Hour), Run: noop})).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/config"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// scheme is the reference scheme the plugin registers with the config
+// layer.
+const scheme = "vault"
+
+// requestTimeout bounds every request to the Vault server.
+const requestTimeout = 10 * time.Second
+
+// Config holds the settings of the Vault secrets provider.
+type Config struct {
+	// Address is the URL of the Vault server, e.g. "https://vault:8200".
+	Address string `json:"address"`
+	// Token authenticates the agent against the server. Typically it is
+	// referenced as ${VAULT_TOKEN} or file:///run/secrets/vault-token
+	// rather than written into the file directly.
+	Token string `json:"token"`
+	// RenewPeriod is the interval of the token self-renewal. Zero
+	// disables the renewal, e.g. for root or long-lived tokens.
+	RenewPeriod time.Duration `json:"renew-period"`
+}
+
+// ConfigFromFile loads the Vault configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Plugin resolves the vault://path#field references of the plugin
+// configuration files against a Vault server.
+type Plugin struct {
+	log    logging.Logger
+	config *Config
+	client *http.Client
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the Vault secrets provider.
+func NewPlugin(log logging.Logger, config *Config) *Plugin {
+	return &Plugin{
+		log:       log,
+		config:    config,
+		client:    &http.Client{Timeout: requestTimeout},
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Init validates the configuration, registers the vault:// resolver and
+// starts the token renewal. It must run before the configs of the
+// plugins using the references are loaded.
+func (plugin *Plugin) Init() error {
+	if plugin.config.Address == "" {
+		return errors.New("the address of the Vault server is not configured")
+	}
+	if plugin.config.Token == "" {
+		return errors.New("the Vault token is not configured")
+	}
+
+	config.RegisterSecretResolver(scheme, plugin.Resolve)
+	if plugin.config.RenewPeriod > 0 {
+		plugin.wg.Add(1)
+		go plugin.periodicRenew()
+	}
+	return nil
+}
+
+// Close unregisters the resolver and stops the token renewal.
+func (plugin *Plugin) Close() error {
+	config.UnregisterSecretResolver(scheme)
+	close(plugin.closeChan)
+	plugin.wg.Wait()
+	return nil
+}
+
+// Resolve reads the field of the secret stored under the path.
+func (plugin *Plugin) Resolve(path string, field string) (string, error) {
+	req, err := http.NewRequest("GET", plugin.url("v1/"+path), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", plugin.config.Token)
+
+	resp, err := plugin.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vault responded with status %s to the read of %s", resp.Status, path)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+	data := secret.Data
+	// the KV version 2 engine nests the fields one level deeper
+	if nested, found := data["data"].(map[string]interface{}); found && data[field] == nil {
+		data = nested
+	}
+	value, found := data[field].(string)
+	if !found {
+		return "", fmt.Errorf("the secret %s has no field %q", path, field)
+	}
+	return value, nil
+}
+
+// periodicRenew renews the token each period.
+func (plugin *Plugin) periodicRenew() {
+	defer plugin.wg.Done()
+
+	ticker := time.NewTicker(plugin.config.RenewPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-plugin.closeChan:
+			return
+		case <-ticker.C:
+			if err := plugin.renewToken(); err != nil {
+				plugin.log.Warn("Failed to renew the Vault token: ", err)
+			}
+		}
+	}
+}
+
+// renewToken performs one self-renewal of the token.
+func (plugin *Plugin) renewToken() error {
+	req, err := http.NewRequest("POST", plugin.url("v1/auth/token/renew-self"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", plugin.config.Token)
+
+	resp, err := plugin.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Vault responded with status %s to the renewal", resp.Status)
+	}
+	plugin.log.Debug("Vault token renewed")
+	return nil
+}
+
+// url composes the URL of one API call.
+func (plugin *Plugin) url(path string) string {
+	return strings.TrimRight(plugin.config.Address, "/") + "/" + path
+}
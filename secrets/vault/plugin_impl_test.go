@@ -0,0 +1,103 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// newFakeVault starts an HTTP server responding like a Vault server with
+// a single secret stored under secret/etcd.
+func newFakeVault(renewals *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != "testToken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch req.URL.Path {
+		case "/v1/secret/etcd":
+			w.Write([]byte(`{"data":{"password":"s3cr3t"}}`))
+		case "/v1/kv/data/etcd":
+			// the KV version 2 engine nests the fields
+			w.Write([]byte(`{"data":{"data":{"password":"nested"},"metadata":{"version":1}}}`))
+		case "/v1/auth/token/renew-self":
+			atomic.AddInt32(renewals, 1)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestResolve(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var renewals int32
+	server := newFakeVault(&renewals)
+	defer server.Close()
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{Address: server.URL, Token: "testToken"})
+
+	secret, err := plugin.Resolve("secret/etcd", "password")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(secret).To(gomega.Equal("s3cr3t"))
+
+	// the nested layout of the KV version 2 engine is understood too
+	secret, err = plugin.Resolve("kv/data/etcd", "password")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(secret).To(gomega.Equal("nested"))
+
+	_, err = plugin.Resolve("secret/etcd", "missing")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	_, err = plugin.Resolve("secret/unknown", "password")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+func TestTokenRenewal(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var renewals int32
+	server := newFakeVault(&renewals)
+	defer server.Close()
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{
+		Address:     server.URL,
+		Token:       "testToken",
+		RenewPeriod: 10 * time.Millisecond,
+	})
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Eventually(func() int32 {
+		return atomic.LoadInt32(&renewals)
+	}).Should(gomega.BeNumerically(">=", 2))
+}
+
+func TestInitValidation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{Token: "testToken"})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+
+	plugin = NewPlugin(logrus.StandardLogger(), &Config{Address: "http://127.0.0.1:8200"})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicelabel
+
+import (
+	"fmt"
+
+	"github.com/ligato/cn-infra/config"
+)
+
+// Constants of the service label resolution.
+const (
+	// MicroserviceLabelFlag is the name of the command-line flag setting
+	// the label; it can also be set via the 'MICROSERVICE_LABEL' env
+	// variable.
+	MicroserviceLabelFlag = "microservice-label"
+	// DefaultMicroserviceLabel is used when the label is not configured.
+	DefaultMicroserviceLabel = "vpp1"
+	// agentPrefix is the common prefix of the data of all agents.
+	agentPrefix = "/vnf-agent/"
+)
+
+var microserviceLabelFlag = config.FlagsFor("servicelabel").String(MicroserviceLabelFlag,
+	DefaultMicroserviceLabel, "Microservice label of the agent; unique across the fleet.")
+
+// ReaderAPI allows the other plugins to read the service label of the agent.
+type ReaderAPI interface {
+	// GetAgentLabel returns the microservice label of the agent.
+	GetAgentLabel() string
+	// GetAgentPrefix returns the prefix of the agent's data in the
+	// key-value store, e.g. /vnf-agent/<label>/.
+	GetAgentPrefix() string
+}
+
+// Config holds the configuration file settings of the plugin.
+type Config struct {
+	MicroserviceLabel string `json:"microservice-label"`
+}
+
+// Plugin resolves the microservice label of the agent from the
+// configuration file, the command-line flag or the environment variable,
+// in this order of precedence. An explicitly set MicroserviceLabel field
+// wins over all of them.
+type Plugin struct {
+	// MicroserviceLabel can be set by the application before Init to
+	// override the configured label.
+	MicroserviceLabel string
+}
+
+// NewPlugin creates the service-label plugin.
+func NewPlugin() *Plugin {
+	return &Plugin{}
+}
+
+// Init resolves the label.
+func (plugin *Plugin) Init() error {
+	if plugin.MicroserviceLabel != "" {
+		return nil
+	}
+	cfg := Config{}
+	if _, err := config.ForPlugin("servicelabel").GetValue(&cfg); err != nil {
+		return err
+	}
+	if cfg.MicroserviceLabel != "" {
+		plugin.MicroserviceLabel = cfg.MicroserviceLabel
+		return nil
+	}
+	plugin.MicroserviceLabel = *microserviceLabelFlag
+	if plugin.MicroserviceLabel == "" {
+		return fmt.Errorf("microservice label of the agent is not set")
+	}
+	return nil
+}
+
+// Close does nothing, the plugin holds no resources.
+func (plugin *Plugin) Close() error {
+	return nil
+}
+
+// GetAgentLabel returns the microservice label of the agent.
+func (plugin *Plugin) GetAgentLabel() string {
+	return plugin.MicroserviceLabel
+}
+
+// GetAgentPrefix returns the prefix of the agent's data in the key-value
+// store, e.g. /vnf-agent/<label>/.
+func (plugin *Plugin) GetAgentPrefix() string {
+	return agentPrefix + plugin.MicroserviceLabel + "/"
+}
+
+// GetAllAgentsPrefix returns the common prefix of the data of all agents.
+func GetAllAgentsPrefix() string {
+	return agentPrefix
+}
+
+// GetDifferentAgentPrefix returns the prefix of the data of another agent.
+func GetDifferentAgentPrefix(microserviceLabel string) string {
+	return agentPrefix + microserviceLabel + "/"
+}
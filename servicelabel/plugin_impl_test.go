@@ -0,0 +1,62 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicelabel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ligato/cn-infra/config"
+	"github.com/onsi/gomega"
+)
+
+func TestAgentPrefix(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin()
+	plugin.MicroserviceLabel = "vpp42"
+	err := plugin.Init()
+	gomega.Expect(err).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Expect(plugin.GetAgentLabel()).To(gomega.Equal("vpp42"))
+	gomega.Expect(plugin.GetAgentPrefix()).To(gomega.Equal("/vnf-agent/vpp42/"))
+	gomega.Expect(GetAllAgentsPrefix()).To(gomega.Equal("/vnf-agent/"))
+	gomega.Expect(GetDifferentAgentPrefix("vpp1")).To(gomega.Equal("/vnf-agent/vpp1/"))
+}
+
+func TestLabelFromConfigFile(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "servicelabel")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "servicelabel.conf"),
+		[]byte("microservice-label: vpp7\n"), 0644)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	os.Setenv(config.DirEnvVar, dir)
+	defer os.Unsetenv(config.DirEnvVar)
+
+	plugin := NewPlugin()
+	err = plugin.Init()
+	gomega.Expect(err).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Expect(plugin.GetAgentLabel()).To(gomega.Equal("vpp7"))
+}
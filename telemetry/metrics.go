@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsRegistry is the part of the metrics plugin API used to register
+// the telemetry collector.
+type MetricsRegistry interface {
+	// RegisterCollector registers a custom collector on the shared
+	// registry.
+	RegisterCollector(collector prometheus.Collector) error
+}
+
+// Descriptors of the exported telemetry metrics.
+var (
+	goroutinesDesc = prometheus.NewDesc("process_goroutines",
+		"Number of live goroutines of the agent.",
+		nil, nil)
+	heapAllocDesc = prometheus.NewDesc("process_heap_alloc_bytes",
+		"Size of the allocated heap objects of the agent.",
+		nil, nil)
+	heapObjectsDesc = prometheus.NewDesc("process_heap_objects",
+		"Number of allocated heap objects of the agent.",
+		nil, nil)
+	gcPauseTotalDesc = prometheus.NewDesc("process_gc_pause_seconds_total",
+		"Cumulative time spent in the GC stop-the-world pauses.",
+		nil, nil)
+	gcCyclesDesc = prometheus.NewDesc("process_gc_cycles_total",
+		"Number of completed GC cycles.",
+		nil, nil)
+	fileDescriptorsDesc = prometheus.NewDesc("process_open_file_descriptors",
+		"Number of open file descriptors of the agent.",
+		nil, nil)
+	channelDepthDesc = prometheus.NewDesc("plugin_channel_depth",
+		"Current depth of one registered plugin channel.",
+		[]string{"channel"}, nil)
+)
+
+// RegisterMetrics exports the runtime statistics on the metrics registry.
+// The statistics are sampled on every scrape.
+func (plugin *Plugin) RegisterMetrics(registry MetricsRegistry) error {
+	return registry.RegisterCollector(&statsCollector{plugin: plugin})
+}
+
+// statsCollector samples the runtime statistics on every scrape.
+type statsCollector struct {
+	plugin *Plugin
+}
+
+// Describe implements the prometheus.Collector interface.
+func (collector *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- goroutinesDesc
+	ch <- heapAllocDesc
+	ch <- heapObjectsDesc
+	ch <- gcPauseTotalDesc
+	ch <- gcCyclesDesc
+	ch <- fileDescriptorsDesc
+	ch <- channelDepthDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (collector *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := collector.plugin.Sample()
+
+	ch <- prometheus.MustNewConstMetric(goroutinesDesc, prometheus.GaugeValue,
+		float64(stats.Goroutines))
+	ch <- prometheus.MustNewConstMetric(heapAllocDesc, prometheus.GaugeValue,
+		float64(stats.HeapAllocBytes))
+	ch <- prometheus.MustNewConstMetric(heapObjectsDesc, prometheus.GaugeValue,
+		float64(stats.HeapObjects))
+	ch <- prometheus.MustNewConstMetric(gcPauseTotalDesc, prometheus.CounterValue,
+		float64(stats.GCPauseTotalNs)/1e9)
+	ch <- prometheus.MustNewConstMetric(gcCyclesDesc, prometheus.CounterValue,
+		float64(stats.GCCycles))
+	if stats.OpenFileDescriptors >= 0 {
+		ch <- prometheus.MustNewConstMetric(fileDescriptorsDesc, prometheus.GaugeValue,
+			float64(stats.OpenFileDescriptors))
+	}
+	for name, depth := range stats.ChannelDepths {
+		ch <- prometheus.MustNewConstMetric(channelDepthDesc, prometheus.GaugeValue,
+			float64(depth), name)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/datasync"
+	"github.com/ligato/cn-infra/db/keyval"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// statusKey is the key the summary record is published under.
+const statusKey = datasync.StatusPrefix + "telemetry"
+
+// Config holds the settings of the telemetry plugin.
+type Config struct {
+	// Period of the periodic publishing of the summary record. Zero
+	// disables the publishing.
+	Period time.Duration
+}
+
+// Stats is one sample of the runtime statistics of the agent process.
+type Stats struct {
+	// Goroutines is the number of live goroutines.
+	Goroutines int `json:"goroutines"`
+	// HeapAllocBytes is the size of the allocated heap objects.
+	HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+	// HeapObjects is the number of allocated heap objects.
+	HeapObjects uint64 `json:"heap_objects"`
+	// GCPauseTotalNs is the cumulative time spent in the GC stop-the-world
+	// pauses since the process started.
+	GCPauseTotalNs uint64 `json:"gc_pause_total_ns"`
+	// GCCycles is the number of completed GC cycles.
+	GCCycles uint32 `json:"gc_cycles"`
+	// OpenFileDescriptors is the number of open file descriptors of the
+	// process, or -1 when it cannot be determined.
+	OpenFileDescriptors int `json:"open_file_descriptors"`
+	// ChannelDepths are the depths of the registered plugin channels.
+	ChannelDepths map[string]int `json:"channel_depths,omitempty"`
+	// Timestamp is the time the sample was taken.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Plugin collects the runtime statistics of the agent process.
+type Plugin struct {
+	log    logging.Logger
+	config *Config
+	broker keyval.BytesBroker
+
+	mu     sync.Mutex
+	depths map[string]func() int
+
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewPlugin creates a new instance of the telemetry plugin. Nil config
+// disables the periodic publishing; nil broker disables the summary
+// record entirely.
+func NewPlugin(log logging.Logger, config *Config, broker keyval.BytesBroker) *Plugin {
+	if config == nil {
+		config = &Config{}
+	}
+	return &Plugin{
+		log:       log,
+		config:    config,
+		broker:    broker,
+		depths:    map[string]func() int{},
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Init starts the periodic publishing of the summary record if it is
+// enabled by the configuration.
+func (plugin *Plugin) Init() error {
+	if plugin.config.Period > 0 && plugin.broker != nil {
+		plugin.wg.Add(1)
+		go plugin.periodicPublish()
+	}
+	return nil
+}
+
+// Close stops the periodic publishing.
+func (plugin *Plugin) Close() error {
+	close(plugin.closeChan)
+	plugin.wg.Wait()
+	return nil
+}
+
+// RegisterChannelDepth registers a probe reporting the current depth of
+// one plugin channel, typically func() int { return len(myChan) }.
+func (plugin *Plugin) RegisterChannelDepth(name string, probe func() int) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+	plugin.depths[name] = probe
+}
+
+// Sample takes one sample of the runtime statistics.
+func (plugin *Plugin) Sample() *Stats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	stats := &Stats{
+		Goroutines:          runtime.NumGoroutine(),
+		HeapAllocBytes:      memStats.HeapAlloc,
+		HeapObjects:         memStats.HeapObjects,
+		GCPauseTotalNs:      memStats.PauseTotalNs,
+		GCCycles:            memStats.NumGC,
+		OpenFileDescriptors: countOpenFileDescriptors(),
+		Timestamp:           time.Now(),
+	}
+
+	plugin.mu.Lock()
+	if len(plugin.depths) > 0 {
+		stats.ChannelDepths = map[string]int{}
+		for name, probe := range plugin.depths {
+			stats.ChannelDepths[name] = probe()
+		}
+	}
+	plugin.mu.Unlock()
+	return stats
+}
+
+// PublishStats writes the summary record of one sample under the KV
+// status prefix.
+func (plugin *Plugin) PublishStats() error {
+	data, err := json.Marshal(plugin.Sample())
+	if err != nil {
+		return err
+	}
+	return plugin.broker.Put(statusKey, data)
+}
+
+// periodicPublish publishes the summary record each period.
+func (plugin *Plugin) periodicPublish() {
+	defer plugin.wg.Done()
+
+	ticker := time.NewTicker(plugin.config.Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-plugin.closeChan:
+			return
+		case <-ticker.C:
+			if err := plugin.PublishStats(); err != nil {
+				plugin.log.Warn("Failed to publish the telemetry record: ", err)
+			}
+		}
+	}
+}
+
+// countOpenFileDescriptors counts the open file descriptors of the
+// process; -1 is returned when procfs is not available.
+func countOpenFileDescriptors() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/datasync/datasynctest"
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSample(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil, nil)
+	queue := make(chan int, 10)
+	queue <- 1
+	queue <- 2
+	plugin.RegisterChannelDepth("testQueue", func() int { return len(queue) })
+
+	stats := plugin.Sample()
+	gomega.Expect(stats.Goroutines).To(gomega.BeNumerically(">", 0))
+	gomega.Expect(stats.HeapAllocBytes).To(gomega.BeNumerically(">", 0))
+	gomega.Expect(stats.ChannelDepths).To(gomega.HaveKeyWithValue("testQueue", 2))
+	gomega.Expect(stats.Timestamp.IsZero()).To(gomega.BeFalse())
+}
+
+func TestPeriodicPublishing(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	broker := datasynctest.NewMemBroker()
+	plugin := NewPlugin(logrus.StandardLogger(), &Config{Period: 10 * time.Millisecond}, broker)
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	gomega.Eventually(func() bool {
+		_, found, _, _ := broker.GetValue(statusKey)
+		return found
+	}).Should(gomega.BeTrue())
+
+	data, _, _, err := broker.GetValue(statusKey)
+	gomega.Expect(err).To(gomega.BeNil())
+	stats := &Stats{}
+	gomega.Expect(json.Unmarshal(data, stats)).To(gomega.BeNil())
+	gomega.Expect(stats.Goroutines).To(gomega.BeNumerically(">", 0))
+}
+
+// registryAdapter exposes a plain Prometheus registry as MetricsRegistry.
+type registryAdapter struct {
+	registry *prometheus.Registry
+}
+
+func (adapter *registryAdapter) RegisterCollector(collector prometheus.Collector) error {
+	return adapter.registry.Register(collector)
+}
+
+func TestMetricsCollector(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPlugin(logrus.StandardLogger(), nil, nil)
+	plugin.RegisterChannelDepth("testQueue", func() int { return 3 })
+
+	registry := prometheus.NewRegistry()
+	gomega.Expect(plugin.RegisterMetrics(&registryAdapter{registry: registry})).To(gomega.BeNil())
+
+	families, err := registry.Gather()
+	gomega.Expect(err).To(gomega.BeNil())
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	gomega.Expect(names).To(gomega.HaveKey("process_goroutines"))
+	gomega.Expect(names).To(gomega.HaveKey("process_heap_alloc_bytes"))
+	gomega.Expect(names).To(gomega.HaveKey("process_gc_pause_seconds_total"))
+	gomega.Expect(names).To(gomega.HaveKey("plugin_channel_depth"))
+}
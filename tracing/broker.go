@@ -0,0 +1,123 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"github.com/ligato/cn-infra/db/keyval"
+)
+
+// NewTracedBytesBroker decorates the broker so that every operation is
+// recorded as a span. A nil parent starts a new trace per operation;
+// typically the parent is the span of the northbound request that
+// caused the operations.
+func NewTracedBytesBroker(tracer Tracer, broker keyval.BytesBroker, parent *SpanContext) keyval.BytesBroker {
+	return &tracedBytesBroker{tracer: tracer, broker: broker, parent: parent}
+}
+
+// tracedBytesBroker decorates keyval.BytesBroker with spans.
+type tracedBytesBroker struct {
+	tracer Tracer
+	broker keyval.BytesBroker
+	parent *SpanContext
+}
+
+// startSpan creates the span of one broker operation.
+func (traced *tracedBytesBroker) startSpan(operation string, key string) Span {
+	span := traced.tracer.StartSpan("keyval "+operation, traced.parent)
+	span.SetTag("key", key)
+	return span
+}
+
+// Put stores the value under the key.
+func (traced *tracedBytesBroker) Put(key string, data []byte, opts ...keyval.PutOption) error {
+	span := traced.startSpan("put", key)
+	defer span.Finish()
+	err := traced.broker.Put(key, data, opts...)
+	span.SetError(err)
+	return err
+}
+
+// GetValue retrieves the value of the key.
+func (traced *tracedBytesBroker) GetValue(key string) (data []byte, found bool, revision int64, err error) {
+	span := traced.startSpan("get", key)
+	defer span.Finish()
+	data, found, revision, err = traced.broker.GetValue(key)
+	span.SetError(err)
+	return data, found, revision, err
+}
+
+// ListKeys returns an iterator over the keys matching the prefix.
+func (traced *tracedBytesBroker) ListKeys(prefix string) (keyval.BytesKeyIterator, error) {
+	span := traced.startSpan("list-keys", prefix)
+	defer span.Finish()
+	iterator, err := traced.broker.ListKeys(prefix)
+	span.SetError(err)
+	return iterator, err
+}
+
+// ListValues returns an iterator over the key-value pairs matching the
+// prefix.
+func (traced *tracedBytesBroker) ListValues(prefix string) (keyval.BytesKeyValIterator, error) {
+	span := traced.startSpan("list-values", prefix)
+	defer span.Finish()
+	iterator, err := traced.broker.ListValues(prefix)
+	span.SetError(err)
+	return iterator, err
+}
+
+// Delete removes the key.
+func (traced *tracedBytesBroker) Delete(key string) (bool, error) {
+	span := traced.startSpan("delete", key)
+	defer span.Finish()
+	existed, err := traced.broker.Delete(key)
+	span.SetError(err)
+	return existed, err
+}
+
+// NewTxn creates a transaction whose commit is recorded as a span.
+func (traced *tracedBytesBroker) NewTxn() keyval.BytesTxn {
+	return &tracedBytesTxn{traced: traced, txn: traced.broker.NewTxn()}
+}
+
+// tracedBytesTxn decorates keyval.BytesTxn with a span of the commit.
+type tracedBytesTxn struct {
+	traced *tracedBytesBroker
+	txn    keyval.BytesTxn
+	ops    int
+}
+
+// Put adds a store operation into the transaction.
+func (txn *tracedBytesTxn) Put(key string, data []byte) keyval.BytesTxn {
+	txn.txn.Put(key, data)
+	txn.ops++
+	return txn
+}
+
+// Delete adds a delete operation into the transaction.
+func (txn *tracedBytesTxn) Delete(key string) keyval.BytesTxn {
+	txn.txn.Delete(key)
+	txn.ops++
+	return txn
+}
+
+// Commit applies the buffered operations.
+func (txn *tracedBytesTxn) Commit() error {
+	span := txn.traced.tracer.StartSpan("keyval txn-commit", txn.traced.parent)
+	span.SetTag("operations", txn.ops)
+	defer span.Finish()
+	err := txn.txn.Commit()
+	span.SetError(err)
+	return err
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ligato/cn-infra/datasync"
+	"github.com/ligato/cn-infra/db/keyval"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// HTTPMiddleware traces every request of the REST server. The span
+// context is extracted from the B3 headers of the request, so the traces
+// started by the northbound clients are continued, and the span is made
+// available to the handlers through the request context.
+func HTTPMiddleware(tracer Tracer) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var parent *SpanContext
+			if extracted, found := ExtractHTTPHeaders(req.Header); found {
+				parent = &extracted
+			}
+			span := tracer.StartSpan("HTTP "+req.Method+" "+req.URL.Path, parent)
+			span.SetTag("http.method", req.Method)
+			span.SetTag("http.url", req.URL.Path)
+			defer span.Finish()
+
+			next.ServeHTTP(w, req.WithContext(ContextWithSpan(req.Context(), span)))
+		})
+	}
+}
+
+// UnaryTracingInterceptor traces every unary call of the gRPC server.
+// The span context is extracted from the call metadata and the span is
+// made available to the handlers through the context.
+func UnaryTracingInterceptor(tracer Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span := tracer.StartSpan("gRPC "+info.FullMethod, spanContextFromMetadata(ctx))
+		span.SetTag("grpc.method", info.FullMethod)
+		defer span.Finish()
+
+		resp, err := handler(ContextWithSpan(ctx, span), req)
+		span.SetError(err)
+		return resp, err
+	}
+}
+
+// StreamTracingInterceptor traces every stream of the gRPC server.
+func StreamTracingInterceptor(tracer Tracer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		span := tracer.StartSpan("gRPC "+info.FullMethod, spanContextFromMetadata(ss.Context()))
+		span.SetTag("grpc.method", info.FullMethod)
+		defer span.Finish()
+
+		err := handler(srv, ss)
+		span.SetError(err)
+		return err
+	}
+}
+
+// DatasyncMiddleware traces the processing of every change event. The
+// span is finished right away since the middleware cannot observe the
+// end of the processing; it still records the event in the trace.
+func DatasyncMiddleware(tracer Tracer) datasync.Middleware {
+	return func(resp keyval.ProtoWatchResp) (keyval.ProtoWatchResp, bool) {
+		span := tracer.StartSpan("datasync change", nil)
+		span.SetTag("key", resp.GetKey())
+		span.SetTag("change.type", string(resp.GetChangeType()))
+		span.SetTag("revision", resp.GetRevision())
+		span.Finish()
+		return resp, true
+	}
+}
+
+// InjectGRPCMetadata returns a copy of the context with the span context
+// attached to the outgoing call metadata.
+func InjectGRPCMetadata(ctx context.Context, spanContext SpanContext) context.Context {
+	sampled := "0"
+	if spanContext.Sampled {
+		sampled = "1"
+	}
+	md := metadata.Pairs(
+		strings.ToLower(traceIDHeader), strconv.FormatUint(spanContext.TraceID, 16),
+		strings.ToLower(spanIDHeader), strconv.FormatUint(spanContext.SpanID, 16),
+		strings.ToLower(sampledHeader), sampled,
+	)
+	return metadata.NewContext(ctx, md)
+}
+
+// spanContextFromMetadata reads the span context from the metadata of an
+// incoming call; nil is returned when the call carries no trace.
+func spanContextFromMetadata(ctx context.Context) *SpanContext {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	traceID, err := parseMetadataID(md, strings.ToLower(traceIDHeader))
+	if err != nil {
+		return nil
+	}
+	spanID, err := parseMetadataID(md, strings.ToLower(spanIDHeader))
+	if err != nil {
+		return nil
+	}
+	sampled := len(md[strings.ToLower(sampledHeader)]) > 0 &&
+		md[strings.ToLower(sampledHeader)][0] == "1"
+	return &SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled}
+}
+
+// parseMetadataID parses one identifier of the span context.
+func parseMetadataID(md metadata.MD, key string) (uint64, error) {
+	values := md[key]
+	if len(values) == 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseUint(values[0], 16, 64)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/ligato/cn-infra/logging"
+)
+
+// The supported exporters.
+const (
+	// ExporterLog writes the finished spans to the logger of the plugin.
+	// It is built in and serves development and troubleshooting.
+	ExporterLog = "log"
+	// ExporterJaeger ships the spans to a Jaeger agent. It requires an
+	// ExporterFactory backed by a Jaeger client.
+	ExporterJaeger = "jaeger"
+	// ExporterOTLP ships the spans to an OpenTelemetry collector. It
+	// requires an ExporterFactory backed by an OTLP client.
+	ExporterOTLP = "otlp"
+)
+
+// Config holds the settings of the tracing plugin.
+type Config struct {
+	// Enabled turns the tracing on.
+	Enabled bool `json:"enabled"`
+	// ServiceName names the process in the traces. It defaults to
+	// "cn-infra".
+	ServiceName string `json:"service-name"`
+	// Exporter selects the span exporter: "log", "jaeger" or "otlp".
+	// It defaults to "log".
+	Exporter string `json:"exporter"`
+	// Endpoint is the address of the tracing backend the external
+	// exporters ship the spans to.
+	Endpoint string `json:"endpoint"`
+	// SampleRate is the fraction of traces to record, from 0 to 1.
+	// It defaults to 1.
+	SampleRate *float64 `json:"sample-rate"`
+}
+
+// ConfigFromFile loads the tracing configuration from the specified file.
+func ConfigFromFile(fpath string) (*Config, error) {
+	b, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	err = yaml.Unmarshal(b, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Plugin initializes the tracer with the configured exporter and makes
+// it available to the other plugins.
+type Plugin struct {
+	log        logging.Logger
+	config     *Config
+	configFile string
+
+	tracer   Tracer
+	exporter Exporter
+}
+
+// NewPlugin creates a new instance of the tracing plugin. The
+// configuration is loaded from the file during Init.
+func NewPlugin(log logging.Logger, configFile string) *Plugin {
+	return &Plugin{log: log, configFile: configFile}
+}
+
+// NewPluginUsingConfig creates a new instance of the tracing plugin with
+// an already loaded configuration.
+func NewPluginUsingConfig(log logging.Logger, config *Config) *Plugin {
+	return &Plugin{log: log, config: config}
+}
+
+// Init creates the exporter and the tracer. A disabled plugin provides
+// a tracer that records nothing.
+func (plugin *Plugin) Init() error {
+	if plugin.config == nil {
+		config, err := ConfigFromFile(plugin.configFile)
+		if err != nil {
+			return err
+		}
+		plugin.config = config
+	}
+	if !plugin.config.Enabled {
+		plugin.tracer = NewTracer("", noopExporter{}, 0)
+		return nil
+	}
+
+	serviceName := plugin.config.ServiceName
+	if serviceName == "" {
+		serviceName = "cn-infra"
+	}
+	sampleRate := 1.0
+	if plugin.config.SampleRate != nil {
+		sampleRate = *plugin.config.SampleRate
+	}
+
+	exporter, err := plugin.newExporter()
+	if err != nil {
+		return err
+	}
+	plugin.exporter = exporter
+	plugin.tracer = NewTracer(serviceName, exporter, sampleRate)
+	plugin.log.WithFields(logging.Fields{
+		"exporter":   plugin.exporterName(),
+		"sampleRate": sampleRate,
+	}).Info("Tracing enabled")
+	return nil
+}
+
+// Close flushes and releases the exporter.
+func (plugin *Plugin) Close() error {
+	if plugin.exporter == nil {
+		return nil
+	}
+	return plugin.exporter.Close()
+}
+
+// Tracer returns the tracer of the agent. It is valid after Init.
+func (plugin *Plugin) Tracer() Tracer {
+	return plugin.tracer
+}
+
+// newExporter creates the exporter selected by the configuration.
+func (plugin *Plugin) newExporter() (Exporter, error) {
+	switch plugin.exporterName() {
+	case ExporterLog:
+		return &logExporter{log: plugin.log}, nil
+	case ExporterJaeger, ExporterOTLP:
+		if ExporterFactory == nil {
+			return nil, fmt.Errorf("exporter %q requires a registered ExporterFactory", plugin.exporterName())
+		}
+		return ExporterFactory(plugin.config)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", plugin.exporterName())
+	}
+}
+
+// exporterName returns the configured exporter name with the default
+// applied.
+func (plugin *Plugin) exporterName() string {
+	if plugin.config.Exporter == "" {
+		return ExporterLog
+	}
+	return plugin.config.Exporter
+}
+
+// logExporter writes the finished spans to the logger.
+type logExporter struct {
+	log logging.Logger
+}
+
+// ExportSpan logs one finished span.
+func (exporter *logExporter) ExportSpan(span *SpanData) {
+	fields := logging.Fields{
+		"traceID":   fmt.Sprintf("%016x", span.Context.TraceID),
+		"spanID":    fmt.Sprintf("%016x", span.Context.SpanID),
+		"operation": span.Operation,
+		"duration":  span.Duration,
+	}
+	if span.ParentSpanID != 0 {
+		fields["parentSpanID"] = fmt.Sprintf("%016x", span.ParentSpanID)
+	}
+	for key, value := range span.Tags {
+		fields["tag."+key] = value
+	}
+	exporter.log.WithFields(fields).Debug("Span finished")
+}
+
+// Close is a no-op.
+func (exporter *logExporter) Close() error {
+	return nil
+}
+
+// noopExporter discards the spans of a disabled plugin.
+type noopExporter struct{}
+
+// ExportSpan discards the span.
+func (noopExporter) ExportSpan(span *SpanData) {}
+
+// Close is a no-op.
+func (noopExporter) Close() error {
+	return nil
+}
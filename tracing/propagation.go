@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/net/context"
+)
+
+// The B3 headers carrying the span context between the processes.
+const (
+	traceIDHeader = "X-B3-TraceId"
+	spanIDHeader  = "X-B3-SpanId"
+	sampledHeader = "X-B3-Sampled"
+)
+
+// contextKey is the type of the key the span is stored under in a context.
+type contextKey struct{}
+
+// ContextWithSpan stores the span in the context.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, contextKey{}, span)
+}
+
+// SpanFromContext retrieves the span stored in the context.
+func SpanFromContext(ctx context.Context) (Span, bool) {
+	span, found := ctx.Value(contextKey{}).(Span)
+	return span, found
+}
+
+// InjectHTTPHeaders writes the span context into the B3 headers of an
+// outgoing request.
+func InjectHTTPHeaders(header http.Header, spanContext SpanContext) {
+	header.Set(traceIDHeader, fmt.Sprintf("%016x", spanContext.TraceID))
+	header.Set(spanIDHeader, fmt.Sprintf("%016x", spanContext.SpanID))
+	sampled := "0"
+	if spanContext.Sampled {
+		sampled = "1"
+	}
+	header.Set(sampledHeader, sampled)
+}
+
+// ExtractHTTPHeaders reads the span context from the B3 headers of an
+// incoming request. It returns false when the request carries no trace.
+func ExtractHTTPHeaders(header http.Header) (SpanContext, bool) {
+	traceID, err := strconv.ParseUint(header.Get(traceIDHeader), 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	spanID, err := strconv.ParseUint(header.Get(spanIDHeader), 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: header.Get(sampledHeader) == "1",
+	}, true
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// NewTracer creates a tracer that hands the finished spans over to the
+// exporter. The sampleRate is the fraction of traces to record, from 0
+// (nothing) to 1 (everything); the spans joining an already running
+// trace inherit its sampling decision.
+func NewTracer(service string, exporter Exporter, sampleRate float64) Tracer {
+	return &tracer{
+		service:    service,
+		exporter:   exporter,
+		sampleRate: sampleRate,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// tracer implements Tracer.
+type tracer struct {
+	service    string
+	exporter   Exporter
+	sampleRate float64
+
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+// StartSpan creates a span of the operation.
+func (tracer *tracer) StartSpan(operation string, parent *SpanContext) Span {
+	tracer.mu.Lock()
+	spanID := tracer.random.Uint64()
+	traceID := spanID
+	var parentSpanID uint64
+	var sampled bool
+	if parent != nil {
+		traceID = parent.TraceID
+		parentSpanID = parent.SpanID
+		sampled = parent.Sampled
+	} else {
+		sampled = tracer.random.Float64() < tracer.sampleRate
+	}
+	tracer.mu.Unlock()
+
+	context := SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled}
+	if !sampled {
+		return &noopSpan{context: context}
+	}
+	return &span{
+		tracer: tracer,
+		data: SpanData{
+			Context:      context,
+			ParentSpanID: parentSpanID,
+			Operation:    operation,
+			Service:      tracer.service,
+			Start:        time.Now(),
+		},
+	}
+}
+
+// span is a recorded span.
+type span struct {
+	tracer *tracer
+
+	mu       sync.Mutex
+	data     SpanData
+	finished bool
+}
+
+// Context returns the identification of the span.
+func (span *span) Context() SpanContext {
+	return span.data.Context
+}
+
+// SetTag attaches a key-value annotation to the span.
+func (span *span) SetTag(key string, value interface{}) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	if span.finished {
+		return
+	}
+	if span.data.Tags == nil {
+		span.data.Tags = map[string]interface{}{}
+	}
+	span.data.Tags[key] = value
+}
+
+// SetError marks the span as failed and records the error message.
+func (span *span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	span.SetTag("error", err.Error())
+}
+
+// Finish completes the span and hands it over to the exporter.
+func (span *span) Finish() {
+	span.mu.Lock()
+	if span.finished {
+		span.mu.Unlock()
+		return
+	}
+	span.finished = true
+	span.data.Duration = time.Since(span.data.Start)
+	data := span.data
+	span.mu.Unlock()
+
+	span.tracer.exporter.ExportSpan(&data)
+}
+
+// noopSpan is returned for the unsampled traces.
+type noopSpan struct {
+	context SpanContext
+}
+
+// Context returns the identification of the span.
+func (span *noopSpan) Context() SpanContext {
+	return span.context
+}
+
+// SetTag is a no-op.
+func (span *noopSpan) SetTag(key string, value interface{}) {}
+
+// SetError is a no-op.
+func (span *noopSpan) SetError(err error) {}
+
+// Finish is a no-op.
+func (span *noopSpan) Finish() {}
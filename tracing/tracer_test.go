@@ -0,0 +1,164 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+// recordingExporter keeps the exported spans for the assertions.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func (exporter *recordingExporter) ExportSpan(span *SpanData) {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = append(exporter.spans, span)
+}
+
+func (exporter *recordingExporter) Close() error {
+	return nil
+}
+
+func (exporter *recordingExporter) exported() []*SpanData {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	spans := make([]*SpanData, len(exporter.spans))
+	copy(spans, exporter.spans)
+	return spans
+}
+
+func TestSpanParentage(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	exporter := &recordingExporter{}
+	tracer := NewTracer("testService", exporter, 1)
+
+	root := tracer.StartSpan("parent", nil)
+	rootContext := root.Context()
+	child := tracer.StartSpan("child", &rootContext)
+	child.SetTag("key", "/abc")
+	child.SetError(errors.New("failure"))
+	child.Finish()
+	root.Finish()
+
+	spans := exporter.exported()
+	gomega.Expect(spans).To(gomega.HaveLen(2))
+	gomega.Expect(spans[0].Operation).To(gomega.Equal("child"))
+	gomega.Expect(spans[0].Context.TraceID).To(gomega.Equal(rootContext.TraceID))
+	gomega.Expect(spans[0].ParentSpanID).To(gomega.Equal(rootContext.SpanID))
+	gomega.Expect(spans[0].Service).To(gomega.Equal("testService"))
+	gomega.Expect(spans[0].Tags).To(gomega.HaveKeyWithValue("key", "/abc"))
+	gomega.Expect(spans[0].Tags).To(gomega.HaveKeyWithValue("error", "failure"))
+	gomega.Expect(spans[1].Operation).To(gomega.Equal("parent"))
+	gomega.Expect(spans[1].ParentSpanID).To(gomega.BeEquivalentTo(0))
+}
+
+func TestSampling(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	exporter := &recordingExporter{}
+	tracer := NewTracer("testService", exporter, 0)
+
+	// nothing is recorded with the zero sample rate
+	root := tracer.StartSpan("unsampled", nil)
+	root.Finish()
+	gomega.Expect(exporter.exported()).To(gomega.BeEmpty())
+
+	// the children inherit the sampling decision of the trace
+	rootContext := root.Context()
+	child := tracer.StartSpan("child", &rootContext)
+	child.Finish()
+	gomega.Expect(exporter.exported()).To(gomega.BeEmpty())
+
+	sampledParent := &SpanContext{TraceID: 1, SpanID: 2, Sampled: true}
+	span := tracer.StartSpan("sampled", sampledParent)
+	span.Finish()
+	gomega.Expect(exporter.exported()).To(gomega.HaveLen(1))
+}
+
+func TestHTTPHeaderPropagation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	header := http.Header{}
+	spanContext := SpanContext{TraceID: 0xabcd, SpanID: 0x1234, Sampled: true}
+	InjectHTTPHeaders(header, spanContext)
+
+	extracted, found := ExtractHTTPHeaders(header)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(extracted).To(gomega.Equal(spanContext))
+
+	_, found = ExtractHTTPHeaders(http.Header{})
+	gomega.Expect(found).To(gomega.BeFalse())
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	exporter := &recordingExporter{}
+	tracer := NewTracer("testService", exporter, 1)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the handler continues the trace of the request
+		span, found := SpanFromContext(req.Context())
+		gomega.Expect(found).To(gomega.BeTrue())
+		gomega.Expect(span.Context().TraceID).To(gomega.BeEquivalentTo(0xabcd))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(HTTPMiddleware(tracer)(handler))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/status", nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	InjectHTTPHeaders(req.Header, SpanContext{TraceID: 0xabcd, SpanID: 0x1234, Sampled: true})
+	resp, err := http.DefaultClient.Do(req)
+	gomega.Expect(err).To(gomega.BeNil())
+	resp.Body.Close()
+
+	spans := exporter.exported()
+	gomega.Expect(spans).To(gomega.HaveLen(1))
+	gomega.Expect(spans[0].Context.TraceID).To(gomega.BeEquivalentTo(0xabcd))
+	gomega.Expect(spans[0].ParentSpanID).To(gomega.BeEquivalentTo(0x1234))
+	gomega.Expect(spans[0].Tags).To(gomega.HaveKeyWithValue("http.url", "/status"))
+}
+
+func TestPluginDisabled(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPluginUsingConfig(nil, &Config{Enabled: false})
+	gomega.Expect(plugin.Init()).To(gomega.BeNil())
+	defer plugin.Close()
+
+	// the tracer is usable, it just records nothing
+	span := plugin.Tracer().StartSpan("operation", nil)
+	span.Finish()
+	gomega.Expect(span.Context().Sampled).To(gomega.BeFalse())
+}
+
+func TestPluginUnknownExporter(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plugin := NewPluginUsingConfig(nil, &Config{Enabled: true, Exporter: "zipkin"})
+	gomega.Expect(plugin.Init()).NotTo(gomega.BeNil())
+}
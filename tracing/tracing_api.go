@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import "time"
+
+// SpanContext identifies a span within a trace. It is the part of the
+// span that crosses the process boundaries.
+type SpanContext struct {
+	// TraceID is shared by all spans of one trace.
+	TraceID uint64
+	// SpanID identifies the span within the trace.
+	SpanID uint64
+	// Sampled is the recording decision made for the whole trace by
+	// the tracer that started it.
+	Sampled bool
+}
+
+// Span is one traced operation. The span is finished exactly once; the
+// calls after Finish are ignored.
+type Span interface {
+	// Context returns the identification of the span.
+	Context() SpanContext
+	// SetTag attaches a key-value annotation to the span.
+	SetTag(key string, value interface{})
+	// SetError marks the span as failed and records the error message.
+	SetError(err error)
+	// Finish completes the span and hands it over to the exporter.
+	Finish()
+}
+
+// Tracer creates the spans of one service.
+type Tracer interface {
+	// StartSpan creates a span of the operation. A non-nil parent links
+	// the span into an already running trace.
+	StartSpan(operation string, parent *SpanContext) Span
+}
+
+// SpanData is a finished span handed over to the exporter.
+type SpanData struct {
+	// Context identifies the span.
+	Context SpanContext
+	// ParentSpanID is zero for a root span.
+	ParentSpanID uint64
+	// Operation names the traced operation.
+	Operation string
+	// Service names the traced process.
+	Service string
+	// Start is the time the span was started.
+	Start time.Time
+	// Duration is the time the operation took.
+	Duration time.Duration
+	// Tags are the annotations attached to the span.
+	Tags map[string]interface{}
+}
+
+// Exporter ships the finished spans to a tracing backend.
+type Exporter interface {
+	// ExportSpan hands over one finished span. It must not block.
+	ExportSpan(span *SpanData)
+	// Close flushes the buffered spans and releases the resources.
+	Close() error
+}
+
+// ExporterFactory creates the exporter described by the configuration.
+// The embedding application is supposed to assign a factory backed by a
+// concrete tracing backend client (Jaeger, OTLP) before the plugin is
+// initialized; the built-in "log" exporter is available without it.
+var ExporterFactory func(config *Config) (Exporter, error)
@@ -0,0 +1,158 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addrs
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// ParseIP parses the given string as an IPv4 or IPv6 address.
+func ParseIP(addr string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", addr)
+	}
+	return ip, nil
+}
+
+// IsIPv6 returns true when the given address is an IPv6 address.
+func IsIPv6(ip net.IP) bool {
+	return ip.To4() == nil && ip.To16() != nil
+}
+
+// ParseIPWithPrefix parses the given string as an IP prefix in the CIDR
+// notation. A plain address without the prefix length is accepted as
+// well; the host prefix (/32 respectively /128) is assumed in that case.
+func ParseIPWithPrefix(addr string) (*net.IPNet, error) {
+	addr = strings.TrimSpace(addr)
+	if strings.Contains(addr, "/") {
+		ip, network, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, err
+		}
+		network.IP = ip
+		return network, nil
+	}
+	ip, err := ParseIP(addr)
+	if err != nil {
+		return nil, err
+	}
+	bits := net.IPv4len * 8
+	if IsIPv6(ip) {
+		bits = net.IPv6len * 8
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// StrAddrsToStruct parses a list of addresses in the CIDR notation.
+func StrAddrsToStruct(addrs []string) ([]*net.IPNet, error) {
+	var parsed []*net.IPNet
+	for _, addr := range addrs {
+		if strings.TrimSpace(addr) == "" {
+			continue
+		}
+		network, err := ParseIPWithPrefix(addr)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, network)
+	}
+	return parsed, nil
+}
+
+// ParseMAC parses the given string as a MAC address and normalizes it
+// to the lowercase colon-separated form.
+func ParseMAC(addr string) (net.HardwareAddr, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(addr))
+	if err != nil {
+		return nil, err
+	}
+	return hw, nil
+}
+
+// FormatMAC returns the canonical (lowercase, colon-separated) form of
+// the MAC address.
+func FormatMAC(hw net.HardwareAddr) string {
+	return strings.Replace(strings.ToLower(hw.String()), "-", ":", -1)
+}
+
+// SortIPs sorts the addresses in place: IPv4 before IPv6, within one
+// family by the byte value.
+func SortIPs(ips []net.IP) {
+	sort.Slice(ips, func(i, j int) bool {
+		return compareIPs(ips[i], ips[j]) < 0
+	})
+}
+
+// DedupIPs returns the addresses without duplicates, in the order of
+// the first occurrence.
+func DedupIPs(ips []net.IP) []net.IP {
+	var deduped []net.IP
+	seen := map[string]struct{}{}
+	for _, ip := range ips {
+		key := ip.String()
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, ip)
+	}
+	return deduped
+}
+
+// SortIPNets sorts the prefixes in place: IPv4 before IPv6, within one
+// family by the network address and the prefix length.
+func SortIPNets(networks []*net.IPNet) {
+	sort.Slice(networks, func(i, j int) bool {
+		if cmp := compareIPs(networks[i].IP, networks[j].IP); cmp != 0 {
+			return cmp < 0
+		}
+		onesI, _ := networks[i].Mask.Size()
+		onesJ, _ := networks[j].Mask.Size()
+		return onesI < onesJ
+	})
+}
+
+// DedupIPNets returns the prefixes without duplicates, in the order of
+// the first occurrence.
+func DedupIPNets(networks []*net.IPNet) []*net.IPNet {
+	var deduped []*net.IPNet
+	seen := map[string]struct{}{}
+	for _, network := range networks {
+		key := network.String()
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, network)
+	}
+	return deduped
+}
+
+// compareIPs orders two addresses: IPv4 before IPv6, within one family
+// by the byte value.
+func compareIPs(a, b net.IP) int {
+	if v6a, v6b := IsIPv6(a), IsIPv6(b); v6a != v6b {
+		if v6b {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.To16(), b.To16())
+}
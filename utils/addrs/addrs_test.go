@@ -0,0 +1,106 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package addrs
+
+import (
+	"net"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParseIPWithPrefix(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	network, err := ParseIPWithPrefix("192.168.1.2/24")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(network.IP.String()).To(gomega.Equal("192.168.1.2"))
+	ones, _ := network.Mask.Size()
+	gomega.Expect(ones).To(gomega.Equal(24))
+
+	// plain address gets the host prefix
+	network, err = ParseIPWithPrefix("10.0.0.1")
+	gomega.Expect(err).To(gomega.BeNil())
+	ones, _ = network.Mask.Size()
+	gomega.Expect(ones).To(gomega.Equal(32))
+
+	network, err = ParseIPWithPrefix("2001:db8::1")
+	gomega.Expect(err).To(gomega.BeNil())
+	ones, _ = network.Mask.Size()
+	gomega.Expect(ones).To(gomega.Equal(128))
+	gomega.Expect(IsIPv6(network.IP)).To(gomega.BeTrue())
+
+	_, err = ParseIPWithPrefix("not-an-address")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+func TestStrAddrsToStruct(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	networks, err := StrAddrsToStruct([]string{"10.0.0.1/8", "", "2001:db8::1/64"})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(networks).To(gomega.HaveLen(2))
+
+	_, err = StrAddrsToStruct([]string{"10.0.0.1/8", "bad"})
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+func TestMAC(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	hw, err := ParseMAC("AA-BB-CC-DD-EE-01")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(FormatMAC(hw)).To(gomega.Equal("aa:bb:cc:dd:ee:01"))
+
+	_, err = ParseMAC("aa:bb")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}
+
+func TestSortAndDedupIPs(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	ips := []net.IP{
+		net.ParseIP("2001:db8::1"),
+		net.ParseIP("10.0.0.2"),
+		net.ParseIP("10.0.0.1"),
+		net.ParseIP("10.0.0.2"),
+	}
+	ips = DedupIPs(ips)
+	gomega.Expect(ips).To(gomega.HaveLen(3))
+
+	SortIPs(ips)
+	gomega.Expect(ips[0].String()).To(gomega.Equal("10.0.0.1"))
+	gomega.Expect(ips[1].String()).To(gomega.Equal("10.0.0.2"))
+	gomega.Expect(ips[2].String()).To(gomega.Equal("2001:db8::1"))
+}
+
+func TestSortAndDedupIPNets(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	networks, err := StrAddrsToStruct([]string{
+		"10.0.0.0/24", "10.0.0.0/8", "10.0.0.0/24", "2001:db8::/64",
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+
+	networks = DedupIPNets(networks)
+	gomega.Expect(networks).To(gomega.HaveLen(3))
+
+	SortIPNets(networks)
+	ones, _ := networks[0].Mask.Size()
+	gomega.Expect(ones).To(gomega.Equal(8))
+	ones, _ = networks[1].Mask.Size()
+	gomega.Expect(ones).To(gomega.Equal(24))
+	gomega.Expect(IsIPv6(networks[2].IP)).To(gomega.BeTrue())
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import "time"
+
+// Clock is the time source of a time-dependent subsystem. Production
+// code uses Real(); tests inject NewMock() and advance it explicitly.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that delivers the time after the
+	// duration elapses.
+	After(duration time.Duration) <-chan time.Time
+	// NewTicker returns a ticker delivering the time periodically.
+	NewTicker(period time.Duration) Ticker
+}
+
+// Ticker delivers ticks at a period; see time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on.
+	C() <-chan time.Time
+	// Stop turns the ticker off.
+	Stop()
+}
+
+// Real returns the clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+func (realClock) NewTicker(period time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(period)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestRealClock(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	real := Real()
+	before := time.Now()
+	gomega.Expect(real.Now()).To(gomega.BeTemporally(">=", before))
+
+	gomega.Eventually(real.After(time.Millisecond), time.Second).Should(gomega.Receive())
+
+	ticker := real.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	gomega.Eventually(ticker.C(), time.Second).Should(gomega.Receive())
+}
+
+func TestMockAfter(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	start := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := NewMock(start)
+	gomega.Expect(mock.Now()).To(gomega.Equal(start))
+
+	timer := mock.After(time.Minute)
+
+	mock.Advance(59 * time.Second)
+	gomega.Expect(timer).NotTo(gomega.Receive())
+
+	mock.Advance(time.Second)
+	var fired time.Time
+	gomega.Expect(timer).To(gomega.Receive(&fired))
+	gomega.Expect(fired).To(gomega.Equal(start.Add(time.Minute)))
+	gomega.Expect(mock.Now()).To(gomega.Equal(start.Add(time.Minute)))
+
+	// the timer does not fire twice
+	mock.Advance(time.Hour)
+	gomega.Expect(timer).NotTo(gomega.Receive())
+}
+
+func TestMockTicker(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	start := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	mock := NewMock(start)
+
+	ticker := mock.NewTicker(time.Second)
+
+	mock.Advance(time.Second)
+	gomega.Expect(ticker.C()).To(gomega.Receive())
+
+	// a tick per period; an unread tick is dropped like with time.Ticker
+	mock.Advance(3 * time.Second)
+	gomega.Expect(ticker.C()).To(gomega.Receive())
+
+	ticker.Stop()
+	mock.Advance(10 * time.Second)
+	gomega.Expect(ticker.C()).NotTo(gomega.Receive())
+}
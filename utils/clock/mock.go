@@ -0,0 +1,126 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Mock is a controllable Clock for tests. Time does not pass on its
+// own; the test advances it explicitly with Advance, which fires the
+// timers and tickers that became due.
+type Mock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*mockWaiter
+}
+
+// mockWaiter is a pending timer (period == 0) or ticker.
+type mockWaiter struct {
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewMock creates a mock clock set to the given time.
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+// Now returns the current mock time.
+func (mock *Mock) Now() time.Time {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return mock.now
+}
+
+// After returns a channel that delivers the mock time once it was
+// advanced past the duration.
+func (mock *Mock) After(duration time.Duration) <-chan time.Time {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	waiter := &mockWaiter{deadline: mock.now.Add(duration), ch: make(chan time.Time, 1)}
+	mock.waiters = append(mock.waiters, waiter)
+	return waiter.ch
+}
+
+// NewTicker returns a ticker driven by Advance.
+func (mock *Mock) NewTicker(period time.Duration) Ticker {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	waiter := &mockWaiter{deadline: mock.now.Add(period), period: period, ch: make(chan time.Time, 1)}
+	mock.waiters = append(mock.waiters, waiter)
+	return &mockTicker{mock: mock, waiter: waiter}
+}
+
+// Advance moves the mock time forward and fires the timers and tickers
+// that became due, in the order of their deadlines.
+func (mock *Mock) Advance(duration time.Duration) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	target := mock.now.Add(duration)
+	for {
+		next := mock.nextDue(target)
+		if next == nil {
+			break
+		}
+		mock.now = next.deadline
+		select {
+		case next.ch <- next.deadline:
+		default:
+		}
+		if next.period > 0 {
+			next.deadline = next.deadline.Add(next.period)
+		} else {
+			next.stopped = true
+		}
+	}
+	mock.now = target
+}
+
+// nextDue returns the unstopped waiter with the earliest deadline not
+// after the target, or nil. The caller must hold the mutex.
+func (mock *Mock) nextDue(target time.Time) *mockWaiter {
+	var due *mockWaiter
+	for _, waiter := range mock.waiters {
+		if waiter.stopped || waiter.deadline.After(target) {
+			continue
+		}
+		if due == nil || waiter.deadline.Before(due.deadline) {
+			due = waiter
+		}
+	}
+	return due
+}
+
+type mockTicker struct {
+	mock   *Mock
+	waiter *mockWaiter
+}
+
+func (t *mockTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t *mockTicker) Stop() {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+	t.waiter.stopped = true
+}
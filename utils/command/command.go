@@ -0,0 +1,117 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+	"golang.org/x/net/context"
+)
+
+// ExitStatusError is returned when the command terminates with
+// a non-zero exit status.
+type ExitStatusError struct {
+	// Command is the executed command with its arguments.
+	Command string
+	// ExitCode reported by the command.
+	ExitCode int
+	// Output is the combined stdout and stderr of the command.
+	Output string
+}
+
+// Error implements the error interface.
+func (err *ExitStatusError) Error() string {
+	return fmt.Sprintf("command %q exited with status %d", err.Command, err.ExitCode)
+}
+
+// TimeoutError is returned when the command is killed because its
+// context expired.
+type TimeoutError struct {
+	// Command is the executed command with its arguments.
+	Command string
+	// Output is the combined output produced before the kill.
+	Output string
+}
+
+// Error implements the error interface.
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("command %q was killed after its context expired", err.Command)
+}
+
+// ExitCode extracts the exit status from an error returned by Run. It
+// returns false when the error does not carry one (e.g. the command was
+// not found or timed out).
+func ExitCode(err error) (int, bool) {
+	if exitErr, ok := err.(*ExitStatusError); ok {
+		return exitErr.ExitCode, true
+	}
+	return 0, false
+}
+
+// Run executes the command and returns its combined stdout and stderr.
+// The command is killed when the context expires. The execution is
+// logged with structured fields; the output of a failed command is
+// logged line by line.
+func Run(ctx context.Context, log logging.Logger, name string, args ...string) (string, error) {
+	cmdLine := strings.Join(append([]string{name}, args...), " ")
+	start := time.Now()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	outputBytes, err := cmd.CombinedOutput()
+	output := string(outputBytes)
+
+	fields := map[string]interface{}{
+		"command":  cmdLine,
+		"duration": time.Since(start),
+	}
+	if err == nil {
+		log.WithFields(fields).Debug("Command finished")
+		return output, nil
+	}
+
+	if ctx.Err() != nil {
+		log.WithFields(fields).Warn("Command was killed on context expiration")
+		return output, &TimeoutError{Command: cmdLine, Output: output}
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode := -1
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			exitCode = status.ExitStatus()
+		}
+		fields["exit-code"] = exitCode
+		entry := log.WithFields(fields)
+		entry.Warn("Command failed")
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			if line != "" {
+				entry.Warn(line)
+			}
+		}
+		return output, &ExitStatusError{Command: cmdLine, ExitCode: exitCode, Output: output}
+	}
+	log.WithFields(fields).Warn("Command could not be executed: ", err)
+	return output, err
+}
+
+// RunWithTimeout executes the command with the given timeout.
+func RunWithTimeout(log logging.Logger, timeout time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return Run(ctx, log, name, args...)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestRunSuccess(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	output, err := Run(context.Background(), logrus.StandardLogger(), "sh", "-c", "echo hello")
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(output).To(gomega.Equal("hello\n"))
+}
+
+func TestRunExitStatus(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	output, err := Run(context.Background(), logrus.StandardLogger(),
+		"sh", "-c", "echo broken >&2; exit 3")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(output).To(gomega.Equal("broken\n"))
+
+	code, ok := ExitCode(err)
+	gomega.Expect(ok).To(gomega.BeTrue())
+	gomega.Expect(code).To(gomega.Equal(3))
+
+	exitErr, ok := err.(*ExitStatusError)
+	gomega.Expect(ok).To(gomega.BeTrue())
+	gomega.Expect(exitErr.Output).To(gomega.Equal("broken\n"))
+}
+
+func TestRunTimeout(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	start := time.Now()
+	_, err := RunWithTimeout(logrus.StandardLogger(), 100*time.Millisecond, "sleep", "60")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(time.Since(start)).To(gomega.BeNumerically("<", 5*time.Second))
+
+	_, ok := err.(*TimeoutError)
+	gomega.Expect(ok).To(gomega.BeTrue())
+	_, hasCode := ExitCode(err)
+	gomega.Expect(hasCode).To(gomega.BeFalse())
+}
+
+func TestRunNotFound(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	_, err := Run(context.Background(), logrus.StandardLogger(), "/nonexistent/binary")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	_, hasCode := ExitCode(err)
+	gomega.Expect(hasCode).To(gomega.BeFalse())
+}
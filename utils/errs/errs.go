@@ -0,0 +1,116 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+import "fmt"
+
+// Code categorizes an error.
+type Code string
+
+// The error categories used across the framework.
+const (
+	// CodeUnknown is reported for errors that carry no code.
+	CodeUnknown Code = "Unknown"
+	// CodeNotFound - the requested item does not exist.
+	CodeNotFound Code = "NotFound"
+	// CodeConflict - the operation collides with the current state
+	// (e.g. the item already exists or was modified concurrently).
+	CodeConflict Code = "Conflict"
+	// CodeUnavailable - a required backend (KV store, broker, peer)
+	// cannot be reached; the operation may succeed when retried.
+	CodeUnavailable Code = "Unavailable"
+	// CodeInvalidConfig - the supplied configuration is not valid.
+	CodeInvalidConfig Code = "InvalidConfig"
+)
+
+// Error is an error carrying a category code and optionally the causing
+// error.
+type Error struct {
+	code  Code
+	msg   string
+	cause error
+}
+
+// Error implements the error interface.
+func (err *Error) Error() string {
+	if err.cause != nil {
+		return err.msg + ": " + err.cause.Error()
+	}
+	return err.msg
+}
+
+// Code returns the category of the error.
+func (err *Error) Code() Code {
+	return err.code
+}
+
+// Cause returns the wrapped error, or nil.
+func (err *Error) Cause() error {
+	return err.cause
+}
+
+// New creates an error with the given code.
+func New(code Code, format string, args ...interface{}) error {
+	return &Error{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap annotates the causing error with a code and a message. It
+// returns nil when the cause is nil.
+func Wrap(cause error, code Code, format string, args ...interface{}) error {
+	if cause == nil {
+		return nil
+	}
+	return &Error{code: code, msg: fmt.Sprintf(format, args...), cause: cause}
+}
+
+// CodeOf returns the category of the error; CodeUnknown for nil errors
+// and errors that carry no code.
+func CodeOf(err error) Code {
+	if coded, ok := err.(*Error); ok {
+		return coded.code
+	}
+	return CodeUnknown
+}
+
+// Cause returns the innermost wrapped error.
+func Cause(err error) error {
+	for {
+		coded, ok := err.(*Error)
+		if !ok || coded.cause == nil {
+			return err
+		}
+		err = coded.cause
+	}
+}
+
+// IsNotFound returns true for errors with CodeNotFound.
+func IsNotFound(err error) bool {
+	return CodeOf(err) == CodeNotFound
+}
+
+// IsConflict returns true for errors with CodeConflict.
+func IsConflict(err error) bool {
+	return CodeOf(err) == CodeConflict
+}
+
+// IsUnavailable returns true for errors with CodeUnavailable.
+func IsUnavailable(err error) bool {
+	return CodeOf(err) == CodeUnavailable
+}
+
+// IsInvalidConfig returns true for errors with CodeInvalidConfig.
+func IsInvalidConfig(err error) bool {
+	return CodeOf(err) == CodeInvalidConfig
+}
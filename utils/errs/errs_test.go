@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestNewAndPredicates(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	err := New(CodeNotFound, "key %q does not exist", "/config/if0")
+	gomega.Expect(err.Error()).To(gomega.Equal(`key "/config/if0" does not exist`))
+	gomega.Expect(IsNotFound(err)).To(gomega.BeTrue())
+	gomega.Expect(IsConflict(err)).To(gomega.BeFalse())
+	gomega.Expect(CodeOf(err)).To(gomega.Equal(CodeNotFound))
+}
+
+func TestWrap(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	cause := errors.New("connection refused")
+	err := Wrap(cause, CodeUnavailable, "etcd endpoint is down")
+	gomega.Expect(err.Error()).To(gomega.Equal("etcd endpoint is down: connection refused"))
+	gomega.Expect(IsUnavailable(err)).To(gomega.BeTrue())
+	gomega.Expect(Cause(err)).To(gomega.Equal(cause))
+
+	// wrapping nil stays nil
+	gomega.Expect(Wrap(nil, CodeUnavailable, "no error")).To(gomega.BeNil())
+}
+
+func TestUncategorizedError(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	plain := errors.New("something failed")
+	gomega.Expect(CodeOf(plain)).To(gomega.Equal(CodeUnknown))
+	gomega.Expect(IsNotFound(plain)).To(gomega.BeFalse())
+	gomega.Expect(Cause(plain)).To(gomega.Equal(plain))
+	gomega.Expect(CodeOf(nil)).To(gomega.Equal(CodeUnknown))
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer coalesces bursts of triggers into a single execution of the
+// function: the function runs once the triggers have been quiet for the
+// configured delay. It is used e.g. to coalesce watch events before an
+// expensive resync.
+type Debouncer struct {
+	delay time.Duration
+	fn    func()
+
+	mu     sync.Mutex
+	timer  *time.Timer
+	closed bool
+}
+
+// NewDebouncer creates a debouncer that executes the function after the
+// given delay of quiet.
+func NewDebouncer(delay time.Duration, fn func()) *Debouncer {
+	return &Debouncer{delay: delay, fn: fn}
+}
+
+// Trigger schedules the execution of the function. A trigger arriving
+// before the delay elapsed restarts the timer, coalescing the burst
+// into one execution.
+func (debouncer *Debouncer) Trigger() {
+	debouncer.mu.Lock()
+	defer debouncer.mu.Unlock()
+
+	if debouncer.closed {
+		return
+	}
+	if debouncer.timer != nil {
+		debouncer.timer.Stop()
+	}
+	debouncer.timer = time.AfterFunc(debouncer.delay, debouncer.fn)
+}
+
+// Close cancels a pending execution and refuses further triggers.
+func (debouncer *Debouncer) Close() error {
+	debouncer.mu.Lock()
+	defer debouncer.mu.Unlock()
+
+	debouncer.closed = true
+	if debouncer.timer != nil {
+		debouncer.timer.Stop()
+		debouncer.timer = nil
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestTokenBucket(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	bucket := NewTokenBucket(1, 2)
+
+	allowed, _ := bucket.Allow()
+	gomega.Expect(allowed).To(gomega.BeTrue())
+	allowed, _ = bucket.Allow()
+	gomega.Expect(allowed).To(gomega.BeTrue())
+
+	allowed, wait := bucket.Allow()
+	gomega.Expect(allowed).To(gomega.BeFalse())
+	gomega.Expect(wait).To(gomega.BeNumerically(">", 0))
+}
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	bucket := NewTokenBucket(0.001, 1)
+	bucket.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	gomega.Expect(bucket.Wait(ctx)).NotTo(gomega.BeNil())
+}
+
+func TestDebouncer(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var runs int32
+	debouncer := NewDebouncer(50*time.Millisecond, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+	defer debouncer.Close()
+
+	// a burst of triggers coalesces into one execution
+	for i := 0; i < 10; i++ {
+		debouncer.Trigger()
+	}
+	gomega.Eventually(func() int32 {
+		return atomic.LoadInt32(&runs)
+	}, time.Second, 10*time.Millisecond).Should(gomega.BeEquivalentTo(1))
+	gomega.Consistently(func() int32 {
+		return atomic.LoadInt32(&runs)
+	}, 100*time.Millisecond, 10*time.Millisecond).Should(gomega.BeEquivalentTo(1))
+
+	// no execution after Close
+	debouncer.Close()
+	debouncer.Trigger()
+	gomega.Consistently(func() int32 {
+		return atomic.LoadInt32(&runs)
+	}, 100*time.Millisecond, 10*time.Millisecond).Should(gomega.BeEquivalentTo(1))
+}
+
+func TestSingleflight(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var group Singleflight
+	var executions, entered int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 5)
+	for i := 0; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			atomic.AddInt32(&entered, 1)
+			result, err, _ := group.Do("key", func() (interface{}, error) {
+				atomic.AddInt32(&executions, 1)
+				<-release
+				return "value", nil
+			})
+			gomega.Expect(err).To(gomega.BeNil())
+			results[i] = result
+		}(i)
+	}
+
+	gomega.Eventually(func() int32 {
+		return atomic.LoadInt32(&entered)
+	}, time.Second, time.Millisecond).Should(gomega.BeEquivalentTo(5))
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	gomega.Expect(atomic.LoadInt32(&executions)).To(gomega.BeEquivalentTo(1))
+	for _, result := range results {
+		gomega.Expect(result).To(gomega.Equal("value"))
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import "sync"
+
+// call is an in-flight or completed singleflight execution.
+type call struct {
+	wg     sync.WaitGroup
+	result interface{}
+	err    error
+	dups   int
+}
+
+// Singleflight deduplicates concurrent calls with the same key: only
+// the first caller executes the function, the others wait and receive
+// the same result. It prevents e.g. a thundering herd of identical
+// broker reads after a reconnect.
+type Singleflight struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// Do executes the function unless another call with the same key is
+// already in flight, in which case it waits for that call and returns
+// its result. The shared flag reports whether the result was shared
+// with other callers.
+func (group *Singleflight) Do(key string, fn func() (interface{}, error)) (result interface{}, err error, shared bool) {
+	group.mu.Lock()
+	if group.calls == nil {
+		group.calls = map[string]*call{}
+	}
+	if existing, found := group.calls[key]; found {
+		existing.dups++
+		group.mu.Unlock()
+		existing.wg.Wait()
+		return existing.result, existing.err, true
+	}
+	current := &call{}
+	current.wg.Add(1)
+	group.calls[key] = current
+	group.mu.Unlock()
+
+	current.result, current.err = fn()
+	current.wg.Done()
+
+	group.mu.Lock()
+	delete(group.calls, key)
+	shared = current.dups > 0
+	group.mu.Unlock()
+
+	return current.result, current.err, shared
+}
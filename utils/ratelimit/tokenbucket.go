@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// TokenBucket is a token-bucket rate limiter refilled at a fixed rate
+// up to the burst size. It is safe for concurrent use.
+type TokenBucket struct {
+	mu         sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewTokenBucket creates a full bucket refilled at the given rate of
+// tokens per second up to the burst size.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:       rate,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket. When the bucket is empty, it
+// returns false together with the duration after which a token becomes
+// available again.
+func (bucket *TokenBucket) Allow() (bool, time.Duration) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	now := time.Now()
+	bucket.tokens += now.Sub(bucket.lastRefill).Seconds() * bucket.rate
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+	bucket.lastRefill = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// Wait blocks until a token becomes available or the context expires.
+func (bucket *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		allowed, wait := bucket.Allow()
+		if allowed {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
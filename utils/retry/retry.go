@@ -0,0 +1,106 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Policy bounds a retry loop. The zero value of a field selects its
+// default.
+type Policy struct {
+	// InitialInterval is the delay before the first retry
+	// (default 100ms).
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff delay (default 10s).
+	MaxInterval time.Duration
+	// Multiplier scales the delay after every attempt (default 2).
+	Multiplier float64
+	// Jitter is the fraction of the delay randomized to desynchronize
+	// retrying clients, e.g. 0.1 for ±10% (default 0.1).
+	Jitter float64
+	// MaxAttempts bounds the total number of attempts
+	// (zero means unlimited).
+	MaxAttempts int
+	// MaxElapsed stops retrying once the time since the first attempt
+	// exceeds it (zero means unlimited).
+	MaxElapsed time.Duration
+}
+
+// DefaultPolicy is a policy suitable for reconnecting to an external
+// backend: backoff from 100ms up to 10s, unlimited attempts.
+func DefaultPolicy() Policy {
+	return Policy{}
+}
+
+// withDefaults resolves the zero fields of the policy.
+func (policy Policy) withDefaults() Policy {
+	if policy.InitialInterval == 0 {
+		policy.InitialInterval = 100 * time.Millisecond
+	}
+	if policy.MaxInterval == 0 {
+		policy.MaxInterval = 10 * time.Second
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = 2
+	}
+	if policy.Jitter == 0 {
+		policy.Jitter = 0.1
+	}
+	return policy
+}
+
+// Do executes fn until it succeeds, the policy gives up or the context
+// is cancelled. It returns nil on success; otherwise the error of the
+// last attempt, annotated with the reason the loop stopped.
+func Do(ctx context.Context, policy Policy, fn func() error) error {
+	policy = policy.withDefaults()
+	start := time.Now()
+	interval := policy.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %v", attempt, err)
+		}
+		if policy.MaxElapsed > 0 && time.Since(start) >= policy.MaxElapsed {
+			return fmt.Errorf("giving up after %v: %v", time.Since(start), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("retrying cancelled: %v (last error: %v)", ctx.Err(), err)
+		case <-time.After(jitter(interval, policy.Jitter)):
+		}
+
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+// jitter randomizes the delay by ±fraction.
+func jitter(interval time.Duration, fraction float64) time.Duration {
+	delta := (rand.Float64()*2 - 1) * fraction * float64(interval)
+	return time.Duration(float64(interval) + delta)
+}
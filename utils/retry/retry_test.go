@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+func TestSucceedsAfterRetries(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	attempts := 0
+	err := Do(context.Background(), Policy{InitialInterval: time.Millisecond}, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(attempts).To(gomega.Equal(3))
+}
+
+func TestMaxAttempts(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	attempts := 0
+	err := Do(context.Background(),
+		Policy{InitialInterval: time.Millisecond, MaxAttempts: 4},
+		func() error {
+			attempts++
+			return errors.New("always failing")
+		})
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(attempts).To(gomega.Equal(4))
+	gomega.Expect(err.Error()).To(gomega.ContainSubstring("giving up after 4 attempts"))
+}
+
+func TestMaxElapsed(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	start := time.Now()
+	err := Do(context.Background(),
+		Policy{InitialInterval: 10 * time.Millisecond, MaxElapsed: 50 * time.Millisecond},
+		func() error {
+			return errors.New("always failing")
+		})
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(time.Since(start)).To(gomega.BeNumerically("<", 5*time.Second))
+}
+
+func TestContextCancellation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := Do(ctx, Policy{InitialInterval: time.Hour}, func() error {
+		return errors.New("always failing")
+	})
+	gomega.Expect(err).NotTo(gomega.BeNil())
+	gomega.Expect(err.Error()).To(gomega.ContainSubstring("cancelled"))
+}
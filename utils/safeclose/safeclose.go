@@ -16,9 +16,10 @@ package safeclose
 
 import (
 	"errors"
-	"github.com/prometheus/common/log"
 	"io"
 	"reflect"
+
+	"github.com/ligato/cn-infra/logging/logrus"
 )
 
 // CloserWithoutErr is similar interface to GoLang Closer but Close() does not return error
@@ -26,91 +27,59 @@ type CloserWithoutErr interface {
 	Close()
 }
 
-// Close closes closable I/O stream.
-func Close(obj interface{}) error {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Error("Recovered in safeclose", r)
-		}
-	}()
-
-	if obj != nil {
-		if closer, ok := obj.(*io.Closer); ok {
-			if closer != nil {
-				err := (*closer).Close()
-				return err
-			}
-		} else if closer, ok := obj.(*CloserWithoutErr); ok {
-			if closer != nil {
-				(*closer).Close()
-			}
-		} else if closer, ok := obj.(io.Closer); ok {
-			if closer != nil {
-				log.Debug("closer: ", closer)
-				err := closer.Close()
-				return err
-			}
-		} else if closer, ok := obj.(CloserWithoutErr); ok {
-			if closer != nil {
-				closer.Close()
-			}
-		}
+// Close closes all of the items. It handles io.Closer, CloserWithoutErr,
+// pointers to them, channels and nil values, so that plugin Close()
+// implementations do not need a near-identical check for every resource.
+// Closing continues after a failure; all errors are aggregated into the
+// returned error.
+func Close(objs ...interface{}) error {
+	errs := make([]error, len(objs))
+	for i, obj := range objs {
+		errs[i] = closeObj(obj)
 	}
-	return nil
+	return format(errs)
 }
 
 // CloseAll tries to close all objects and return all errors (there are nils if there was no errors).
 func CloseAll(objs ...interface{}) (details []error, errOccured error) {
+	details = make([]error, len(objs))
+	for i, obj := range objs {
+		details[i] = closeObj(obj)
+	}
+	return details, format(details)
+}
+
+// closeObj closes one item, recovering from a panic (e.g. a channel
+// closed twice).
+func closeObj(obj interface{}) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Error("Recovered in safeclose", r)
+			logrus.StandardLogger().Error("Recovered in safeclose ", r)
 		}
 	}()
 
-	details = make([]error, len(objs))
-	for i, obj := range objs {
-		if obj != nil {
-			if closer, ok := obj.(*io.Closer); ok {
-				if closer != nil {
-					err := (*closer).Close()
-					if err != nil {
-						details[i] = err
-						errOccured = err
-
-					}
-				}
-			} else if closer, ok := obj.(*CloserWithoutErr); ok {
-				if closer != nil {
-					(*closer).Close()
-				}
-			} else if closer, ok := obj.(io.Closer); ok {
-				if closer != nil {
-					err := closer.Close()
-					if err != nil {
-						details[i] = err
-						errOccured = err
-
-					}
-				}
-			} else if closer, ok := obj.(CloserWithoutErr); ok {
-				if closer != nil {
-					closer.Close()
-				}
-			} else if reflect.TypeOf(obj).Kind() == reflect.Chan {
-				//reflect.ValueOf(nil).
-
-				if x, ok := obj.(chan interface{}); ok {
-					close(x)
-				}
-			}
-		}
+	if obj == nil {
+		return nil
 	}
-
-	if errOccured != nil {
-		return details, format(details)
+	switch closer := obj.(type) {
+	case *io.Closer:
+		if closer != nil {
+			return (*closer).Close()
+		}
+	case *CloserWithoutErr:
+		if closer != nil {
+			(*closer).Close()
+		}
+	case io.Closer:
+		return closer.Close()
+	case CloserWithoutErr:
+		closer.Close()
+	default:
+		if value := reflect.ValueOf(obj); value.Kind() == reflect.Chan {
+			value.Close()
+		}
 	}
-
-	return details, nil
+	return nil
 }
 
 // format squashes multiple errors into one.
@@ -119,7 +88,10 @@ func format(errs []error) error {
 
 	for _, err := range errs {
 		if err != nil {
-			errMsg += ";" + err.Error()
+			if len(errMsg) > 0 {
+				errMsg += "; "
+			}
+			errMsg += err.Error()
 		}
 	}
 
@@ -0,0 +1,105 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsreload
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ligato/cn-infra/logging"
+)
+
+// KeyPair serves the certificate of the configured files and reloads it
+// when the files are rotated. The check is a cheap stat performed on
+// every handshake; a half-written rotation (the new certificate does not
+// match the new key yet) keeps the previous pair in use and is retried
+// on the next handshake.
+type KeyPair struct {
+	log      logging.Logger
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// NewKeyPair loads the key pair from the files. Use GetCertificate or
+// GetClientCertificate in the tls.Config instead of the static
+// Certificates slice to pick up the rotations.
+func NewKeyPair(log logging.Logger, certFile string, keyFile string) (*KeyPair, error) {
+	pair := &KeyPair{log: log, certFile: certFile, keyFile: keyFile}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	pair.cert = &cert
+	pair.certMod = modTime(certFile)
+	pair.keyMod = modTime(keyFile)
+	return pair, nil
+}
+
+// GetCertificate serves the current key pair to a TLS server; assign it
+// to tls.Config.GetCertificate.
+func (pair *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return pair.current(), nil
+}
+
+// GetClientCertificate serves the current key pair to a TLS client;
+// assign it to tls.Config.GetClientCertificate.
+func (pair *KeyPair) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return pair.current(), nil
+}
+
+// current returns the loaded key pair, reloading it first when the files
+// have changed.
+func (pair *KeyPair) current() *tls.Certificate {
+	pair.mu.Lock()
+	defer pair.mu.Unlock()
+
+	certMod := modTime(pair.certFile)
+	keyMod := modTime(pair.keyFile)
+	if certMod.Equal(pair.certMod) && keyMod.Equal(pair.keyMod) {
+		return pair.cert
+	}
+
+	cert, err := tls.LoadX509KeyPair(pair.certFile, pair.keyFile)
+	if err != nil {
+		// keep serving the previous pair, the rotation may not be complete
+		pair.log.Warn("Failed to reload the rotated TLS key pair, keeping the previous one: ", err)
+		return pair.cert
+	}
+	pair.cert = &cert
+	pair.certMod = certMod
+	pair.keyMod = keyMod
+	pair.log.WithFields(logging.Fields{
+		"certFile": pair.certFile,
+		"keyFile":  pair.keyFile,
+	}).Info("Reloaded the rotated TLS key pair")
+	return pair.cert
+}
+
+// modTime returns the modification time of the file; the zero time when
+// the file cannot be examined.
+func modTime(fpath string) time.Time {
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsreload
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ligato/cn-infra/logging/logrus"
+	"github.com/onsi/gomega"
+)
+
+// selfSignedPair generates a self-signed certificate with the given
+// common name and returns its PEM-encoded certificate and key.
+func selfSignedPair(t *testing.T, commonName string) (certPEM []byte, keyPEM []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+// writePair writes the PEM-encoded pair to the files with the given
+// modification time, so the rotation is observable regardless of the
+// filesystem timestamp granularity.
+func writePair(t *testing.T, certFile, keyFile string, certPEM, keyPEM []byte, mod time.Time) {
+	for fpath, data := range map[string][]byte{certFile: certPEM, keyFile: keyPEM} {
+		if err := ioutil.WriteFile(fpath, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fpath, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// commonName extracts the common name of the leaf certificate.
+func commonName(t *testing.T, cert []byte) string {
+	leaf, err := x509.ParseCertificate(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+// TestKeyPairReload checks that the served certificate follows the
+// rotations of the files and that a half-written rotation keeps the
+// previous pair in use.
+func TestKeyPairReload(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dir, err := ioutil.TempDir("", "tlsreload")
+	gomega.Expect(err).To(gomega.BeNil())
+	defer os.RemoveAll(dir)
+	certFile := path.Join(dir, "cert.pem")
+	keyFile := path.Join(dir, "key.pem")
+
+	certA, keyA := selfSignedPair(t, "pair-a")
+	certB, keyB := selfSignedPair(t, "pair-b")
+
+	mod := time.Now().Add(-time.Minute)
+	writePair(t, certFile, keyFile, certA, keyA, mod)
+
+	pair, err := NewKeyPair(logrus.StandardLogger(), certFile, keyFile)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	served, err := pair.GetCertificate(nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commonName(t, served.Certificate[0])).To(gomega.BeEquivalentTo("pair-a"))
+
+	// rotate to the second pair
+	writePair(t, certFile, keyFile, certB, keyB, mod.Add(time.Second))
+	served, err = pair.GetClientCertificate(nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commonName(t, served.Certificate[0])).To(gomega.BeEquivalentTo("pair-b"))
+
+	// half-written rotation - the certificate does not match the key
+	writePair(t, certFile, keyFile, certA, keyB, mod.Add(2*time.Second))
+	served, err = pair.GetCertificate(nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commonName(t, served.Certificate[0])).To(gomega.BeEquivalentTo("pair-b"))
+
+	// the rotation completes, the next handshake picks it up
+	writePair(t, certFile, keyFile, certA, keyA, mod.Add(3*time.Second))
+	served, err = pair.GetCertificate(nil)
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commonName(t, served.Certificate[0])).To(gomega.BeEquivalentTo("pair-a"))
+}
+
+// TestKeyPairMissingFiles checks that the constructor fails when the
+// files do not exist.
+func TestKeyPairMissingFiles(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	_, err := NewKeyPair(logrus.StandardLogger(), "/does/not/exist.pem", "/does/not/exist.key")
+	gomega.Expect(err).NotTo(gomega.BeNil())
+}